@@ -0,0 +1,131 @@
+package main
+
+import (
+	"github.com/sqp/godock/libs/net/uptoshare"
+
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var cmdDecrypt = &Command{
+	UsageLine: "decrypt [-o file] link | decrypt [-o file] file key",
+	Short:     "decrypt files uploaded with encryption",
+	Long: `
+Decrypt opens a file uploaded with the NetActivity encryption option.
+
+The key is the part of the link after the #. It's never sent to the host, so
+the recipient needs the full link, or the downloaded .enc file and the key.
+The decrypted file is saved in the current directory, without the .enc
+extension.
+
+Flags:
+  -o file      Output file, or - for the standard output.
+
+Examples:
+  cdc decrypt 'https://host/abc/report.pdf.enc#key'
+  cdc decrypt report.pdf.enc key
+  cdc decrypt -o - 'https://host/abc/text.txt.enc#key'
+`,
+}
+
+func init() {
+	cmdDecrypt.Run = runDecrypt // break init cycle
+}
+
+var decryptOutput = cmdDecrypt.Flag.String("o", "", "")
+
+func runDecrypt(cmd *Command, args []string) {
+	var source, key string
+	switch len(args) {
+	case 1:
+		i := strings.LastIndex(args[0], "#")
+		if i < 0 {
+			cmd.Usage()
+		}
+		source, key = args[0][:i], args[0][i+1:]
+
+	case 2:
+		source, key = args[0], args[1]
+
+	default:
+		cmd.Usage()
+	}
+
+	r, e := openSource(source)
+	exitIfFail(e, "decrypt open")
+	defer r.Close()
+
+	if *decryptOutput == "-" {
+		exitIfFail(uptoshare.Decrypt(os.Stdout, r, key), "decrypt")
+		return
+	}
+
+	output := *decryptOutput
+	if output == "" {
+		output = strings.TrimSuffix(path.Base(sourcePath(source)), ".enc")
+	}
+	if _, e := os.Stat(output); e == nil {
+		exitIfFail(errors.New(output+" already exists"), "decrypt")
+	}
+
+	// Decrypt to a temp file, to never leave a partial or unauthenticated output.
+	tmp, e := ioutil.TempFile(filepath.Dir(output), ".decrypt")
+	exitIfFail(e, "decrypt create")
+	e = uptoshare.Decrypt(tmp, r, key)
+	if e == nil {
+		e = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if e == nil {
+		e = os.Rename(tmp.Name(), output)
+	}
+	if e != nil {
+		os.Remove(tmp.Name())
+	}
+	exitIfFail(e, "decrypt")
+	logger.Info("decrypted", output)
+}
+
+// sourcePath returns the path of the source file, without the link query.
+//
+func sourcePath(source string) string {
+	if !isLink(source) {
+		return source
+	}
+	u, e := url.Parse(source)
+	if e != nil {
+		return source
+	}
+	return u.Path
+}
+
+// isLink returns whether the source must be downloaded.
+//
+func isLink(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// openSource opens the encrypted file, downloaded if it's a link.
+//
+func openSource(source string) (io.ReadCloser, error) {
+	if !isLink(source) {
+		return os.Open(source)
+	}
+	resp, e := http.Get(source)
+	if e != nil {
+		return nil, e
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.New("download: " + resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -9,7 +9,9 @@ The commands are:
 
     appinfo     appinfo edits applets information
     build       build cairo-dock sources
+    decrypt     decrypt files uploaded with encryption
     external    external applets management
+    metrics     metrics exports system monitoring history
     remote      remote controls the active dock
     upload      upload files or text to one-click hosting services
     version     print cdc version
@@ -97,6 +99,28 @@ Options:
                    Default = all availables processors.
 
 
+Decrypt files uploaded with encryption
+
+Usage:
+
+	cdc decrypt [-o file] link | decrypt [-o file] file key
+
+Decrypt opens a file uploaded with the NetActivity encryption option.
+
+The key is the part of the link after the #. It's never sent to the host, so
+the recipient needs the full link, or the downloaded .enc file and the key.
+The decrypted file is saved in the current directory, without the .enc
+extension.
+
+Flags:
+  -o file      Output file, or - for the standard output.
+
+Examples:
+  cdc decrypt 'https://host/abc/report.pdf.enc#key'
+  cdc decrypt report.pdf.enc key
+  cdc decrypt -o - 'https://host/abc/text.txt.enc#key'
+
+
 External applets management
 
 Usage:
@@ -133,6 +157,27 @@ For the full list of fields, see AppletPackage:
   http://godoc.org/github.com/sqp/godock/libs/packages#AppletPackage
 
 
+Metrics exports system monitoring history
+
+Usage:
+
+	cdc metrics [-f format] [-d path] [-s duration] appletname
+
+Metrics exports the history recorded by system monitoring applets.
+
+The history must be enabled in the applet configuration (Metrics history size).
+Available for the Cpu, Mem, DiskActivity, NetActivity, Pressure and Sensors applets.
+
+Flags:
+  -f format    Output format: csv or json. Default: csv
+  -d path      Use a custom config directory. Default: ~/.config/cairo-dock
+  -s duration  Only export samples more recent than the duration (e.g. 2h30m).
+
+Examples:
+  cdc metrics Cpu > cpu.csv
+  cdc metrics -f json -s 1h NetActivity
+
+
 Remote controls the active dock
 
 Usage:
@@ -155,6 +200,13 @@ Remote sends a remote command to the active dock.
 
   ul  UpToShareLastLink
         Print the link of the last uploaded item.
+  uls UpToShareLinks [key=value...]
+        Print the uploaded items matching the query, newest first.
+          Keys: type (text, image, video or file), after and before (date
+          as 20060102), name (part of the file or link) and expired (false
+          to hide files removed by the host).
+  uld UpToShareDelete link
+        Delete an uploaded item from its hosting service.
 
 
 Upload files or text to one-click hosting services
@@ -47,6 +47,14 @@ Paths override:
 Web service:
   -host       Adress to listen for the web server (def=localhost).
   -port       Port for the web server (def=15610).
+  -iface name Network interface to listen on (overrides host). Services are
+              still refused to other hosts unless allowed in their config.
+  -tls        Use HTTPS. A self-signed certificate is created if missing:
+              websrv.crt and websrv.key in the config directory.
+
+Network:
+  -proxy url  Proxy for downloads (def=from environment: HTTP_PROXY...).
+              Use "direct" to ignore the environment settings.
 
 Debug:
   -w time     Wait for N seconds before starting; this is useful if you notice
@@ -101,7 +109,10 @@ everybody at the moment. But it also needs to be tested now.
 
 		newWebHost     = cmdDefault.Flag.String("host", websrv.DefaultHost, "")
 		newWebPort     = cmdDefault.Flag.Int("port", websrv.DefaultPort, "")
+		newWebIface    = cmdDefault.Flag.String("iface", "", "")
+		newWebTLS      = cmdDefault.Flag.Bool("tls", false, "")
 		newWebMonitor  = cmdDefault.Flag.Bool("mon", false, "")
+		newHTTPProxy   = cmdDefault.Flag.String("proxy", "", "")
 		newDisableDBus = cmdDefault.Flag.Bool("N", false, "")
 		newDebug       = cmdDefault.Flag.Bool("D", false, "")
 	)
@@ -143,11 +154,14 @@ everybody at the moment. But it also needs to be tested now.
 			NoSticky:           *userNoSticky,
 			ModulesDir:         *userModulesDir,
 
-			WebHost:     *newWebHost,
-			WebPort:     *newWebPort,
-			WebMonitor:  *newWebMonitor,
-			DisableDBus: *newDisableDBus,
-			Debug:       *newDebug,
+			WebHost:      *newWebHost,
+			WebPort:      *newWebPort,
+			WebInterface: *newWebIface,
+			WebTLS:       *newWebTLS,
+			WebMonitor:   *newWebMonitor,
+			HTTPProxy:    *newHTTPProxy,
+			DisableDBus:  *newDisableDBus,
+			Debug:        *newDebug,
 		}
 	}
 }
@@ -22,7 +22,9 @@ import (
 var Commands = CommandList{
 	cmdAppInfo,
 	cmdBuild,
+	cmdDecrypt,
 	cmdExternal,
+	cmdMetrics,
 	cmdRemote,
 	cmdUpload,
 	cmdVersion,
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"os"
+	"time"
+)
+
+var cmdMetrics = &Command{
+	UsageLine: "metrics [-f format] [-d path] [-s duration] appletname",
+	Short:     "metrics exports system monitoring history",
+	Long: `
+Metrics exports the history recorded by system monitoring applets.
+
+The history must be enabled in the applet configuration (Metrics history size).
+Available for the Cpu, Mem, DiskActivity, NetActivity, Pressure and Sensors applets.
+
+Flags:
+  -f format    Output format: csv or json. Default: csv
+  -d path      Use a custom config directory. Default: ~/.config/cairo-dock
+  -s duration  Only export samples more recent than the duration (e.g. 2h30m).
+
+Examples:
+  cdc metrics Cpu > cpu.csv
+  cdc metrics -f json -s 1h NetActivity
+`,
+}
+
+func init() {
+	cmdMetrics.Run = runMetrics // break init cycle
+}
+
+var (
+	metricsFormat   = cmdMetrics.Flag.String("f", "csv", "")
+	metricsConfPath = cmdMetrics.Flag.String("d", "", "")
+	metricsSince    = cmdMetrics.Flag.Duration("s", 0, "")
+)
+
+func runMetrics(cmd *Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+	}
+	setPathAbsolute(metricsConfPath) // Ensure we have an absolute path for the config dir.
+
+	f, e := os.Open(sysinfo.RecordFilePath(*metricsConfPath, args[0]))
+	exitIfFail(e, "open metrics history")
+	list, e := sysinfo.ReadSamples(f)
+	f.Close()
+	logger.Err(e, "read metrics history")
+
+	if *metricsSince > 0 {
+		limit := time.Now().Add(-*metricsSince)
+		for len(list) > 0 && list[0].Date.Before(limit) {
+			list = list[1:]
+		}
+	}
+
+	switch *metricsFormat {
+	case "csv":
+		e = sysinfo.WriteCSV(os.Stdout, list)
+
+	case "json":
+		e = sysinfo.WriteJSON(os.Stdout, list)
+
+	default:
+		cmd.Usage()
+	}
+	exitIfFail(e, "export metrics")
+}
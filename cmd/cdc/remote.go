@@ -28,6 +28,13 @@ Remote sends a remote command to the active dock.
 
   ul  UpToShareLastLink
         Print the link of the last uploaded item.
+  uls UpToShareLinks [key=value...]
+        Print the uploaded items matching the query, newest first.
+          Keys: type (text, image, video or file), after and before (date
+          as 20060102), name (part of the file or link) and expired (false
+          to hide files removed by the host).
+  uld UpToShareDelete link
+        Delete an uploaded item from its hosting service.
 `,
 }
 
@@ -73,6 +80,31 @@ func runRemote(cmd *Command, args []string) {
 			fmt.Println(link)
 		}
 
+	case "uls", "UpToShareLinks":
+		query := make(map[string]string)
+		for _, arg := range args[1:] {
+			kv := strings.SplitN(arg, "=", 2)
+			if len(kv) != 2 {
+				cmd.Usage()
+			}
+			query[kv[0]] = kv[1]
+		}
+		var list []map[string]string
+		list, e = srvdbus.UpToShareLinks(query)
+		for _, links := range list {
+			line := links["date"] + "  " + links["link"] + "  " + links["file"]
+			if links["expire"] != "" {
+				line += "  (expire " + links["expire"] + ")"
+			}
+			fmt.Println(line)
+		}
+
+	case "uld", "UpToShareDelete":
+		if len(args) < 2 {
+			cmd.Usage()
+		}
+		e = srvdbus.UpToShareDelete(args[1])
+
 	default:
 		logger.Errorf("unknown remote command", "%v", args)
 		cmd.Usage()
@@ -6,6 +6,7 @@ import (
 	"github.com/sqp/godock/libs/cdglobal"        // Dock types.
 	"github.com/sqp/godock/libs/cdtype"          // Applets types.
 	"github.com/sqp/godock/libs/config"          // Config parser.
+	"github.com/sqp/godock/libs/keyring"         // Secrets storage.
 	"github.com/sqp/godock/libs/log"             // Display info in terminal.
 	"github.com/sqp/godock/libs/poller"          // Polling counter.
 	"github.com/sqp/godock/libs/ternary"         // Ternary operators.
@@ -14,6 +15,7 @@ import (
 	"errors"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 //
@@ -33,6 +35,7 @@ type CDApplet struct {
 	events    cdtype.Events      // Applet events callbacks.
 	action    cdtype.AppAction   // Actions handler. Where an applet can declare its list of actions.
 	poller    cdtype.AppPoller   // Poller counter. If you want more than one, use a common denominator.
+	secrets   *keyring.Keyring   // Credentials storage.
 	command   cdtype.AppCommand  // Programs and locations configured by the user, including application monitor.
 	log       cdtype.Logger      // Applet logger.
 	shortkeys []*cdtype.Shortkey // Shortkeys and callbacks.
@@ -82,6 +85,7 @@ func (cda *CDApplet) SetBase(name, conf, rootdir, sharedir string) {
 	cda.confFile = conf
 	cda.rootDataDir = rootdir
 	cda.shareDataDir = sharedir
+	cda.secrets = keyring.New(name, filepath.Join(rootdir, cdglobal.DirUserAppData, keyring.FileName), cda.askPassphrase)
 }
 
 // SetBackend sets the applet backend and connects its OnEvent callback to the
@@ -192,6 +196,56 @@ func (cda *CDApplet) Poller() cdtype.AppPoller {
 	return cda.poller
 }
 
+//
+//-------------------------------------------------------------[ CREDENTIALS ]--
+
+// Credentials returns the applet secrets storage.
+//
+func (cda *CDApplet) Credentials() cdtype.AppCredentials {
+	return cda.secrets
+}
+
+// askPassphrase asks the user the passphrase of the secrets file, used when
+// the Secret Service isn't available. With confirm, a new passphrase is asked
+// again.
+//
+func (cda *CDApplet) askPassphrase(confirm bool) (string, error) {
+	msg := "No keyring service found, secrets are saved in an encrypted file.\n\nPlease enter the passphrase of the secrets file"
+	if confirm {
+		msg = "Please enter the new passphrase of the secrets file again"
+	}
+	answer := make(chan string, 1)
+	e := cda.PopupDialog(cdtype.DialogData{
+		Message: msg,
+		Buttons: "ok;cancel",
+		Widget:  cdtype.DialogWidgetText{Hidden: true},
+		Callback: func(clickedButton int, data interface{}) {
+			str, _ := data.(string)
+			if clickedButton != cdtype.DialogButtonFirst && clickedButton != cdtype.DialogKeyEnter {
+				str = ""
+			}
+			select {
+			case answer <- str:
+			default:
+			}
+		},
+	})
+	if e != nil {
+		return "", e
+	}
+
+	select {
+	case pass := <-answer:
+		if pass == "" {
+			return "", keyring.ErrNoPassphrase
+		}
+		return pass, nil
+
+	case <-time.After(keyring.PromptTimeout):
+		return "", keyring.ErrNoPassphrase
+	}
+}
+
 //
 //------------------------------------------------------------------[ ACTION ]--
 
@@ -156,6 +156,10 @@ type AppBase interface {
 	//
 	Poller() AppPoller
 
+	// Credentials returns the applet secrets storage.
+	//
+	Credentials() AppCredentials
+
 	// Log gives access to the applet logger.
 	//
 	Log() Logger
@@ -630,6 +634,34 @@ type AppPoller interface {
 	Plop() bool
 }
 
+//
+//-------------------------------------------------------------[ CREDENTIALS ]--
+
+// AppCredentials stores applet secrets, like passwords.
+//
+// Secrets are saved in the freedesktop Secret Service if available, or in a
+// file encrypted with a user passphrase. Methods can block to ask the user,
+// and must not be called from the events loop: use them in threaded actions
+// or polling checks.
+//
+type AppCredentials interface {
+	// Get returns the secret saved for the key. Empty if not found.
+	//
+	Get(key string) (string, error)
+
+	// Set saves the secret for the key, replacing the previous one.
+	//
+	Set(key, secret string) error
+
+	// Delete removes the secret saved for the key, if any.
+	//
+	Delete(key string) error
+
+	// Backend returns the name of the storage used.
+	//
+	Backend() string
+}
+
 //
 //----------------------------------------------------------[ APP MANAGEMENT ]--
 
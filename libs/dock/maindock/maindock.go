@@ -75,11 +75,14 @@ type DockSettings struct {
 
 	// --- New Dock settings ---
 	//
-	WebHost     string
-	WebPort     int
-	WebMonitor  bool
-	DisableDBus bool
-	Debug       bool
+	WebHost      string
+	WebPort      int
+	WebInterface string
+	WebTLS       bool
+	WebMonitor   bool
+	HTTPProxy    string
+	DisableDBus  bool
+	Debug        bool
 
 	// auto loaded from original dock hidden file.
 	isFirstLaunch  bool
@@ -25,6 +25,7 @@ import (
 	_ "github.com/sqp/godock/services/allapps"
 
 	// Other services.
+	"github.com/sqp/godock/libs/net/download"     // Web client.
 	"github.com/sqp/godock/libs/net/websrv"       // Web server.
 	"github.com/sqp/godock/libs/srvdbus"          // DBus own service.
 	"github.com/sqp/godock/libs/srvdbus/dockpath" // hack dock dbus path
@@ -97,6 +98,12 @@ func Run(log cdtype.Logger, getSettings func() maindock.DockSettings) bool {
 	websrv.Init(log)
 	websrv.Service.Host = settings.WebHost
 	websrv.Service.Port = settings.WebPort
+	websrv.Service.Interface = settings.WebInterface
+	websrv.Service.TLS = settings.WebTLS
+	websrv.Service.CertDir = cdglobal.ConfigDirDock(settings.UserDefinedDataDir)
+
+	// HTTP client used by the applets and services.
+	log.Err(download.Default.SetProxy(settings.HTTPProxy), "set HTTP proxy")
 
 	if settings.WebMonitor || confown.Current.OnStartWebMon {
 		websrv.Service.SetMonitored(true)
@@ -71,6 +71,13 @@ func IsExist(path string) bool {
 	return err == nil || os.IsExist(err)
 }
 
+// IsDir checks whether the path is a directory.
+//
+func IsDir(path string) bool {
+	fi, e := os.Stat(path)
+	return e == nil && fi.IsDir()
+}
+
 // Reader returns a Reader to the given file, with its size and close call.
 //
 func Reader(filePath string) (r io.Reader, size int64, close func() error, e error) {
@@ -165,3 +172,56 @@ func UnTarGz(topath string, source io.ReadCloser) error {
 	}
 	return nil
 }
+
+//
+//----------------------------------------------------------------[ COMPRESS ]--
+
+// TarGz writes a tar gz archive of the files and directories to the writer.
+// Each path is stored with its base name as root.
+//
+func TarGz(w io.Writer, paths ...string) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	for _, path := range paths {
+		root := filepath.Dir(filepath.Clean(path))
+		e := filepath.Walk(path, func(file string, fi os.FileInfo, e error) error {
+			if e != nil {
+				return e
+			}
+			if !fi.IsDir() && !fi.Mode().IsRegular() { // Links, devices...
+				return nil
+			}
+			header, e := tar.FileInfoHeader(fi, "")
+			if e != nil {
+				return e
+			}
+			header.Name, e = filepath.Rel(root, file)
+			if e != nil {
+				return e
+			}
+			if fi.IsDir() {
+				header.Name += "/"
+			}
+			e = tw.WriteHeader(header)
+			if e != nil || fi.IsDir() {
+				return e
+			}
+
+			f, e := os.Open(file)
+			if e != nil {
+				return e
+			}
+			defer f.Close()
+			_, e = io.Copy(tw, f)
+			return e
+		})
+		if e != nil {
+			return e
+		}
+	}
+	e := tw.Close()
+	if e != nil {
+		return e
+	}
+	return zw.Close()
+}
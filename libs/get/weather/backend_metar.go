@@ -0,0 +1,387 @@
+package weather
+
+import (
+	"github.com/sqp/godock/libs/net/download"
+	"github.com/sqp/godock/libs/text/tran"
+
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL for METAR data. Variable so it can be redirected to a mirror.
+var METARURLBase = "https://aviationweather.gov/api/data"
+
+// URLMETAR is the webpage to open for METAR stations.
+const URLMETAR = "https://aviationweather.gov/data/metar/?ids=%s&taf=1"
+
+// metarStationSearch is the half size in degrees of the box used to find
+// stations around a place.
+const metarStationSearch = 0.5
+
+// metarStation matches ICAO station codes.
+var metarStation = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+
+// Station defines a weather station reporting METAR.
+//
+type Station struct {
+	ICAO    string  `json:"icaoId"`
+	Site    string  `json:"site"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// metar downloads METAR observations and TAF forecasts of an airport station
+// and implements Weather.
+//
+// The location code is the ICAO code of the station. The forecast is limited
+// to the TAF validity (24 to 30 hours).
+//
+type metar struct {
+	*Config
+
+	current  *Current
+	forecast *Forecast
+
+	stations map[string]*Station // cache of station info.
+	mu       sync.Mutex
+}
+
+func (w *metar) Current() *Current      { return w.current }
+func (w *metar) Forecast() *Forecast    { return w.forecast }
+func (w *metar) SetConfig(conf *Config) { w.Config = conf }
+func (w *metar) Clear()                 { w.current = nil; w.forecast = nil }
+
+// WebpageURL returns the webpage url to open to the user.
+//
+func (w *metar) WebpageURL(numDay int) string {
+	return fmt.Sprintf(URLMETAR, w.LocationCode)
+}
+
+// ValidLocation returns whether the location code is an ICAO station code.
+//
+func (w *metar) ValidLocation(code string) bool {
+	return metarStation.MatchString(code)
+}
+
+//
+//----------------------------------------------------------------[ GET DATA ]--
+
+func (w *metar) Get() chan error {
+	chane := make(chan error, 1)
+	defer close(chane)
+
+	switch {
+	case w.Config == nil:
+		chane <- ErrMissingConfObject
+
+	case w.LocationCode == "":
+		chane <- ErrMissingLocationCode
+
+	default:
+		chane <- w.download()
+	}
+	return chane
+}
+
+func (w *metar) download() error {
+	code := strings.ToUpper(w.LocationCode)
+	station, e := w.station(code)
+	if e != nil {
+		return e
+	}
+
+	now := time.Now()
+	raw, e := download.Get(METARURLBase + "/metar?" + url.Values{"ids": {code}, "format": {"raw"}}.Encode())
+	if e != nil {
+		return e
+	}
+	met, e := ParseMETAR(firstLine(raw), now)
+	if e != nil {
+		return e
+	}
+
+	raw, e = download.Get(METARURLBase + "/taf?" + url.Values{"ids": {code}, "format": {"raw"}}.Encode())
+	if e != nil {
+		return e
+	}
+	taf, e := ParseTAF(string(raw), now)
+	if e != nil {
+		return e
+	}
+
+	// Received data is valid, update it.
+	w.current = w.parseCurrent(station, met, now)
+	w.forecast = w.parseForecast(station, taf, now)
+	return nil
+}
+
+func (w *metar) parseCurrent(station *Station, met *METAR, now time.Time) *Current {
+	distance, pressure, speed, temp := units(w.UseCelcius)
+	cur := &Current{
+		UnitDistance:  distance,
+		UnitPressure:  pressure,
+		UnitSpeed:     speed,
+		UnitTemp:      temp,
+		LocName:       w.locName(station),
+		Observatory:   station.Site,
+		Pressure:      ValueMissing,
+		Humidity:      ValueMissing,
+		WindSpeed:     ValueMissing,
+		WindDirection: ValueMissing,
+		Visibility:    ValueMissing,
+		MoonIcon:      MoonAge(now),
+		UVDescription: ValueMissing,
+	}
+	if met.HasTemp {
+		cur.TempReal = roundInt(celsius(float64(met.Temp), w.UseCelcius))
+		cur.TempFelt = roundInt(celsius(met.FeltTemp(), w.UseCelcius))
+		cur.Humidity = round(met.Humidity())
+	}
+	switch {
+	case met.Pressure > 0 && w.UseCelcius:
+		cur.Pressure = round(met.Pressure)
+	case met.Pressure > 0:
+		cur.Pressure = fmt.Sprintf("%.2f", met.Pressure/hPaPerInHg)
+	}
+	if met.HasWind {
+		cur.WindSpeed, cur.WindDirection = windText(met.Conditions, w.UseCelcius)
+	}
+	if met.Visibility > 0 {
+		cur.Visibility = visibility(met.Visibility, w.UseCelcius)
+	}
+
+	// Sun.
+	rise, set, ok, night := SunTimes(now, station.Lat, station.Lon)
+	cur.IsNight = night || (ok && (now.Before(rise) || now.After(set)))
+	cur.Sunrise, cur.Sunset, cur.TxtSunrise, cur.TxtSunset = sunText(rise, set, ok, w.Time24H)
+
+	cond := met.condition()
+	cur.WeatherDescription = tran.Splug(cond.text(cur.IsNight))
+	cur.WeatherIcon = cond.icon(cur.IsNight)
+
+	updated := met.Time.Local()
+	cur.UpdateTime = updated.Format("1/2/06 3:04 PM")
+	cur.TxtUpdateTime = updated.Format(timeFormat(w.Time24H))
+	return cur
+}
+
+func (w *metar) parseForecast(station *Station, taf *TAF, now time.Time) *Forecast {
+	distance, pressure, speed, temp := units(w.UseCelcius)
+	fc := &Forecast{
+		UnitDistance: distance,
+		UnitPressure: pressure,
+		UnitSpeed:    speed,
+		UnitTemp:     temp,
+		LocName:      w.locName(station),
+		Lat:          strconv.FormatFloat(station.Lat, 'f', 4, 64),
+		Lon:          strconv.FormatFloat(station.Lon, 'f', 4, 64),
+		UpdateTime:   taf.Issued.Local().Format("1/2/06 3:04 PM"),
+	}
+
+	tempText := func(temps map[time.Time]int, date time.Time) string {
+		value, ok := temps[time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)]
+		if !ok {
+			return ValueMissing
+		}
+		return round(celsius(float64(value), w.UseCelcius))
+	}
+
+	// Parts are sampled at 13:00 for the day and 01:00 for the night.
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for i := 0; i <= w.NbDays; i++ {
+		date := today.AddDate(0, 0, i)
+		rise, set, ok, _ := SunTimes(date, station.Lat, station.Lon)
+		day := Day{
+			Date:     date.Format("Jan 2"),
+			DayName:  tran.Splug(date.Weekday().String()),
+			DayCount: strconv.Itoa(i),
+			MonthDay: date.Day(),
+			TempMax:  tempText(taf.TempMax, date),
+			TempMin:  tempText(taf.TempMin, date),
+		}
+		day.Sunrise, day.Sunset, day.TxtSunrise, day.TxtSunset = sunText(rise, set, ok, w.Time24H)
+
+		for _, night := range []bool{false, true} {
+			from, to := date.Add(6*time.Hour), date.Add(18*time.Hour)
+			sample := date.Add(13 * time.Hour)
+			if night {
+				from, to = to, date.Add(30*time.Hour)
+				sample = date.Add(25 * time.Hour)
+			}
+			if sample.Before(now) && to.After(now) {
+				sample = now
+			}
+			if to.Before(now) || !taf.Covers(sample) {
+				continue
+			}
+
+			cond := taf.Prevailing(sample)
+			condi := cond.condition()
+			part := Part{
+				Period:             "d",
+				WeatherDescription: tran.Splug(condi.text(night)),
+				WeatherShortDesc:   tran.Splug(condi.text(night)),
+				WeatherIcon:        condi.icon(night),
+				WindDegree:         ValueMissing,
+				WindSpeed:          ValueMissing,
+				WindDirection:      ValueMissing,
+				Humidity:           ValueMissing,
+				PrecipitationProba: strconv.Itoa(taf.PrecipitationProba(from, to)),
+			}
+			if night {
+				part.Period = "n"
+			}
+			if cond.HasWind {
+				part.WindSpeed, part.WindDirection = windText(cond, w.UseCelcius)
+				if cond.WindDir >= 0 {
+					part.WindDegree = strconv.Itoa(cond.WindDir)
+				}
+				if cond.WindGust > 0 {
+					part.Gust = round(speedUnit(cond.WindGust, w.UseCelcius))
+				}
+			}
+			day.Part = append(day.Part, part)
+		}
+
+		if len(day.Part) == 0 {
+			break
+		}
+		if day.Part[0].Period != "d" { // Current daylight part is over.
+			day.Part = append([]Part{{Period: "d", WeatherDescription: ValueMissing}}, day.Part...)
+		}
+		fc.Days = append(fc.Days, day)
+	}
+	return fc
+}
+
+// locName returns the configured location name, or the station one.
+//
+func (w *metar) locName(station *Station) string {
+	if w.LocationName != "" {
+		return w.LocationName
+	}
+	return station.Site
+}
+
+//
+//-----------------------------------------------------------[ FIND LOCATION ]--
+
+// station returns the station info, downloaded once.
+//
+func (w *metar) station(code string) (*Station, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if station, ok := w.stations[code]; ok {
+		return station, nil
+	}
+
+	var list []*Station
+	e := download.JSON(METARURLBase+"/stationinfo?"+url.Values{"ids": {code}, "format": {"json"}}.Encode(), &list)
+	if e != nil {
+		return nil, e
+	}
+	if len(list) == 0 {
+		return nil, errors.New("metar: unknown station " + code)
+	}
+	w.stations[code] = list[0]
+	return list[0], nil
+}
+
+// FindLocation returns the list of stations around the places matching the
+// given name. A station code can also be given directly.
+//
+func (w *metar) FindLocation(name string) ([]Loc, error) {
+	if code := strings.ToUpper(strings.TrimSpace(name)); metarStation.MatchString(code) {
+		if station, e := w.station(code); e == nil {
+			return []Loc{stationLoc(station)}, nil
+		}
+	}
+
+	places, e := openMeteoFind(name)
+	if e != nil {
+		return nil, e
+	}
+
+	var list []Loc
+	for _, place := range places {
+		slat, slon, _ := parseCoords(place.ID)
+		lat, _ := strconv.ParseFloat(slat, 64)
+		lon, _ := strconv.ParseFloat(slon, 64)
+		bbox := fmt.Sprintf("%.2f,%.2f,%.2f,%.2f",
+			lat-metarStationSearch, lon-metarStationSearch, lat+metarStationSearch, lon+metarStationSearch)
+
+		var stations []*Station
+		e := download.JSON(METARURLBase+"/stationinfo?"+url.Values{"bbox": {bbox}, "format": {"json"}}.Encode(), &stations)
+		if e != nil {
+			return nil, e
+		}
+		for _, station := range stations {
+			if w.ValidLocation(station.ICAO) {
+				list = append(list, stationLoc(station))
+			}
+		}
+		if len(list) > 0 { // Only keep stations around the best matching place.
+			return list, nil
+		}
+	}
+	return list, nil
+}
+
+func stationLoc(station *Station) Loc {
+	return Loc{Type: "station", Name: station.Site + " (" + station.ICAO + ")", ID: station.ICAO}
+}
+
+//
+//-----------------------------------------------------------------[ HELPERS ]--
+
+// firstLine returns the first non empty line of data.
+//
+func firstLine(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// speedUnit converts a speed in km/h to the unit: km/h or mph.
+//
+func speedUnit(kmh float64, useCelcius bool) float64 {
+	if useCelcius {
+		return kmh
+	}
+	return kmh / 1.609344
+}
+
+// windText formats the wind speed and direction.
+//
+func windText(cond Conditions, useCelcius bool) (speed, direction string) {
+	speed = round(speedUnit(cond.WindSpeed, useCelcius))
+	switch {
+	case cond.WindSpeed == 0:
+		direction = "CALM"
+	case cond.WindDir < 0:
+		direction = "VAR"
+	default:
+		direction = Compass(float64(cond.WindDir))
+	}
+	return speed, direction
+}
+
+// sunText formats sunrise and sunset times, raw and for display.
+//
+func sunText(rise, set time.Time, ok, time24H bool) (sunrise, sunset, txtSunrise, txtSunset string) {
+	if !ok {
+		return ValueMissing, ValueMissing, ValueMissing, ValueMissing
+	}
+	rise, set = rise.Local(), set.Local()
+	return rise.Format("3:04 PM"), set.Format("3:04 PM"), rise.Format(timeFormat(time24H)), set.Format(timeFormat(time24H))
+}
@@ -0,0 +1,385 @@
+package weather
+
+import (
+	"github.com/sqp/godock/libs/net/download"
+	"github.com/sqp/godock/libs/text/tran"
+
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URL for Open-Meteo data. Variables so they can be redirected to a mirror.
+var (
+	OpenMeteoURLForecast  = "https://api.open-meteo.com/v1/forecast"
+	OpenMeteoURLGeocoding = "https://geocoding-api.open-meteo.com/v1/search"
+)
+
+// URLOpenMeteo is the webpage to open for Open-Meteo locations.
+const URLOpenMeteo = "https://open-meteo.com/en/docs#latitude=%s&longitude=%s"
+
+// Open-Meteo requested fields.
+const (
+	openMeteoCurrent = "temperature_2m,apparent_temperature,relative_humidity_2m,is_day,weather_code," +
+		"pressure_msl,wind_speed_10m,wind_direction_10m,visibility,uv_index"
+	openMeteoDaily = "weather_code,temperature_2m_max,temperature_2m_min,sunrise,sunset," +
+		"precipitation_probability_max,wind_speed_10m_max,wind_gusts_10m_max,wind_direction_10m_dominant"
+	openMeteoTime = "2006-01-02T15:04"
+	openMeteoDate = "2006-01-02"
+)
+
+// openMeteo downloads weather data from Open-Meteo and implements Weather.
+//
+// The location code is the coordinates: "latitude,longitude".
+//
+type openMeteo struct {
+	*Config
+
+	current  *Current
+	forecast *Forecast
+}
+
+func (w *openMeteo) Current() *Current      { return w.current }
+func (w *openMeteo) Forecast() *Forecast    { return w.forecast }
+func (w *openMeteo) SetConfig(conf *Config) { w.Config = conf }
+func (w *openMeteo) Clear()                 { w.current = nil; w.forecast = nil }
+
+// WebpageURL returns the webpage url to open to the user.
+//
+func (w *openMeteo) WebpageURL(numDay int) string {
+	lat, lon, _ := parseCoords(w.LocationCode)
+	return fmt.Sprintf(URLOpenMeteo, lat, lon)
+}
+
+// ValidLocation returns whether the location code is valid coordinates.
+//
+func (w *openMeteo) ValidLocation(code string) bool {
+	_, _, e := parseCoords(code)
+	return e == nil
+}
+
+//
+//----------------------------------------------------------------[ GET DATA ]--
+
+// openMeteoData is the JSON answer of the forecast query.
+//
+type openMeteoData struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+
+	Current struct {
+		Time          string  `json:"time"`
+		Temperature   float64 `json:"temperature_2m"`
+		Apparent      float64 `json:"apparent_temperature"`
+		Humidity      float64 `json:"relative_humidity_2m"`
+		IsDay         int     `json:"is_day"`
+		WeatherCode   int     `json:"weather_code"`
+		Pressure      float64 `json:"pressure_msl"`
+		WindSpeed     float64 `json:"wind_speed_10m"`
+		WindDirection float64 `json:"wind_direction_10m"`
+		Visibility    float64 `json:"visibility"`
+		UVIndex       float64 `json:"uv_index"`
+	} `json:"current"`
+
+	Daily struct {
+		Time               []string  `json:"time"`
+		WeatherCode        []int     `json:"weather_code"`
+		TempMax            []float64 `json:"temperature_2m_max"`
+		TempMin            []float64 `json:"temperature_2m_min"`
+		Sunrise            []string  `json:"sunrise"`
+		Sunset             []string  `json:"sunset"`
+		PrecipitationProba []float64 `json:"precipitation_probability_max"`
+		WindSpeed          []float64 `json:"wind_speed_10m_max"`
+		WindGusts          []float64 `json:"wind_gusts_10m_max"`
+		WindDirection      []float64 `json:"wind_direction_10m_dominant"`
+	} `json:"daily"`
+}
+
+func (w *openMeteo) Get() chan error {
+	chane := make(chan error, 1)
+	defer close(chane)
+
+	switch {
+	case w.Config == nil:
+		chane <- ErrMissingConfObject
+
+	case w.LocationCode == "":
+		chane <- ErrMissingLocationCode
+
+	default:
+		chane <- w.download()
+	}
+	return chane
+}
+
+func (w *openMeteo) download() error {
+	lat, lon, e := parseCoords(w.LocationCode)
+	if e != nil {
+		return e
+	}
+	query := url.Values{
+		"latitude":      {lat},
+		"longitude":     {lon},
+		"current":       {openMeteoCurrent},
+		"daily":         {openMeteoDaily},
+		"timezone":      {"auto"},
+		"forecast_days": {strconv.Itoa(w.NbDays + 1)},
+	}
+	if !w.UseCelcius {
+		query.Set("temperature_unit", "fahrenheit")
+		query.Set("wind_speed_unit", "mph")
+	}
+
+	data := &openMeteoData{}
+	e = download.JSON(OpenMeteoURLForecast+"?"+query.Encode(), data)
+	if e != nil {
+		return e
+	}
+	if data.Error {
+		return errors.New("open-meteo: " + data.Reason)
+	}
+
+	cur, fc, e := w.parse(data)
+	if e != nil {
+		return e
+	}
+
+	// Received data is valid, update it.
+	fc.Lat, fc.Lon = lat, lon
+	w.current, w.forecast = cur, fc
+	return nil
+}
+
+func (w *openMeteo) parse(data *openMeteoData) (*Current, *Forecast, error) {
+	distance, pressure, speed, temp := units(w.UseCelcius)
+	cur := &Current{
+		UnitDistance:  distance,
+		UnitPressure:  pressure,
+		UnitSpeed:     speed,
+		UnitTemp:      temp,
+		LocName:       w.LocationName,
+		Observatory:   "Open-Meteo",
+		TempReal:      roundInt(data.Current.Temperature),
+		TempFelt:      roundInt(data.Current.Apparent),
+		Pressure:      round(data.Current.Pressure),
+		WindSpeed:     round(data.Current.WindSpeed),
+		WindDirection: Compass(data.Current.WindDirection),
+		Humidity:      round(data.Current.Humidity),
+		UVi:           roundInt(data.Current.UVIndex),
+		UVDescription: uvDescription(data.Current.UVIndex),
+		IsNight:       data.Current.IsDay == 0,
+	}
+	if !w.UseCelcius {
+		cur.Pressure = fmt.Sprintf("%.2f", data.Current.Pressure/hPaPerInHg)
+	}
+	cur.Visibility = visibility(data.Current.Visibility, w.UseCelcius)
+
+	cond := wmoCondition(data.Current.WeatherCode)
+	cur.WeatherDescription = tran.Splug(cond.text(cur.IsNight))
+	cur.WeatherIcon = cond.icon(cur.IsNight)
+
+	updated, e := time.Parse(openMeteoTime, data.Current.Time)
+	if e != nil {
+		return nil, nil, e
+	}
+	cur.UpdateTime = updated.Format("1/2/06 3:04 PM")
+	cur.TxtUpdateTime = updated.Format(timeFormat(w.Time24H))
+	cur.MoonIcon = MoonAge(updated)
+
+	fc := &Forecast{
+		UnitDistance: distance,
+		UnitPressure: pressure,
+		UnitSpeed:    speed,
+		UnitTemp:     temp,
+		LocName:      w.LocationName,
+		UpdateTime:   cur.UpdateTime,
+	}
+
+	daily := &data.Daily
+	for i, dayTime := range daily.Time {
+		date, e := time.Parse(openMeteoDate, dayTime)
+		if e != nil {
+			return nil, nil, e
+		}
+		day := Day{
+			Date:     date.Format("Jan 2"),
+			DayName:  tran.Splug(date.Weekday().String()),
+			DayCount: strconv.Itoa(i),
+			MonthDay: date.Day(),
+			TempMin:  floatAt(daily.TempMin, i),
+			TempMax:  floatAt(daily.TempMax, i),
+		}
+
+		var sunset time.Time
+		if i < len(daily.Sunrise) && i < len(daily.Sunset) {
+			sunrise, e1 := time.Parse(openMeteoTime, daily.Sunrise[i])
+			sunset, e = time.Parse(openMeteoTime, daily.Sunset[i])
+			if e1 == nil && e == nil {
+				day.Sunrise = sunrise.Format("3:04 PM")
+				day.Sunset = sunset.Format("3:04 PM")
+				day.TxtSunrise = sunrise.Format(timeFormat(w.Time24H))
+				day.TxtSunset = sunset.Format(timeFormat(w.Time24H))
+			}
+		}
+		if i == 0 {
+			cur.Sunrise, cur.Sunset = day.Sunrise, day.Sunset
+			cur.TxtSunrise, cur.TxtSunset = day.TxtSunrise, day.TxtSunset
+		}
+
+		// Daily data are used for both parts. The current daylight part is
+		// left empty when over, like weather.com does.
+		cond := condition{desc: ValueMissing}
+		if i < len(daily.WeatherCode) {
+			cond = wmoCondition(daily.WeatherCode[i])
+		}
+		for _, night := range []bool{false, true} {
+			part := Part{
+				Period:             "d",
+				WeatherDescription: tran.Splug(cond.text(night)),
+				WeatherShortDesc:   tran.Splug(cond.text(night)),
+				WeatherIcon:        cond.icon(night),
+				WindDegree:         floatAt(daily.WindDirection, i),
+				WindSpeed:          floatAt(daily.WindSpeed, i),
+				Gust:               floatAt(daily.WindGusts, i),
+				Humidity:           ValueMissing,
+				PrecipitationProba: floatAt(daily.PrecipitationProba, i),
+			}
+			if i < len(daily.WindDirection) {
+				part.WindDirection = Compass(daily.WindDirection[i])
+			}
+			if night {
+				part.Period = "n"
+			} else if i == 0 && !sunset.IsZero() && updated.After(sunset) {
+				part.WeatherIcon = ""
+			}
+			day.Part = append(day.Part, part)
+		}
+		fc.Days = append(fc.Days, day)
+	}
+	return cur, fc, nil
+}
+
+//
+//-----------------------------------------------------------[ FIND LOCATION ]--
+
+// openMeteoSearch is the JSON answer of the geocoding query.
+//
+type openMeteoSearch struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Country   string  `json:"country"`
+		Admin1    string  `json:"admin1"`
+	} `json:"results"`
+}
+
+// FindLocation asks the Open-Meteo geocoding service the list of locations
+// matching the given name.
+//
+func (w *openMeteo) FindLocation(name string) ([]Loc, error) {
+	return openMeteoFind(name)
+}
+
+func openMeteoFind(name string) ([]Loc, error) {
+	// The service only matches a place name, so drop the country if any.
+	if i := strings.Index(name, ","); i > 0 {
+		name = name[:i]
+	}
+	query := url.Values{"name": {strings.TrimSpace(name)}, "count": {"10"}, "format": {"json"}}
+	var search openMeteoSearch
+	e := download.JSON(OpenMeteoURLGeocoding+"?"+query.Encode(), &search)
+	if e != nil {
+		return nil, e
+	}
+
+	var list []Loc
+	for _, res := range search.Results {
+		names := []string{res.Name}
+		for _, str := range []string{res.Admin1, res.Country} {
+			if str != "" && str != res.Name {
+				names = append(names, str)
+			}
+		}
+		list = append(list, Loc{
+			Type: "coords",
+			Name: strings.Join(names, ", "),
+			ID:   formatCoords(res.Latitude, res.Longitude),
+		})
+	}
+	return list, nil
+}
+
+//
+//-----------------------------------------------------------------[ HELPERS ]--
+
+// hPaPerInHg is the number of hectopascals in one inch of mercury.
+const hPaPerInHg = 33.8639
+
+// formatCoords formats coordinates as a location code.
+//
+func formatCoords(lat, lon float64) string {
+	return strconv.FormatFloat(lat, 'f', 4, 64) + "," + strconv.FormatFloat(lon, 'f', 4, 64)
+}
+
+// parseCoords splits and checks coordinates from a location code.
+//
+func parseCoords(code string) (lat, lon string, e error) {
+	args := strings.Split(code, ",")
+	if len(args) != 2 {
+		return "", "", errors.New("bad coordinates: " + code)
+	}
+	lat, lon = strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+	flat, e := strconv.ParseFloat(lat, 64)
+	if e != nil {
+		return "", "", e
+	}
+	flon, e := strconv.ParseFloat(lon, 64)
+	if e != nil {
+		return "", "", e
+	}
+	if flat < -90 || flat > 90 || flon < -180 || flon > 180 {
+		return "", "", errors.New("coordinates out of range: " + code)
+	}
+	return lat, lon, nil
+}
+
+// floatAt returns the formatted value at index in the list, if any.
+//
+func floatAt(list []float64, i int) string {
+	if i >= len(list) {
+		return ValueMissing
+	}
+	return round(list[i])
+}
+
+// visibility formats a visibility in meters to km or miles.
+//
+func visibility(meters float64, useCelcius bool) string {
+	if meters < 0 {
+		return ValueMissing
+	}
+	if useCelcius {
+		return strconv.FormatFloat(meters/1000, 'f', 1, 64)
+	}
+	return strconv.FormatFloat(meters/1609.344, 'f', 1, 64)
+}
+
+// uvDescription returns the description of the UV index.
+//
+func uvDescription(index float64) string {
+	switch {
+	case index < 3:
+		return "Low"
+	case index < 6:
+		return "Moderate"
+	case index < 8:
+		return "High"
+	case index < 11:
+		return "Very High"
+	}
+	return "Extreme"
+}
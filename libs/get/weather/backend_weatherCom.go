@@ -5,6 +5,7 @@ import (
 	"github.com/sqp/godock/libs/text/tran"
 
 	"fmt"
+	"regexp"
 	"sync"
 	"time"
 )
@@ -26,6 +27,9 @@ const (
 	URLWeatherComDetail = "http://www.weather.com/weather/tenday/%s"
 )
 
+// weatherComLocation matches weather.com location codes (FRXX0076) and US zip codes.
+var weatherComLocation = regexp.MustCompile(`^([A-Z]{4}\d{4}|\d{5})$`)
+
 // weatherCom downloads weather data from weather.com and implements Weather
 //
 type weatherCom struct {
@@ -170,6 +174,18 @@ type Loc struct {
 
 // FindLocation asks the server the list of locations matching the given name.
 //
+func (w *weatherCom) FindLocation(locationName string) ([]Loc, error) {
+	return FindLocation(locationName)
+}
+
+// ValidLocation returns whether the location code is a weather.com code.
+//
+func (w *weatherCom) ValidLocation(code string) bool {
+	return weatherComLocation.MatchString(code)
+}
+
+// FindLocation asks weather.com the list of locations matching the given name.
+//
 func FindLocation(locationName string) ([]Loc, error) {
 	var search Search
 	e := download.XML(WeatherComURLBase+"/search/search?where="+locationName, &search)
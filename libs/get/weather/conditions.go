@@ -0,0 +1,202 @@
+package weather
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+//
+//--------------------------------------------------------------[ CONDITIONS ]--
+
+// condition defines a weather condition with its description and the matching
+// weather.com icon codes, used by themes.
+//
+type condition struct {
+	desc      string
+	descNight string // Optional.
+	day       int
+	night     int
+}
+
+// Weather conditions, common to backends.
+var (
+	condClear        = condition{"Sunny", "Clear", 32, 31}
+	condFair         = condition{"Fair", "", 34, 33}
+	condPartlyCloudy = condition{"Partly Cloudy", "", 30, 29}
+	condMostlyCloudy = condition{"Mostly Cloudy", "", 28, 27}
+	condCloudy       = condition{"Cloudy", "", 26, 26}
+	condFog          = condition{"Fog", "", 20, 20}
+	condMist         = condition{"Mist", "", 20, 20}
+	condHaze         = condition{"Haze", "", 21, 21}
+	condSmoke        = condition{"Smoke", "", 22, 22}
+	condDust         = condition{"Dust", "", 19, 19}
+	condWindy        = condition{"Windy", "", 24, 24}
+	condTornado      = condition{"Tornado", "", 0, 0}
+	condDrizzle      = condition{"Drizzle", "", 9, 9}
+	condFreezingDz   = condition{"Freezing Drizzle", "", 8, 8}
+	condLightRain    = condition{"Light Rain", "", 11, 11}
+	condRain         = condition{"Rain", "", 12, 12}
+	condHeavyRain    = condition{"Heavy Rain", "", 40, 40}
+	condFreezingRain = condition{"Freezing Rain", "", 10, 10}
+	condShowers      = condition{"Showers", "", 39, 45}
+	condRainSnow     = condition{"Rain and Snow", "", 5, 5}
+	condSleet        = condition{"Sleet", "", 18, 18}
+	condHail         = condition{"Hail", "", 17, 17}
+	condLightSnow    = condition{"Light Snow", "", 14, 14}
+	condSnow         = condition{"Snow", "", 16, 16}
+	condHeavySnow    = condition{"Heavy Snow", "", 42, 42}
+	condSnowShowers  = condition{"Snow Showers", "", 41, 46}
+	condThunder      = condition{"Thunderstorm", "", 4, 47}
+	condSevereStorm  = condition{"Severe Thunderstorm", "", 3, 3}
+)
+
+// text returns the description of the condition.
+//
+func (c condition) text(night bool) string {
+	if night && c.descNight != "" {
+		return c.descNight
+	}
+	return c.desc
+}
+
+// icon returns the weather.com icon code of the condition.
+//
+func (c condition) icon(night bool) string {
+	if night {
+		return strconv.Itoa(c.night)
+	}
+	return strconv.Itoa(c.day)
+}
+
+// wmoCondition returns the condition for a WMO weather interpretation code.
+//
+func wmoCondition(code int) condition {
+	switch code {
+	case 0:
+		return condClear
+	case 1:
+		return condFair
+	case 2:
+		return condPartlyCloudy
+	case 3:
+		return condCloudy
+	case 45, 48:
+		return condFog
+	case 51, 53, 55:
+		return condDrizzle
+	case 56, 57:
+		return condFreezingDz
+	case 61:
+		return condLightRain
+	case 63:
+		return condRain
+	case 65:
+		return condHeavyRain
+	case 66, 67:
+		return condFreezingRain
+	case 71:
+		return condLightSnow
+	case 73, 77:
+		return condSnow
+	case 75:
+		return condHeavySnow
+	case 80, 81, 82:
+		return condShowers
+	case 85, 86:
+		return condSnowShowers
+	case 95:
+		return condThunder
+	case 96, 99:
+		return condSevereStorm
+	}
+	return condition{desc: ValueMissing}
+}
+
+//
+//-----------------------------------------------------------------[ HELPERS ]--
+
+// Compass returns the compass direction name for the wind direction in degrees.
+//
+func Compass(degree float64) string {
+	names := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	index := int(math.Floor(math.Mod(degree+11.25, 360) / 22.5))
+	if index < 0 {
+		index += len(names)
+	}
+	return names[index%len(names)]
+}
+
+// MoonAge returns the age of the moon in days (0 to 29), 0 is new moon.
+//
+func MoonAge(t time.Time) int {
+	const synodic = 29.530588853
+	newMoon := time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
+	days := t.Sub(newMoon).Hours() / 24
+	age := math.Mod(days, synodic)
+	if age < 0 {
+		age += synodic
+	}
+	return int(age) % 30
+}
+
+// SunTimes returns the sunrise and sunset times for the day at the given
+// location. ok is false when the sun doesn't rise or set that day (polar day
+// or night), and night tells which one.
+//
+func SunTimes(day time.Time, lat, lon float64) (rise, set time.Time, ok, night bool) {
+	const rad = math.Pi / 180
+	y, m, d := day.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, time.UTC)
+	julian := float64(noon.Unix())/86400 + 2440587.5
+
+	n := math.Floor(julian - 2451545 + 0.5)
+	meanNoon := n - lon/360
+	anomaly := math.Mod(357.5291+0.98560028*meanNoon, 360) * rad
+	center := 1.9148*math.Sin(anomaly) + 0.02*math.Sin(2*anomaly) + 0.0003*math.Sin(3*anomaly)
+	ecliptic := math.Mod(anomaly/rad+center+180+102.9372, 360) * rad
+	transit := 2451545 + meanNoon + 0.0053*math.Sin(anomaly) - 0.0069*math.Sin(2*ecliptic)
+
+	sinDecl := math.Sin(ecliptic) * math.Sin(23.4397*rad)
+	cosDecl := math.Cos(math.Asin(sinDecl))
+	cosHour := (math.Sin(-0.833*rad) - math.Sin(lat*rad)*sinDecl) / (math.Cos(lat*rad) * cosDecl)
+	if cosHour < -1 || cosHour > 1 {
+		return rise, set, false, cosHour > 1
+	}
+	hour := math.Acos(cosHour) / rad / 360
+
+	fromJulian := func(j float64) time.Time {
+		return time.Unix(int64((j-2440587.5)*86400), 0)
+	}
+	return fromJulian(transit - hour), fromJulian(transit + hour), true, false
+}
+
+// celsius converts the temperature to the unit: celsius or fahrenheit.
+//
+func celsius(temp float64, useCelcius bool) float64 {
+	if useCelcius {
+		return temp
+	}
+	return temp*9/5 + 32
+}
+
+// round formats the value as an integer string.
+//
+func round(value float64) string {
+	return strconv.Itoa(roundInt(value))
+}
+
+// roundInt rounds the value to the nearest integer.
+//
+func roundInt(value float64) int {
+	return int(math.Floor(value + 0.5))
+}
+
+// units returns the distance, pressure, speed and temperature units.
+//
+func units(useCelcius bool) (distance, pressure, speed, temp string) {
+	if useCelcius {
+		return "km", "mb", "km/h", DegreeSymbol + "C"
+	}
+	return "mi", "in", "mph", DegreeSymbol + "F"
+}
@@ -0,0 +1,525 @@
+package weather
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+//-------------------------------------------------------------[ CONDITIONS ]--
+
+// Cloud cover levels, from METAR cloud groups.
+const (
+	CoverUnknown = iota - 1
+	CoverClear
+	CoverFew
+	CoverScattered
+	CoverBroken
+	CoverOvercast
+)
+
+// Conditions defines the weather conditions reported by a METAR or a TAF group.
+//
+type Conditions struct {
+	HasWind    bool
+	WindDir    int      // Wind direction in degrees, -1 when variable.
+	WindSpeed  float64  // Wind speed in km/h.
+	WindGust   float64  // Wind gust in km/h, 0 if none.
+	Visibility float64  // Visibility in meters, 0 if unknown.
+	Weather    []string // Weather phenomena (-RA, TSRA, BR...). Nil if unknown, empty for none.
+	Cover      int      // Cloud cover level (Cover consts).
+}
+
+func newConditions() Conditions {
+	return Conditions{Cover: CoverUnknown}
+}
+
+// merge applies the fields set in the given conditions.
+//
+func (c *Conditions) merge(other Conditions) {
+	if other.HasWind {
+		c.HasWind, c.WindDir, c.WindSpeed, c.WindGust = true, other.WindDir, other.WindSpeed, other.WindGust
+	}
+	if other.Visibility > 0 {
+		c.Visibility = other.Visibility
+	}
+	if other.Weather != nil {
+		c.Weather = other.Weather
+	}
+	if other.Cover != CoverUnknown {
+		c.Cover = other.Cover
+	}
+}
+
+// HasPrecipitation returns whether precipitations are reported.
+//
+func (c *Conditions) HasPrecipitation() bool {
+	for _, wx := range c.Weather {
+		if strings.HasPrefix(wx, "VC") {
+			continue
+		}
+		for _, code := range []string{"DZ", "RA", "SN", "SG", "PL", "GR", "GS", "UP", "TS"} {
+			if strings.Contains(wx, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// condition returns the matching weather condition.
+//
+func (c *Conditions) condition() condition {
+	var cond *condition
+	rank := 0
+	set := func(r int, cd condition) {
+		if r > rank {
+			rank, cond = r, &cd
+		}
+	}
+
+	for _, wx := range c.Weather {
+		if strings.HasPrefix(wx, "VC") { // Vicinity.
+			continue
+		}
+		heavy, light := strings.HasPrefix(wx, "+"), strings.HasPrefix(wx, "-")
+		has := func(code string) bool { return strings.Contains(wx, code) }
+		switch {
+		case has("FC"):
+			set(20, condTornado)
+		case has("TS") && heavy:
+			set(19, condSevereStorm)
+		case has("TS"):
+			set(18, condThunder)
+		case has("GR"), has("GS"):
+			set(17, condHail)
+		case has("FZ") && has("RA"):
+			set(16, condFreezingRain)
+		case has("FZ") && has("DZ"):
+			set(15, condFreezingDz)
+		case has("PL"):
+			set(14, condSleet)
+		case has("SN") && (has("RA") || has("DZ")):
+			set(13, condRainSnow)
+		case has("SN") && has("SH"):
+			set(12, condSnowShowers)
+		case has("SN") && heavy:
+			set(12, condHeavySnow)
+		case has("SN") && light, has("SG"):
+			set(12, condLightSnow)
+		case has("SN"):
+			set(12, condSnow)
+		case has("RA") && has("SH"):
+			set(11, condShowers)
+		case has("RA") && heavy:
+			set(11, condHeavyRain)
+		case has("RA") && light:
+			set(11, condLightRain)
+		case has("RA"), has("UP"):
+			set(11, condRain)
+		case has("DZ"):
+			set(10, condDrizzle)
+		case has("SQ"):
+			set(9, condWindy)
+		case has("FG"):
+			set(8, condFog)
+		case has("DU"), has("SA"), has("SS"), has("DS"), has("PO"):
+			set(7, condDust)
+		case has("FU"), has("VA"):
+			set(6, condSmoke)
+		case has("HZ"):
+			set(5, condHaze)
+		case has("BR"):
+			set(4, condMist)
+		}
+	}
+	if cond != nil {
+		return *cond
+	}
+
+	switch c.Cover {
+	case CoverClear:
+		return condClear
+	case CoverFew:
+		return condFair
+	case CoverScattered:
+		return condPartlyCloudy
+	case CoverBroken:
+		return condMostlyCloudy
+	case CoverOvercast:
+		return condCloudy
+	}
+	return condition{desc: ValueMissing}
+}
+
+var (
+	reWind       = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(G(\d{2,3}))?(KT|MPS|KMH)$`)
+	reVisibility = regexp.MustCompile(`^(\d{4})(NDV)?$`)
+	reVisMiles   = regexp.MustCompile(`^P?(\d+)(/(\d+))?SM$`)
+	reWeather    = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ|DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)+$`)
+	reCloud      = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU|///)?$`)
+)
+
+// parseCondition parses a conditions group into c. Returns false if the token
+// wasn't a conditions group.
+//
+func parseCondition(c *Conditions, token string) bool {
+	switch token {
+	case "CAVOK":
+		c.Visibility, c.Cover = 10000, CoverClear
+		if c.Weather == nil {
+			c.Weather = []string{}
+		}
+		return true
+
+	case "SKC", "CLR", "NSC", "NCD":
+		if c.Cover == CoverUnknown {
+			c.Cover = CoverClear
+		}
+		return true
+
+	case "NSW":
+		c.Weather = []string{}
+		return true
+	}
+
+	if m := reWind.FindStringSubmatch(token); m != nil {
+		factor := 1.852 // Knots.
+		switch m[5] {
+		case "MPS":
+			factor = 3.6
+		case "KMH":
+			factor = 1
+		}
+		c.HasWind = true
+		c.WindDir = -1
+		if m[1] != "VRB" {
+			c.WindDir, _ = strconv.Atoi(m[1])
+		}
+		speed, _ := strconv.Atoi(m[2])
+		c.WindSpeed = float64(speed) * factor
+		gust, _ := strconv.Atoi(m[4])
+		c.WindGust = float64(gust) * factor
+		return true
+	}
+
+	if m := reVisibility.FindStringSubmatch(token); m != nil {
+		c.Visibility, _ = strconv.ParseFloat(m[1], 64)
+		if c.Visibility == 0 {
+			c.Visibility = 1 // Keep set for merge.
+		}
+		return true
+	}
+
+	if m := reVisMiles.FindStringSubmatch(token); m != nil {
+		miles, _ := strconv.ParseFloat(m[1], 64)
+		if m[3] != "" {
+			div, _ := strconv.ParseFloat(m[3], 64)
+			if div > 0 {
+				miles /= div
+			}
+		}
+		c.Visibility = math.Max(miles*1609.344, 1)
+		return true
+	}
+
+	if m := reCloud.FindStringSubmatch(token); m != nil {
+		cover := map[string]int{"FEW": CoverFew, "SCT": CoverScattered, "BKN": CoverBroken, "OVC": CoverOvercast, "VV": CoverOvercast}[m[1]]
+		if cover > c.Cover {
+			c.Cover = cover
+		}
+		return true
+	}
+
+	if reWeather.MatchString(token) {
+		c.Weather = append(c.Weather, token)
+		return true
+	}
+	return false
+}
+
+//
+//-------------------------------------------------------------------[ METAR ]--
+
+// METAR defines a decoded METAR weather observation.
+//
+type METAR struct {
+	Conditions
+	Station  string
+	Time     time.Time
+	HasTemp  bool
+	Temp     int     // Temperature in °C.
+	Dew      int     // Dew point in °C.
+	Pressure float64 // Sea level pressure in hPa, 0 if unknown.
+}
+
+var (
+	reTime = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	reTemp = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	reQNH  = regexp.MustCompile(`^([QA])(\d{4})$`)
+)
+
+// ParseMETAR decodes a raw METAR report. The observation day is resolved in
+// the month of ref (usually now), or the previous one.
+//
+func ParseMETAR(raw string, ref time.Time) (*METAR, error) {
+	fields := strings.Fields(raw)
+	for len(fields) > 0 && (fields[0] == "METAR" || fields[0] == "SPECI") {
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return nil, errors.New("metar: report too short: " + raw)
+	}
+
+	met := &METAR{Conditions: newConditions(), Station: fields[0]}
+	for _, token := range fields[1:] {
+		switch token {
+		case "RMK", "TEMPO", "BECMG", "NOSIG": // Remarks and trends are not decoded.
+			return met, nil
+
+		case "AUTO", "COR", "NIL":
+			continue
+		}
+
+		if m := reTime.FindStringSubmatch(token); m != nil {
+			met.Time = reportTime(ref, m[1], m[2], m[3])
+
+		} else if m := reTemp.FindStringSubmatch(token); m != nil {
+			met.HasTemp = true
+			met.Temp = parseTemp(m[1])
+			met.Dew = met.Temp
+			if m[2] != "" {
+				met.Dew = parseTemp(m[2])
+			}
+
+		} else if m := reQNH.FindStringSubmatch(token); m != nil {
+			value, _ := strconv.ParseFloat(m[2], 64)
+			if m[1] == "A" {
+				value = value / 100 * hPaPerInHg
+			}
+			met.Pressure = value
+
+		} else {
+			parseCondition(&met.Conditions, token)
+		}
+	}
+	return met, nil
+}
+
+// Humidity returns the relative humidity computed from the temperature and
+// the dew point.
+//
+func (met *METAR) Humidity() float64 {
+	magnus := func(t float64) float64 { return math.Exp(17.625 * t / (243.04 + t)) }
+	return 100 * magnus(float64(met.Dew)) / magnus(float64(met.Temp))
+}
+
+// FeltTemp returns the felt temperature in °C, with the wind chill when cold.
+//
+func (met *METAR) FeltTemp() float64 {
+	temp := float64(met.Temp)
+	if temp > 10 || met.WindSpeed < 4.8 {
+		return temp
+	}
+	v := math.Pow(met.WindSpeed, 0.16)
+	return 13.12 + 0.6215*temp - 11.37*v + 0.3965*temp*v
+}
+
+//
+//---------------------------------------------------------------------[ TAF ]--
+
+// TAF defines a decoded Terminal Aerodrome Forecast.
+//
+type TAF struct {
+	Station  string
+	Issued   time.Time
+	From, To time.Time
+	Groups   []TAFGroup        // The base forecast first, then changes in order.
+	TempMax  map[time.Time]int // Max temperatures in °C, by UTC day.
+	TempMin  map[time.Time]int // Min temperatures in °C, by UTC day.
+}
+
+// TAFGroup defines a forecast group of a TAF.
+//
+type TAFGroup struct {
+	Conditions
+	Kind        string // "" for the base forecast, FM, BECMG, TEMPO or PROB.
+	From, To    time.Time
+	Probability int // Percent, for TEMPO and PROB groups.
+}
+
+var (
+	rePeriod = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	reFrom   = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	reProb   = regexp.MustCompile(`^PROB(\d{2})$`)
+	reTX     = regexp.MustCompile(`^T([XN])(M?\d{2})/(\d{2})(\d{2})Z$`)
+)
+
+// ParseTAF decodes a raw TAF report. Days are resolved in the month of ref
+// (usually now), or the closest one.
+//
+func ParseTAF(raw string, ref time.Time) (*TAF, error) {
+	fields := strings.Fields(raw)
+	for len(fields) > 0 && (fields[0] == "TAF" || fields[0] == "AMD" || fields[0] == "COR") {
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return nil, errors.New("taf: report too short: " + raw)
+	}
+
+	taf := &TAF{
+		Station: fields[0],
+		TempMax: make(map[time.Time]int),
+		TempMin: make(map[time.Time]int),
+	}
+	taf.Groups = []TAFGroup{{Conditions: newConditions()}}
+	group := &taf.Groups[0]
+	newGroup := func(kind string, from, to time.Time, proba int) {
+		taf.Groups = append(taf.Groups, TAFGroup{Conditions: newConditions(), Kind: kind, From: from, To: to, Probability: proba})
+		group = &taf.Groups[len(taf.Groups)-1]
+	}
+
+	for _, token := range fields[1:] {
+		if token == "RMK" {
+			break
+		}
+
+		if m := reTime.FindStringSubmatch(token); m != nil && taf.Issued.IsZero() {
+			taf.Issued = reportTime(ref, m[1], m[2], m[3])
+
+		} else if m := rePeriod.FindStringSubmatch(token); m != nil {
+			from := reportTime(ref, m[1], m[2], "00")
+			to := reportTime(from, m[3], m[4], "00")
+			if to.Before(from) {
+				to = to.AddDate(0, 1, 0)
+			}
+			if taf.From.IsZero() {
+				taf.From, taf.To = from, to
+				group.From, group.To = from, to
+			} else {
+				group.From, group.To = from, to
+			}
+
+		} else if m := reFrom.FindStringSubmatch(token); m != nil {
+			from := reportTime(taf.From, m[1], m[2], m[3])
+			newGroup("FM", from, taf.To, 0)
+
+		} else if token == "BECMG" {
+			newGroup("BECMG", time.Time{}, time.Time{}, 0)
+
+		} else if token == "TEMPO" {
+			proba := 50
+			if group.Kind == "PROB" && group.From.IsZero() { // PROB30 TEMPO.
+				proba = group.Probability
+			}
+			newGroup("TEMPO", time.Time{}, time.Time{}, proba)
+
+		} else if m := reProb.FindStringSubmatch(token); m != nil {
+			proba, _ := strconv.Atoi(m[1])
+			newGroup("PROB", time.Time{}, time.Time{}, proba)
+
+		} else if m := reTX.FindStringSubmatch(token); m != nil {
+			when := reportTime(taf.From, m[3], m[4], "00")
+			day := time.Date(when.Year(), when.Month(), when.Day(), 0, 0, 0, 0, time.UTC)
+			if m[1] == "X" {
+				taf.TempMax[day] = parseTemp(m[2])
+			} else {
+				taf.TempMin[day] = parseTemp(m[2])
+			}
+
+		} else if token != "NIL" && token != "CNL" {
+			parseCondition(&group.Conditions, token)
+		}
+	}
+
+	// Drop the PROB group when directly followed by its TEMPO.
+	for i := len(taf.Groups) - 2; i >= 0; i-- {
+		if taf.Groups[i].Kind == "PROB" && taf.Groups[i].From.IsZero() && taf.Groups[i+1].Kind == "TEMPO" {
+			taf.Groups = append(taf.Groups[:i], taf.Groups[i+1:]...)
+		}
+	}
+	return taf, nil
+}
+
+// Prevailing returns the forecast prevailing conditions at the given time.
+//
+func (taf *TAF) Prevailing(t time.Time) Conditions {
+	cond := taf.Groups[0].Conditions
+	for _, group := range taf.Groups[1:] {
+		if group.From.After(t) {
+			continue
+		}
+		switch group.Kind {
+		case "FM":
+			cond = group.Conditions
+			if cond.Weather == nil {
+				cond.Weather = []string{}
+			}
+
+		case "BECMG":
+			cond.merge(group.Conditions)
+		}
+	}
+	return cond
+}
+
+// PrecipitationProba returns the probability of precipitations, in percent,
+// during the given period.
+//
+func (taf *TAF) PrecipitationProba(from, to time.Time) int {
+	proba := 0
+	for t := from; t.Before(to); t = t.Add(time.Hour) {
+		if cond := taf.Prevailing(t); cond.HasPrecipitation() {
+			return 100
+		}
+	}
+	for _, group := range taf.Groups[1:] {
+		if (group.Kind == "TEMPO" || group.Kind == "PROB") && group.HasPrecipitation() &&
+			group.From.Before(to) && group.To.After(from) && group.Probability > proba {
+			proba = group.Probability
+		}
+	}
+	return proba
+}
+
+// Covers returns whether the forecast covers the given time.
+//
+func (taf *TAF) Covers(t time.Time) bool {
+	return !t.Before(taf.From) && t.Before(taf.To)
+}
+
+//
+//-----------------------------------------------------------------[ HELPERS ]--
+
+// reportTime returns the UTC time for a day of month, hour and minute,
+// resolved in the month of ref, or the closest one when far from ref.
+//
+func reportTime(ref time.Time, day, hour, minute string) time.Time {
+	d, _ := strconv.Atoi(day)
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	ref = ref.UTC()
+	t := time.Date(ref.Year(), ref.Month(), d, h, m, 0, 0, time.UTC)
+	switch {
+	case t.Sub(ref) > 48*time.Hour:
+		t = time.Date(ref.Year(), ref.Month()-1, d, h, m, 0, 0, time.UTC)
+
+	case ref.Sub(t) > 20*24*time.Hour:
+		t = time.Date(ref.Year(), ref.Month()+1, d, h, m, 0, 0, time.UTC)
+	}
+	return t
+}
+
+// parseTemp parses a METAR temperature (M prefix for negative).
+//
+func parseTemp(str string) int {
+	value, _ := strconv.Atoi(strings.TrimPrefix(str, "M"))
+	if strings.HasPrefix(str, "M") {
+		return -value
+	}
+	return value
+}
@@ -22,6 +22,13 @@ type Weather interface {
 	Forecast() *Forecast
 	SetConfig(*Config)
 	Clear()
+
+	// FindLocation returns the list of locations matching the given name,
+	// with their backend location code as ID.
+	FindLocation(name string) ([]Loc, error)
+
+	// ValidLocation returns whether the location code is valid for the backend.
+	ValidLocation(code string) bool
 }
 
 // Weather errors.
@@ -38,6 +45,8 @@ type BackendID int
 // Weather backends.
 const (
 	BackendWeatherCom BackendID = iota
+	BackendOpenMeteo
+	BackendMETAR
 )
 
 // Other possible backends.
@@ -50,18 +59,27 @@ const (
 
 // New creates a new weather data source for the given backend.
 //
-func New() Weather { // backend BackendID
+func New(backend BackendID) Weather {
+	switch backend {
+	case BackendOpenMeteo:
+		return &openMeteo{}
+
+	case BackendMETAR:
+		return &metar{stations: make(map[string]*Station)}
+	}
 	return &weatherCom{}
 }
 
 // Config defines the weather data source configuration.
 //
 type Config struct {
-	LocationCode       string // hidden in conf
-	UseCelcius         bool   // format temp celcius or fahrenheit
-	Time24H            bool   // format time 24H or 12H (AM/PM).
-	DisplayCurrentIcon bool   // current weather.
-	NbDays             int    // forecast (next days).
+	Backend            BackendID // weather data source.
+	LocationCode       string    // hidden in conf
+	LocationName       string    // hidden in conf, displayed when the backend has no name.
+	UseCelcius         bool      // format temp celcius or fahrenheit
+	Time24H            bool      // format time 24H or 12H (AM/PM).
+	DisplayCurrentIcon bool      // current weather.
+	NbDays             int       // forecast (next days).
 }
 
 //
@@ -5,11 +5,14 @@ import (
 
 	"github.com/sqp/godock/libs/get/weather"
 
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestDockbus(t *testing.T) {
-	wea := weather.New()
+	wea := weather.New(weather.BackendWeatherCom)
 	locations, e := weather.FindLocation("paris")
 	assert.NoError(t, e, "weather.FindLocation")
 	assert.NotEmpty(t, locations, "locations")
@@ -37,3 +40,153 @@ func TestDockbus(t *testing.T) {
 	assert.NotEmpty(t, cur.WeatherIcon, "WeatherIcon")
 	assert.NotEmpty(t, cur.MoonIcon, "MoonIcon")
 }
+
+func TestParseMETAR(t *testing.T) {
+	ref := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	met, e := weather.ParseMETAR("METAR LFPO 291030Z AUTO 24015G28KT 200V280 3000 -SHRA BR FEW015 BKN030CB M02/M05 Q1008 TEMPO 4000", ref)
+	if !assert.NoError(t, e, "ParseMETAR") {
+		return
+	}
+	assert.Equal(t, "LFPO", met.Station, "Station")
+	assert.Equal(t, time.Date(2016, 2, 29, 10, 30, 0, 0, time.UTC), met.Time, "Time previous month")
+	assert.Equal(t, 240, met.WindDir, "WindDir")
+	assert.InDelta(t, 27.78, met.WindSpeed, 0.01, "WindSpeed km/h")
+	assert.InDelta(t, 51.86, met.WindGust, 0.01, "WindGust km/h")
+	assert.Equal(t, 3000.0, met.Visibility, "Visibility")
+	assert.Equal(t, []string{"-SHRA", "BR"}, met.Weather, "Weather")
+	assert.Equal(t, weather.CoverBroken, met.Cover, "Cover")
+	assert.True(t, met.HasTemp, "HasTemp")
+	assert.Equal(t, -2, met.Temp, "Temp")
+	assert.Equal(t, -5, met.Dew, "Dew")
+	assert.Equal(t, 1008.0, met.Pressure, "Pressure")
+	assert.InDelta(t, 80, met.Humidity(), 1, "Humidity")
+	assert.True(t, met.FeltTemp() < -2, "FeltTemp wind chill")
+	assert.True(t, met.HasPrecipitation(), "HasPrecipitation")
+
+	met, e = weather.ParseMETAR("KJFK 011051Z VRB03KT 1/2SM FG VV002 18/18 A3001 RMK AO2 TSB05", ref)
+	if !assert.NoError(t, e, "ParseMETAR US") {
+		return
+	}
+	assert.Equal(t, -1, met.WindDir, "WindDir variable")
+	assert.InDelta(t, 804.7, met.Visibility, 0.1, "Visibility miles")
+	assert.Equal(t, []string{"FG"}, met.Weather, "Weather remarks ignored")
+	assert.Equal(t, weather.CoverOvercast, met.Cover, "Cover vertical visibility")
+	assert.InDelta(t, 1016.3, met.Pressure, 0.1, "Pressure inHg")
+
+	met, _ = weather.ParseMETAR("EGLL 011050Z 05005KT CAVOK 08/01 Q1030 NOSIG", ref)
+	assert.Equal(t, weather.CoverClear, met.Cover, "CAVOK")
+	assert.False(t, met.HasPrecipitation(), "CAVOK precipitation")
+
+	_, e = weather.ParseMETAR("", ref)
+	assert.Error(t, e, "ParseMETAR empty")
+}
+
+func TestParseTAF(t *testing.T) {
+	ref := time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)
+	taf, e := weather.ParseTAF(`TAF LFPO 011100Z 0112/0218 24010KT 9999 SCT030 TX12/0114Z TNM01/0206Z
+		BECMG 0118/0120 VRB03KT BKN015
+		PROB30 TEMPO 0200/0206 -RA
+		FM021200 31020G35KT 6000 SHRA OVC010`, ref)
+	if !assert.NoError(t, e, "ParseTAF") {
+		return
+	}
+	assert.Equal(t, "LFPO", taf.Station, "Station")
+	assert.Equal(t, time.Date(2016, 3, 1, 11, 0, 0, 0, time.UTC), taf.Issued, "Issued")
+	assert.Equal(t, time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC), taf.From, "From")
+	assert.Equal(t, time.Date(2016, 3, 2, 18, 0, 0, 0, time.UTC), taf.To, "To")
+	assert.Len(t, taf.Groups, 4, "Groups")
+	assert.Equal(t, 12, taf.TempMax[time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC)], "TempMax")
+	assert.Equal(t, -1, taf.TempMin[time.Date(2016, 3, 2, 0, 0, 0, 0, time.UTC)], "TempMin")
+
+	cond := taf.Prevailing(time.Date(2016, 3, 1, 14, 0, 0, 0, time.UTC))
+	assert.Equal(t, weather.CoverScattered, cond.Cover, "base Cover")
+	assert.Equal(t, 240, cond.WindDir, "base WindDir")
+
+	cond = taf.Prevailing(time.Date(2016, 3, 2, 3, 0, 0, 0, time.UTC))
+	assert.Equal(t, weather.CoverBroken, cond.Cover, "BECMG Cover")
+	assert.Equal(t, -1, cond.WindDir, "BECMG WindDir")
+	assert.Equal(t, 9999.0, cond.Visibility, "BECMG Visibility kept")
+
+	cond = taf.Prevailing(time.Date(2016, 3, 2, 13, 0, 0, 0, time.UTC))
+	assert.Equal(t, weather.CoverOvercast, cond.Cover, "FM Cover")
+	assert.Equal(t, []string{"SHRA"}, cond.Weather, "FM Weather")
+
+	night := taf.PrecipitationProba(time.Date(2016, 3, 1, 18, 0, 0, 0, time.UTC), time.Date(2016, 3, 2, 6, 0, 0, 0, time.UTC))
+	assert.Equal(t, 30, night, "PrecipitationProba PROB30 TEMPO")
+	day := taf.PrecipitationProba(time.Date(2016, 3, 2, 6, 0, 0, 0, time.UTC), time.Date(2016, 3, 2, 18, 0, 0, 0, time.UTC))
+	assert.Equal(t, 100, day, "PrecipitationProba prevailing")
+	assert.True(t, taf.Covers(time.Date(2016, 3, 2, 0, 0, 0, 0, time.UTC)), "Covers")
+	assert.False(t, taf.Covers(time.Date(2016, 3, 2, 18, 0, 0, 0, time.UTC)), "Covers end")
+}
+
+func TestHelpers(t *testing.T) {
+	rise, set, ok, _ := weather.SunTimes(time.Date(2016, 6, 21, 0, 0, 0, 0, time.UTC), 48.85, 2.35)
+	assert.True(t, ok, "SunTimes ok")
+	assert.WithinDuration(t, time.Date(2016, 6, 21, 3, 47, 0, 0, time.UTC), rise, 3*time.Minute, "SunTimes rise")
+	assert.WithinDuration(t, time.Date(2016, 6, 21, 19, 58, 0, 0, time.UTC), set, 3*time.Minute, "SunTimes set")
+
+	_, _, ok, night := weather.SunTimes(time.Date(2016, 12, 21, 0, 0, 0, 0, time.UTC), 78.22, 15.65)
+	assert.False(t, ok, "SunTimes polar")
+	assert.True(t, night, "SunTimes polar night")
+
+	assert.Equal(t, "N", weather.Compass(355), "Compass")
+	assert.Equal(t, "ENE", weather.Compass(65), "Compass")
+	assert.Equal(t, "W", weather.Compass(270), "Compass")
+	assert.Equal(t, 0, weather.MoonAge(time.Date(2016, 3, 9, 2, 0, 0, 0, time.UTC)), "MoonAge new moon")
+}
+
+func TestOpenMeteo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "48.85", req.URL.Query().Get("latitude"), "latitude")
+		assert.Equal(t, "fahrenheit", req.URL.Query().Get("temperature_unit"), "temperature_unit")
+		rw.Write([]byte(`{
+			"current": {"time": "2016-03-01T22:15", "temperature_2m": 41.2, "apparent_temperature": 36.6,
+				"relative_humidity_2m": 81, "is_day": 0, "weather_code": 61, "pressure_msl": 1015.9,
+				"wind_speed_10m": 9.1, "wind_direction_10m": 225, "visibility": 16093, "uv_index": 0},
+			"daily": {"time": ["2016-03-01", "2016-03-02"], "weather_code": [3, 0],
+				"temperature_2m_max": [48.1, 50.7], "temperature_2m_min": [38.3, 35.4],
+				"sunrise": ["2016-03-01T07:31", "2016-03-02T07:29"], "sunset": ["2016-03-01T18:33", "2016-03-02T18:35"],
+				"precipitation_probability_max": [60, 5], "wind_speed_10m_max": [12.4, 8],
+				"wind_gusts_10m_max": [25, 15], "wind_direction_10m_dominant": [200, 10]}}`))
+	}))
+	defer srv.Close()
+	defer func(url string) { weather.OpenMeteoURLForecast = url }(weather.OpenMeteoURLForecast)
+	weather.OpenMeteoURLForecast = srv.URL
+
+	wea := weather.New(weather.BackendOpenMeteo)
+	assert.True(t, wea.ValidLocation("48.85,2.35"), "ValidLocation")
+	assert.False(t, wea.ValidLocation("FRXX0076"), "ValidLocation weather.com")
+
+	wea.SetConfig(&weather.Config{LocationCode: "48.85,2.35", LocationName: "Paris", NbDays: 1})
+	for e := range wea.Get() {
+		assert.NoError(t, e, "Get")
+	}
+	cur := wea.Current()
+	if !assert.NotNil(t, cur, "Current") {
+		return
+	}
+	assert.Equal(t, "Paris", cur.LocName, "LocName")
+	assert.Equal(t, 41, cur.TempReal, "TempReal")
+	assert.Equal(t, 37, cur.TempFelt, "TempFelt")
+	assert.Equal(t, "°F", cur.UnitTemp, "UnitTemp")
+	assert.Equal(t, "mph", cur.UnitSpeed, "UnitSpeed")
+	assert.Equal(t, "SW", cur.WindDirection, "WindDirection")
+	assert.Equal(t, "10.0", cur.Visibility, "Visibility")
+	assert.Equal(t, "11", cur.WeatherIcon, "WeatherIcon")
+	assert.True(t, cur.IsNight, "IsNight")
+	assert.Equal(t, "6:33 PM", cur.Sunset, "Sunset")
+	assert.Equal(t, "10:15 PM", cur.TxtUpdateTime, "TxtUpdateTime")
+
+	fc := wea.Forecast()
+	if !assert.Len(t, fc.Days, 2, "Days") {
+		return
+	}
+	assert.Equal(t, "Tuesday", fc.Days[0].DayName, "DayName")
+	assert.Equal(t, "1", fc.Days[1].DayCount, "DayCount")
+	assert.Equal(t, "38", fc.Days[0].TempMin, "TempMin")
+	assert.Empty(t, fc.DayPart(0, false).WeatherIcon, "daylight over")
+	assert.Equal(t, "26", fc.DayPart(0, true).WeatherIcon, "night icon")
+	assert.Equal(t, "60", fc.DayPart(0, true).PrecipitationProba, "PrecipitationProba")
+	assert.Equal(t, "32", fc.DayPart(1, false).WeatherIcon, "day icon")
+	assert.Equal(t, "N", fc.DayPart(1, false).WindDirection, "WindDirection")
+}
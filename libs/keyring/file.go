@@ -0,0 +1,219 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Key derivation settings of the encrypted file.
+//
+const (
+	fileVersion    = 1
+	fileIterations = 100000
+	fileSaltSize   = 16
+	fileKeySize    = 32 // AES-256.
+)
+
+// fileLock serializes the file accesses of all keyrings of the process.
+//
+var fileLock sync.Mutex
+
+// fileData is the content of the encrypted file.
+//
+type fileData struct {
+	Version    int
+	Iterations int
+	Salt       []byte
+	Nonce      []byte
+	Data       []byte // Encrypted JSON map of secrets, by key attributes.
+}
+
+// fileStore stores secrets in a file encrypted with AES-GCM, with a key
+// derived from the user passphrase.
+//
+type fileStore struct {
+	file       string
+	passphrase func(confirm bool) (string, error)
+
+	key  []byte // Derived key, cached after the first success.
+	salt []byte // Salt of the cached key.
+}
+
+func (fs *fileStore) get(attrs map[string]string) (string, error) {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+	secrets, _, e := fs.load(false)
+	return secrets[fileKey(attrs)], e
+}
+
+func (fs *fileStore) set(label string, attrs map[string]string, secret string) error {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+	secrets, data, e := fs.load(true)
+	if e != nil {
+		return e
+	}
+	secrets[fileKey(attrs)] = secret
+	return fs.save(secrets, data)
+}
+
+func (fs *fileStore) delete(attrs map[string]string) error {
+	fileLock.Lock()
+	defer fileLock.Unlock()
+	secrets, data, e := fs.load(false)
+	key := fileKey(attrs)
+	if _, ok := secrets[key]; e != nil || !ok {
+		return e
+	}
+	delete(secrets, key)
+	return fs.save(secrets, data)
+}
+
+// load reads and decrypts the secrets file. A missing file has no secrets,
+// and asks the new passphrase twice to create it if needed.
+//
+func (fs *fileStore) load(create bool) (map[string]string, *fileData, error) {
+	secrets := make(map[string]string)
+	raw, e := ioutil.ReadFile(fs.file)
+	if os.IsNotExist(e) {
+		if !create {
+			return secrets, nil, nil
+		}
+		data := &fileData{Version: fileVersion, Iterations: fileIterations, Salt: make([]byte, fileSaltSize)}
+		if _, e = rand.Read(data.Salt); e != nil {
+			return nil, nil, e
+		}
+		_, e = fs.deriveKey(data, true)
+		return secrets, data, e
+	}
+	if e != nil {
+		return nil, nil, e
+	}
+
+	data := &fileData{}
+	if e = json.Unmarshal(raw, data); e != nil {
+		return nil, nil, e
+	}
+	gcm, e := fs.deriveKey(data, false)
+	if e != nil {
+		return nil, nil, e
+	}
+	plain, e := gcm.Open(nil, data.Nonce, data.Data, nil)
+	if e != nil {
+		fs.key = nil // Ask again next time.
+		return nil, nil, ErrBadPassphrase
+	}
+	return secrets, data, json.Unmarshal(plain, &secrets)
+}
+
+// save encrypts and writes the secrets file.
+//
+func (fs *fileStore) save(secrets map[string]string, data *fileData) error {
+	plain, e := json.Marshal(secrets)
+	if e != nil {
+		return e
+	}
+	gcm, e := fs.deriveKey(data, false)
+	if e != nil {
+		return e
+	}
+	data.Nonce = make([]byte, gcm.NonceSize())
+	if _, e = rand.Read(data.Nonce); e != nil {
+		return e
+	}
+	data.Data = gcm.Seal(nil, data.Nonce, plain, nil)
+
+	raw, e := json.Marshal(data)
+	if e != nil {
+		return e
+	}
+	if e = os.MkdirAll(filepath.Dir(fs.file), 0700); e != nil {
+		return e
+	}
+	tmp := fs.file + ".tmp"
+	if e = ioutil.WriteFile(tmp, raw, 0600); e != nil {
+		return e
+	}
+	return os.Rename(tmp, fs.file)
+}
+
+// deriveKey returns the cipher for the file, asking the passphrase if the key
+// isn't known yet. A new passphrase is asked twice.
+//
+func (fs *fileStore) deriveKey(data *fileData, create bool) (cipher.AEAD, error) {
+	if fs.key == nil || string(fs.salt) != string(data.Salt) {
+		if fs.passphrase == nil {
+			return nil, ErrNoPassphrase
+		}
+		pass, e := fs.passphrase(false)
+		if e != nil {
+			return nil, e
+		}
+		if pass == "" {
+			return nil, ErrNoPassphrase
+		}
+		if create {
+			again, e := fs.passphrase(true)
+			if e != nil {
+				return nil, e
+			}
+			if again != pass {
+				return nil, ErrPassphraseMismatch
+			}
+		}
+		fs.key = pbkdf2([]byte(pass), data.Salt, data.Iterations, fileKeySize)
+		fs.salt = data.Salt
+	}
+
+	block, e := aes.NewCipher(fs.key)
+	if e != nil {
+		return nil, e
+	}
+	return cipher.NewGCM(block)
+}
+
+// fileKey returns the secrets map key for the attributes.
+//
+func fileKey(attrs map[string]string) string {
+	list := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return strings.Join(list, "\n")
+}
+
+// pbkdf2 derives a key from the password with PBKDF2-HMAC-SHA256 (RFC 8018).
+//
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.Write(prf, binary.BigEndian, block)
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
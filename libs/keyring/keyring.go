@@ -0,0 +1,169 @@
+// Package keyring stores applets secrets, like passwords.
+//
+// Secrets are saved in the freedesktop Secret Service over D-Bus (GNOME
+// Keyring, KWallet, KeePassXC...). When the service isn't available, they are
+// saved in a file encrypted with a key derived from a user passphrase.
+//
+// Methods can block to ask the passphrase or to unlock the keyring, and must
+// not be called from the applet events loop (Init, click callbacks...). Use
+// them in threaded actions or polling checks.
+//
+package keyring
+
+import (
+	"github.com/godbus/dbus"
+
+	"errors"
+	"sync"
+)
+
+// Application is the application attribute of stored secrets.
+//
+const Application = "cairo-dock"
+
+// FileName defines the name of the encrypted secrets file, in the user
+// applets data dir.
+//
+const FileName = "secrets.enc"
+
+// Backends names.
+//
+const (
+	BackendSecretService = "Secret Service"
+	BackendFile          = "encrypted file"
+)
+
+// Errors.
+var (
+	ErrBadPassphrase      = errors.New("keyring: bad passphrase")
+	ErrNoPassphrase       = errors.New("keyring: no passphrase provided")
+	ErrPassphraseMismatch = errors.New("keyring: passphrases don't match")
+	ErrDismissed          = errors.New("keyring: prompt dismissed")
+)
+
+// Keyring stores the secrets of an applet. Implements cdtype.AppCredentials.
+//
+type Keyring struct {
+	Applet     string                             // Applet name, to separate secrets.
+	File       string                             // Encrypted file location, used without Secret Service.
+	Passphrase func(confirm bool) (string, error) // Asks the user passphrase of the file, again to confirm a new one.
+	Bus        func() (*dbus.Conn, error)         // Session bus connection. Default is dbus.SessionBus.
+
+	mu      sync.Mutex
+	backend store // Selected on first use, the Secret Service is probed again when the file has no match.
+}
+
+// store defines a secrets storage backend.
+//
+type store interface {
+	get(attrs map[string]string) (string, error)
+	set(label string, attrs map[string]string, secret string) error
+	delete(attrs map[string]string) error
+}
+
+// New creates a keyring for the applet.
+//
+func New(applet, file string, passphrase func(confirm bool) (string, error)) *Keyring {
+	return &Keyring{
+		Applet:     applet,
+		File:       file,
+		Passphrase: passphrase,
+		Bus:        dbus.SessionBus,
+	}
+}
+
+// Get returns the secret saved for the key. Empty if not found.
+//
+// When the file has no match, the Secret Service is probed again, as it may
+// have been started after the first use.
+//
+func (kr *Keyring) Get(key string) (string, error) {
+	attrs := kr.attributes(key)
+	st := kr.store()
+	secret, e := st.get(attrs)
+	if _, isFile := st.(*fileStore); isFile && e == nil && secret == "" {
+		if ss := kr.probe(); ss != nil {
+			return ss.get(attrs)
+		}
+	}
+	return secret, e
+}
+
+// Set saves the secret for the key, replacing the previous one.
+//
+func (kr *Keyring) Set(key, secret string) error {
+	return kr.store().set(Application+" "+kr.Applet+" "+key, kr.attributes(key), secret)
+}
+
+// Delete removes the secret saved for the key, if any.
+//
+func (kr *Keyring) Delete(key string) error {
+	return kr.store().delete(kr.attributes(key))
+}
+
+// Backend returns the name of the storage used.
+//
+func (kr *Keyring) Backend() string {
+	if _, ok := kr.store().(*secretService); ok {
+		return BackendSecretService
+	}
+	return BackendFile
+}
+
+// store returns the storage backend: the Secret Service if it answers, or the
+// encrypted file.
+//
+func (kr *Keyring) store() store {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if kr.backend != nil {
+		return kr.backend
+	}
+
+	if ss := kr.openSecretService(); ss != nil {
+		kr.backend = ss
+		return ss
+	}
+	kr.backend = &fileStore{file: kr.File, passphrase: kr.Passphrase}
+	return kr.backend
+}
+
+// probe switches to the Secret Service if it answers now. Returns nil if not.
+//
+func (kr *Keyring) probe() store {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	ss := kr.openSecretService()
+	if ss == nil {
+		return nil
+	}
+	kr.backend = ss
+	return ss
+}
+
+// openSecretService returns the Secret Service, or nil if it doesn't answer.
+//
+func (kr *Keyring) openSecretService() *secretService {
+	if kr.Bus == nil {
+		return nil
+	}
+	conn, e := kr.Bus()
+	if e != nil {
+		return nil
+	}
+	ss, e := openSecretService(conn)
+	if e != nil {
+		return nil
+	}
+	return ss
+}
+
+// attributes returns the lookup attributes of the key.
+//
+func (kr *Keyring) attributes(key string) map[string]string {
+	return map[string]string{
+		"application": Application,
+		"applet":      kr.Applet,
+		"key":         key,
+	}
+}
@@ -0,0 +1,351 @@
+package keyring
+
+import (
+	"github.com/godbus/dbus"
+	"github.com/stretchr/testify/assert"
+
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPBKDF2(t *testing.T) { // RFC 7914 test vector.
+	key := pbkdf2([]byte("passwd"), []byte("salt"), 1, 64)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"+
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783", hex.EncodeToString(key), "pbkdf2")
+}
+
+func TestFile(t *testing.T) {
+	dir, e := ioutil.TempDir("", "keyring")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "sub", FileName)
+
+	asked := 0
+	pass := "first"
+	noBus := func() (*dbus.Conn, error) { return nil, errors.New("no bus") }
+	kr := New("GoGmail", file, func(bool) (string, error) { asked++; return pass, nil })
+	kr.Bus = noBus
+
+	assert.Equal(t, BackendFile, kr.Backend(), "backend")
+	secret, e := kr.Get("login")
+	assert.NoError(t, e, "Get missing file")
+	assert.Empty(t, secret, "Get missing file")
+	assert.Equal(t, 0, asked, "no passphrase to read a missing file")
+
+	assert.NoError(t, kr.Set("login", "me:secret"), "Set")
+	assert.NoError(t, kr.Set("other", "value"), "Set")
+	secret, e = kr.Get("login")
+	assert.NoError(t, e, "Get")
+	assert.Equal(t, "me:secret", secret, "Get")
+	assert.Equal(t, 2, asked, "passphrase confirmed and cached")
+
+	raw, _ := ioutil.ReadFile(file)
+	assert.NotContains(t, string(raw), "secret", "encrypted")
+	info, e := os.Stat(file)
+	if assert.NoError(t, e, "Stat") {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "file mode")
+	}
+
+	// Same file, other applet.
+	other := New("Other", file, func(bool) (string, error) { return "first", nil })
+	other.Bus = noBus
+	secret, _ = other.Get("login")
+	assert.Empty(t, secret, "applets separated")
+
+	// Wrong passphrase.
+	pass = "wrong"
+	bad := New("GoGmail", file, func(bool) (string, error) { asked++; return pass, nil })
+	bad.Bus = noBus
+	_, e = bad.Get("login")
+	assert.Equal(t, ErrBadPassphrase, e, "bad passphrase")
+	pass = "first"
+	secret, e = bad.Get("login")
+	assert.NoError(t, e, "passphrase asked again")
+	assert.Equal(t, "me:secret", secret, "passphrase asked again")
+
+	assert.NoError(t, kr.Delete("login"), "Delete")
+	secret, _ = bad.Get("login")
+	assert.Empty(t, secret, "deleted")
+	secret, _ = bad.Get("other")
+	assert.Equal(t, "value", secret, "other key kept")
+
+	cancel := New("GoGmail", filepath.Join(dir, "new.enc"), func(bool) (string, error) { return "", nil })
+	cancel.Bus = noBus
+	assert.Equal(t, ErrNoPassphrase, cancel.Set("login", "x"), "no passphrase")
+
+	typo := New("GoGmail", filepath.Join(dir, "typo.enc"), func(confirm bool) (string, error) {
+		if confirm {
+			return "secondd", nil
+		}
+		return "second", nil
+	})
+	typo.Bus = noBus
+	assert.Equal(t, ErrPassphraseMismatch, typo.Set("login", "x"), "passphrase not confirmed")
+	_, e = os.Stat(filepath.Join(dir, "typo.enc"))
+	assert.True(t, os.IsNotExist(e), "file not created")
+}
+
+//
+//----------------------------------------------------------[ FAKE SERVICE ]--
+
+// startBus starts a private session bus. Skips the test if dbus-daemon isn't
+// available.
+//
+func startBus(t *testing.T) (string, func()) {
+	if _, e := exec.LookPath("dbus-daemon"); e != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	dir, _ := ioutil.TempDir("", "keyring-bus")
+	conf := filepath.Join(dir, "bus.conf")
+	ioutil.WriteFile(conf, []byte(`<busconfig>
+  <type>session</type>
+  <listen>unix:path=`+filepath.Join(dir, "bus")+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`), 0600)
+
+	cmd := exec.Command("dbus-daemon", "--config-file="+conf, "--nofork", "--print-address=1")
+	out, _ := cmd.StdoutPipe()
+	if e := cmd.Start(); e != nil {
+		os.RemoveAll(dir)
+		t.Skip("dbus-daemon start:", e)
+	}
+	address, _ := bufio.NewReader(out).ReadString('\n')
+	return strings.TrimSpace(address), func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+}
+
+// dial opens a connection to the private bus.
+//
+func dial(address string) (*dbus.Conn, error) {
+	conn, e := dbus.Dial(address)
+	if e != nil {
+		return nil, e
+	}
+	if e = conn.Auth(nil); e != nil {
+		conn.Close()
+		return nil, e
+	}
+	if e = conn.Hello(); e != nil {
+		conn.Close()
+		return nil, e
+	}
+	return conn, nil
+}
+
+// fakeService is a minimal Secret Service. Created items are locked, and
+// unlocked with a prompt.
+//
+type fakeService struct {
+	conn    *dbus.Conn
+	mu      sync.Mutex
+	items   map[dbus.ObjectPath]*fakeItem
+	count   int
+	prompts int
+	dismiss bool // Prompts answer.
+}
+
+type fakeItem struct {
+	path   dbus.ObjectPath
+	srv    *fakeService
+	attrs  map[string]string
+	secret []byte
+	locked bool
+}
+
+func (fs *fakeService) OpenSession(algo string, input dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	if algo != "plain" {
+		return dbus.MakeVariant(""), "/", dbus.NewError("org.freedesktop.DBus.Error.NotSupported", nil)
+	}
+	return dbus.MakeVariant(""), "/org/freedesktop/secrets/session/1", nil
+}
+
+func (fs *fakeService) SearchItems(attrs map[string]string) (unlocked, locked []dbus.ObjectPath, err *dbus.Error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for path, item := range fs.items {
+		match := true
+		for k, v := range attrs {
+			match = match && item.attrs[k] == v
+		}
+		switch {
+		case !match:
+		case item.locked:
+			locked = append(locked, path)
+		default:
+			unlocked = append(unlocked, path)
+		}
+	}
+	return unlocked, locked, nil
+}
+
+func (fs *fakeService) Unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	var needPrompt bool
+	for _, path := range objects {
+		if item, ok := fs.items[path]; ok && item.locked {
+			needPrompt = true
+		}
+	}
+	if !needPrompt {
+		return objects, ssNoPrompt, nil
+	}
+
+	fs.prompts++
+	path := dbus.ObjectPath("/org/freedesktop/secrets/prompt/" + strconv.Itoa(fs.prompts))
+	fs.conn.ExportMethodTable(map[string]interface{}{
+		"Prompt": func(windowID string) *dbus.Error {
+			fs.mu.Lock()
+			dismissed := fs.dismiss
+			if !dismissed {
+				for _, obj := range objects {
+					fs.items[obj].locked = false
+				}
+			}
+			fs.mu.Unlock()
+			go fs.conn.Emit(path, ssIfacePrompt+".Completed", dismissed, dbus.MakeVariant(objects))
+			return nil
+		},
+	}, path, ssIfacePrompt)
+	return nil, path, nil
+}
+
+func (fs *fakeService) CreateItem(props map[string]dbus.Variant, secret ssSecret, replace bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	attrs, _ := props[ssPropAttributes].Value().(map[string]string)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if replace {
+		for path, item := range fs.items {
+			if fileKey(item.attrs) == fileKey(attrs) {
+				item.secret = secret.Value
+				return path, ssNoPrompt, nil
+			}
+		}
+	}
+
+	fs.count++
+	item := &fakeItem{
+		path:   dbus.ObjectPath("/org/freedesktop/secrets/collection/login/" + strconv.Itoa(fs.count)),
+		srv:    fs,
+		attrs:  attrs,
+		secret: secret.Value,
+		locked: true,
+	}
+	fs.items[item.path] = item
+	fs.conn.Export(item, item.path, ssIfaceItem)
+	return item.path, ssNoPrompt, nil
+}
+
+func (item *fakeItem) GetSecret(session dbus.ObjectPath) (ssSecret, *dbus.Error) {
+	item.srv.mu.Lock()
+	defer item.srv.mu.Unlock()
+	if item.locked {
+		return ssSecret{}, dbus.NewError("org.freedesktop.Secret.Error.IsLocked", nil)
+	}
+	return ssSecret{Session: session, Value: item.secret, ContentType: "text/plain"}, nil
+}
+
+func (item *fakeItem) Delete() (dbus.ObjectPath, *dbus.Error) {
+	item.srv.mu.Lock()
+	defer item.srv.mu.Unlock()
+	delete(item.srv.items, item.path)
+	return ssNoPrompt, nil
+}
+
+// state returns the prompts and items counts.
+//
+func (fs *fakeService) state() (prompts, items int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.prompts, len(fs.items)
+}
+
+func (fs *fakeService) setDismiss(dismiss bool) {
+	fs.mu.Lock()
+	fs.dismiss = dismiss
+	fs.mu.Unlock()
+}
+
+func TestSecretService(t *testing.T) {
+	address, stop := startBus(t)
+	defer stop()
+
+	srvConn, e := dial(address)
+	if !assert.NoError(t, e, "dial service") {
+		return
+	}
+	defer srvConn.Close()
+	fake := &fakeService{conn: srvConn, items: make(map[dbus.ObjectPath]*fakeItem)}
+	srvConn.Export(fake, ssPath, ssIfaceService)
+	srvConn.Export(fake, ssDefault, ssIfaceColl)
+	_, e = srvConn.RequestName(ssName, dbus.NameFlagDoNotQueue)
+	assert.NoError(t, e, "RequestName")
+
+	newKeyring := func(applet string) *Keyring {
+		kr := New(applet, "", nil)
+		kr.Bus = func() (*dbus.Conn, error) { return dial(address) }
+		return kr
+	}
+	kr := newKeyring("GoGmail")
+	assert.Equal(t, BackendSecretService, kr.Backend(), "backend")
+
+	secret, e := kr.Get("login")
+	assert.NoError(t, e, "Get missing")
+	assert.Empty(t, secret, "Get missing")
+
+	assert.NoError(t, kr.Set("login", "me:secret"), "Set")
+	secret, e = kr.Get("login")
+	assert.NoError(t, e, "Get locked")
+	assert.Equal(t, "me:secret", secret, "Get locked")
+	prompts, _ := fake.state()
+	assert.Equal(t, 1, prompts, "unlock prompt")
+
+	assert.NoError(t, kr.Set("login", "me:changed"), "Set replace")
+	secret, _ = kr.Get("login")
+	assert.Equal(t, "me:changed", secret, "Set replace")
+	_, items := fake.state()
+	assert.Equal(t, 1, items, "replaced")
+
+	secret, _ = newKeyring("Other").Get("login")
+	assert.Empty(t, secret, "applets separated")
+
+	fake.setDismiss(true)
+	assert.NoError(t, kr.Set("other", "value"), "Set other")
+	_, e = kr.Get("other")
+	assert.Equal(t, ErrDismissed, e, "prompt dismissed")
+
+	assert.NoError(t, kr.Delete("login"), "Delete")
+	secret, _ = kr.Get("login")
+	assert.Empty(t, secret, "deleted")
+
+	// Service started after the first use.
+	fake.setDismiss(false)
+	assert.NoError(t, kr.Set("late", "value"), "Set late")
+	late := New("GoGmail", filepath.Join(os.TempDir(), "keyring-missing.enc"), nil)
+	late.Bus = func() (*dbus.Conn, error) { return nil, errors.New("no bus") }
+	assert.Equal(t, BackendFile, late.Backend(), "backend before")
+	late.Bus = func() (*dbus.Conn, error) { return dial(address) }
+	secret, e = late.Get("late")
+	assert.NoError(t, e, "Get probed")
+	assert.Equal(t, "value", secret, "Get probed")
+	assert.Equal(t, BackendSecretService, late.Backend(), "backend after")
+}
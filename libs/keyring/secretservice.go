@@ -0,0 +1,173 @@
+package keyring
+
+import (
+	"github.com/godbus/dbus"
+
+	"errors"
+	"time"
+)
+
+// Secret Service D-Bus names.
+//
+const (
+	ssName           = "org.freedesktop.secrets"
+	ssPath           = "/org/freedesktop/secrets"
+	ssDefault        = "/org/freedesktop/secrets/aliases/default"
+	ssIfaceService   = "org.freedesktop.Secret.Service"
+	ssIfaceColl      = "org.freedesktop.Secret.Collection"
+	ssIfaceItem      = "org.freedesktop.Secret.Item"
+	ssIfacePrompt    = "org.freedesktop.Secret.Prompt"
+	ssPropLabel      = "org.freedesktop.Secret.Item.Label"
+	ssPropAttributes = "org.freedesktop.Secret.Item.Attributes"
+	ssNoPrompt       = "/"
+)
+
+// PromptTimeout is the maximum time to wait for the user to answer a Secret
+// Service prompt, like unlocking the keyring.
+//
+var PromptTimeout = 5 * time.Minute
+
+// ssSecret is a secret as transferred by the Secret Service.
+//
+type ssSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretService stores secrets in the freedesktop Secret Service.
+//
+type secretService struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+// openSecretService opens a session with the Secret Service. Secrets are sent
+// in plain over the session bus, like most clients do.
+//
+func openSecretService(conn *dbus.Conn) (*secretService, error) {
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	e := conn.Object(ssName, ssPath).
+		Call(ssIfaceService+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+	if e != nil {
+		return nil, e
+	}
+	return &secretService{conn: conn, session: session}, nil
+}
+
+func (ss *secretService) get(attrs map[string]string) (string, error) {
+	item, e := ss.search(attrs)
+	if e != nil || item == "" {
+		return "", e
+	}
+	var secret ssSecret
+	e = ss.conn.Object(ssName, item).Call(ssIfaceItem+".GetSecret", 0, ss.session).Store(&secret)
+	return string(secret.Value), e
+}
+
+func (ss *secretService) set(label string, attrs map[string]string, secret string) error {
+	if e := ss.unlock(ssDefault); e != nil {
+		return e
+	}
+	props := map[string]dbus.Variant{
+		ssPropLabel:      dbus.MakeVariant(label),
+		ssPropAttributes: dbus.MakeVariant(attrs),
+	}
+	value := ssSecret{
+		Session:     ss.session,
+		Value:       []byte(secret),
+		ContentType: "text/plain; charset=utf8",
+	}
+	var item, prompt dbus.ObjectPath
+	e := ss.conn.Object(ssName, ssDefault).Call(ssIfaceColl+".CreateItem", 0, props, value, true).Store(&item, &prompt)
+	if e != nil {
+		return e
+	}
+	return ss.prompt(prompt)
+}
+
+func (ss *secretService) delete(attrs map[string]string) error {
+	item, e := ss.search(attrs)
+	if e != nil || item == "" {
+		return e
+	}
+	var prompt dbus.ObjectPath
+	e = ss.conn.Object(ssName, item).Call(ssIfaceItem+".Delete", 0).Store(&prompt)
+	if e != nil {
+		return e
+	}
+	return ss.prompt(prompt)
+}
+
+// search returns the first item matching the attributes, unlocked if needed.
+// Empty if not found.
+//
+func (ss *secretService) search(attrs map[string]string) (dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	e := ss.conn.Object(ssName, ssPath).Call(ssIfaceService+".SearchItems", 0, attrs).Store(&unlocked, &locked)
+	switch {
+	case e != nil:
+		return "", e
+
+	case len(unlocked) > 0:
+		return unlocked[0], nil
+
+	case len(locked) > 0:
+		return locked[0], ss.unlock(locked[0])
+	}
+	return "", nil
+}
+
+// unlock unlocks the item or collection, with a user prompt if needed.
+//
+func (ss *secretService) unlock(path dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	e := ss.conn.Object(ssName, ssPath).Call(ssIfaceService+".Unlock", 0, []dbus.ObjectPath{path}).Store(&unlocked, &prompt)
+	if e != nil {
+		return e
+	}
+	return ss.prompt(prompt)
+}
+
+// prompt shows the Secret Service prompt if any, and waits for its completion.
+//
+func (ss *secretService) prompt(path dbus.ObjectPath) error {
+	if path == ssNoPrompt || path == "" {
+		return nil
+	}
+
+	match := "type='signal',interface='" + ssIfacePrompt + "',member='Completed',path='" + string(path) + "'"
+	if e := ss.conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, match).Err; e != nil {
+		return e
+	}
+	defer ss.conn.BusObject().Call("org.freedesktop.DBus.RemoveMatch", 0, match)
+
+	signals := make(chan *dbus.Signal, 10)
+	ss.conn.Signal(signals)
+	defer ss.conn.RemoveSignal(signals)
+
+	if e := ss.conn.Object(ssName, path).Call(ssIfacePrompt+".Prompt", 0, "").Err; e != nil {
+		return e
+	}
+
+	timeout := time.After(PromptTimeout)
+	for {
+		select {
+		case sig := <-signals:
+			if sig.Path != path || sig.Name != ssIfacePrompt+".Completed" || len(sig.Body) == 0 {
+				continue
+			}
+			if dismissed, _ := sig.Body[0].(bool); dismissed {
+				return ErrDismissed
+			}
+			return nil
+
+		case <-timeout:
+			return errors.New("keyring: prompt timeout")
+		}
+	}
+}
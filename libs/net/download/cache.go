@@ -0,0 +1,162 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheDirName is the name of the cache dir, in the user cache location.
+const CacheDirName = "cairo-dock/http"
+
+// UserCacheDir returns the default cache location: in $XDG_CACHE_HOME or
+// ~/.cache. Empty if not found.
+//
+func UserCacheDir() string {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		usr, e := user.Current()
+		if e != nil || usr.HomeDir == "" {
+			return ""
+		}
+		base = filepath.Join(usr.HomeDir, ".cache")
+	}
+	return filepath.Join(base, filepath.FromSlash(CacheDirName))
+}
+
+// ClearCache removes all cached answers.
+//
+func (c *Client) ClearCache() error {
+	if c.CacheDir == "" {
+		return nil
+	}
+	return os.RemoveAll(c.CacheDir)
+}
+
+// cacheMeta defines the validators of a cached answer.
+//
+type cacheMeta struct {
+	URL          string
+	ETag         string
+	LastModified string
+	Saved        time.Time
+}
+
+// cacheEntry is a cached answer, stored in two files named by the hash of the
+// request url and headers: the body and its meta data.
+//
+type cacheEntry struct {
+	uri  string
+	file string     // Without extension.
+	meta *cacheMeta // Set when the request was made conditional.
+}
+
+// cacheEntry returns the cache entry for the url, or nil if the cache is
+// disabled or the request is authenticated: private data is never stored.
+//
+func (c *Client) cacheEntry(uri string) *cacheEntry {
+	if c.CacheDir == "" {
+		return nil
+	}
+
+	// Headers are part of the key: answers may depend on them.
+	keys := make([]string, 0, len(c.Header))
+	for k := range c.Header {
+		if http.CanonicalHeaderKey(k) == "Authorization" {
+			return nil
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	io.WriteString(hash, uri)
+	for _, k := range keys {
+		io.WriteString(hash, "\n"+k+": "+c.Header[k])
+	}
+	return &cacheEntry{uri: uri, file: filepath.Join(c.CacheDir, hex.EncodeToString(hash.Sum(nil)))}
+}
+
+// prepare makes the request conditional if the answer is in cache.
+//
+func (ce *cacheEntry) prepare(req *http.Request) {
+	data, e := ioutil.ReadFile(ce.file + ".json")
+	if e != nil {
+		return
+	}
+	meta := &cacheMeta{}
+	if json.Unmarshal(data, meta) != nil || meta.URL != ce.uri {
+		return
+	}
+	if _, e := os.Stat(ce.file + ".body"); e != nil {
+		return
+	}
+
+	ce.meta = meta
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+}
+
+// load returns the cached body.
+//
+func (ce *cacheEntry) load() ([]byte, error) {
+	return ioutil.ReadFile(ce.file + ".body")
+}
+
+// save stores the answer if it has validators, unless the server forbids it
+// with Cache-Control no-store or private. The cache is best effort, errors
+// are ignored.
+//
+func (ce *cacheEntry) save(resp *http.Response, body []byte) {
+	meta := cacheMeta{
+		URL:          ce.uri,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Saved:        time.Now(),
+	}
+	control := cacheControl(resp.Header)
+	if (meta.ETag == "" && meta.LastModified == "") || control["no-store"] || control["private"] {
+		if ce.meta != nil { // Drop the outdated answer.
+			os.Remove(ce.file + ".json")
+			os.Remove(ce.file + ".body")
+		}
+		return
+	}
+
+	data, e := json.Marshal(meta)
+	if e != nil || os.MkdirAll(filepath.Dir(ce.file), 0700) != nil {
+		return
+	}
+	if ioutil.WriteFile(ce.file+".body", body, 0600) == nil {
+		ioutil.WriteFile(ce.file+".json", data, 0600)
+	}
+}
+
+// cacheControl returns the Cache-Control directives of the answer, lowercase
+// and without their value.
+//
+func cacheControl(header http.Header) map[string]bool {
+	directives := make(map[string]bool)
+	for _, line := range header["Cache-Control"] {
+		for _, token := range strings.Split(line, ",") {
+			if i := strings.Index(token, "="); i >= 0 {
+				token = token[:i]
+			}
+			directives[strings.ToLower(strings.TrimSpace(token))] = true
+		}
+	}
+	return directives
+}
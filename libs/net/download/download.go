@@ -1,12 +1,21 @@
 // Package download gets content from internet.
+//
+// Requests use a shared client with timeouts, retries with exponential
+// backoff, conditional requests cached on disk (ETag and Last-Modified), and
+// explicit proxy settings.
 package download
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // Get gets data from url.
@@ -33,52 +42,228 @@ func JSON(uri string, data interface{}) error {
 	return Default.JSON(uri, data)
 }
 
-// Default defines default settings for the package.
+// Default is the shared client used by the package functions and headers.
+//
+var Default = NewClient()
+
+func init() {
+	Default.CacheDir = UserCacheDir()
+}
+
 //
-var Default = Header{}
+//------------------------------------------------------------------[ HEADER ]--
 
-// Header represents a HTTP downloader with custom header settings.
+// Header represents a HTTP downloader with custom header settings, using the
+// Default client.
 //
 type Header map[string]string
 
 // Reader gets a reader for the url.
 //
 func (h Header) Reader(uri string) (io.ReadCloser, error) {
-	request, e := http.NewRequest("GET", uri, nil)
-	if e != nil {
-		return nil, e
-	}
-	for k, v := range h {
-		request.Header.Add(k, v)
+	return Default.WithHeader(h).Reader(uri)
+}
+
+// Get gets data from url.
+//
+func (h Header) Get(uri string) ([]byte, error) {
+	return Default.WithHeader(h).Get(uri)
+}
+
+// XML gets data from url and unmarshal as XML to data.
+//
+func (h Header) XML(uri string, data interface{}) error {
+	return Default.WithHeader(h).XML(uri, data)
+}
+
+// JSON gets data from url and unmarshal as JSON to data.
+//
+func (h Header) JSON(uri string, data interface{}) error {
+	return Default.WithHeader(h).JSON(uri, data)
+}
+
+//
+//------------------------------------------------------------------[ CLIENT ]--
+
+// Client defaults.
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultRetries    = 2
+	DefaultBackoff    = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// ProxyDirect disables the proxy, even if set in the environment.
+const ProxyDirect = "direct"
+
+// StatusError is returned when the server answered with an error status.
+//
+type StatusError struct {
+	URL    string
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return "download " + e.URL + ": " + e.Status
+}
+
+// Client defines a HTTP downloader.
+//
+// Settings must not be changed while requests are running. Use the With
+// methods to get a modified copy for some calls.
+//
+type Client struct {
+	Header     Header        // Headers added to requests.
+	Timeout    time.Duration // Request timeout. For Reader, only until the answer headers.
+	Retries    int           // Retries on network errors, server errors and 429 answers.
+	Backoff    time.Duration // Delay before the first retry, doubled for each retry.
+	MaxBackoff time.Duration // Maximum delay between retries.
+	CacheDir   string        // Cache location for conditional requests. Disabled if empty.
+
+	transport *http.Transport
+	sleep     func(time.Duration) // Overridden in tests.
+}
+
+// NewClient creates a HTTP downloader with default settings, without cache
+// and with the proxy from the environment.
+//
+func NewClient() *Client {
+	c := &Client{
+		Timeout:    DefaultTimeout,
+		Retries:    DefaultRetries,
+		Backoff:    DefaultBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		sleep:      time.Sleep,
 	}
+	c.SetProxy("")
+	return c
+}
 
-	// Try to get data from source.
-	resp, e := new(http.Client).Do(request)
-	if e != nil {
-		return nil, e
+// SetProxy sets the proxy URL. An empty value uses the environment settings
+// (HTTP_PROXY, HTTPS_PROXY and NO_PROXY), and ProxyDirect disables the proxy.
+//
+func (c *Client) SetProxy(proxy string) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch proxy {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+
+	case ProxyDirect:
+		transport.Proxy = nil
+
+	default:
+		proxyURL, e := url.Parse(proxy)
+		if e != nil {
+			return e
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
 	}
-	return resp.Body, nil
+	c.transport = transport
+	return nil
 }
 
-// Get gets data from url.
+// WithTimeout returns a copy of the client with a new timeout.
 //
-func (h Header) Get(uri string) ([]byte, error) {
-	reader, e := h.Reader(uri)
-	if e != nil {
-		return nil, e
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	cp := *c
+	cp.Timeout = timeout
+	return &cp
+}
+
+// WithRetries returns a copy of the client with a new number of retries.
+//
+func (c *Client) WithRetries(retries int) *Client {
+	cp := *c
+	cp.Retries = retries
+	return &cp
+}
+
+// WithHeader returns a copy of the client with headers added.
+//
+func (c *Client) WithHeader(header Header) *Client {
+	cp := *c
+	cp.Header = make(Header, len(c.Header)+len(header))
+	for k, v := range c.Header {
+		cp.Header[k] = v
 	}
-	body, e := ioutil.ReadAll(reader)
-	reader.Close()
-	if e != nil {
-		return nil, e
+	for k, v := range header {
+		cp.Header[k] = v
 	}
+	return &cp
+}
+
+// Get gets data from url.
+//
+// The answer is cached when the server provides an ETag or Last-Modified
+// header, and the next requests are conditional. Authenticated requests and
+// answers marked no-store or private aren't cached.
+//
+func (c *Client) Get(uri string) ([]byte, error) {
+	return c.GetContext(context.Background(), uri)
+}
+
+// GetContext gets data from url, and can be canceled with the context.
+//
+func (c *Client) GetContext(ctx context.Context, uri string) ([]byte, error) {
+	cache := c.cacheEntry(uri)
+	var body []byte
+	e := c.retry(ctx, func() error {
+		reqCtx, cancel := c.withTimeout(ctx)
+		defer cancel()
+
+		resp, e := c.do(reqCtx, uri, cache)
+		if e != nil {
+			return e
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotModified && cache != nil && cache.meta != nil {
+			body, e = cache.load()
+			return e
+		}
+		if e = checkStatus(uri, resp); e != nil {
+			return e
+		}
+
+		body, e = ioutil.ReadAll(resp.Body)
+		if e == nil && cache != nil {
+			cache.save(resp, body)
+		}
+		return e
+	})
+	return body, e
+}
+
+// Reader gets a reader for the url. The answer is not cached, and the timeout
+// only applies until the answer headers are received.
+//
+func (c *Client) Reader(uri string) (io.ReadCloser, error) {
+	var body io.ReadCloser
+	e := c.retry(context.Background(), func() error {
+		ctx, cancel := context.WithCancel(context.Background())
+		timer := time.AfterFunc(c.timeout(), cancel)
+		resp, e := c.do(ctx, uri, nil)
+		timer.Stop()
+		if e != nil {
+			cancel()
+			return e
+		}
+		if e = checkStatus(uri, resp); e != nil {
+			resp.Body.Close()
+			cancel()
+			return e
+		}
+		body = &cancelCloser{ReadCloser: resp.Body, cancel: cancel}
+		return nil
+	})
 	return body, e
 }
 
 // XML gets data from url and unmarshal as XML to data.
 //
-func (h Header) XML(uri string, data interface{}) error {
-	body, e := h.Get(uri)
+func (c *Client) XML(uri string, data interface{}) error {
+	body, e := c.Get(uri)
 	if e != nil {
 		return e
 	}
@@ -87,10 +272,133 @@ func (h Header) XML(uri string, data interface{}) error {
 
 // JSON gets data from url and unmarshal as JSON to data.
 //
-func (h Header) JSON(uri string, data interface{}) error {
-	body, e := h.Get(uri)
+func (c *Client) JSON(uri string, data interface{}) error {
+	body, e := c.Get(uri)
 	if e != nil {
 		return e
 	}
 	return json.Unmarshal(body, data)
 }
+
+//
+//-----------------------------------------------------------------[ REQUEST ]--
+
+// do sends a GET request, conditional if a cache entry is provided.
+//
+func (c *Client) do(ctx context.Context, uri string, cache *cacheEntry) (*http.Response, error) {
+	req, e := http.NewRequest("GET", uri, nil)
+	if e != nil {
+		return nil, permanent{e}
+	}
+	req = req.WithContext(ctx)
+	for k, v := range c.Header {
+		req.Header.Add(k, v)
+	}
+	if cache != nil {
+		cache.prepare(req)
+	}
+	return (&http.Client{Transport: c.transport}).Do(req)
+}
+
+// retry calls the request until success, a permanent error or the number of
+// retries is reached.
+//
+func (c *Client) retry(ctx context.Context, call func() error) error {
+	delay := c.Backoff
+	for try := 0; ; try++ {
+		e := call()
+		if e == nil || try >= c.Retries || ctx.Err() != nil {
+			return unwrap(e)
+		}
+
+		wait, ok := retryDelay(e)
+		if !ok {
+			return unwrap(e)
+		}
+		if wait == 0 {
+			wait = delay + time.Duration(rand.Int63n(int64(delay)/2+1)) // With jitter.
+			delay *= 2
+		}
+		if c.MaxBackoff > 0 && wait > c.MaxBackoff {
+			wait = c.MaxBackoff
+		}
+
+		sleep := c.sleep
+		if sleep == nil {
+			sleep = time.Sleep
+		}
+		sleep(wait)
+	}
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+	return c.Timeout
+}
+
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout())
+}
+
+// checkStatus returns an error if the answer status isn't a success.
+//
+func checkStatus(uri string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	e := &StatusError{URL: uri, Code: resp.StatusCode, Status: resp.Status}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return retryAfter{e, time.Duration(seconds) * time.Second}
+	}
+	return permanent{e}
+}
+
+// permanent wraps errors that must not be retried.
+type permanent struct{ error }
+
+// retryAfter wraps errors with a delay requested by the server.
+type retryAfter struct {
+	error
+	wait time.Duration
+}
+
+// retryDelay returns whether the error can be retried, and the delay
+// requested by the server, if any.
+//
+func retryDelay(e error) (time.Duration, bool) {
+	switch err := e.(type) {
+	case permanent:
+		return 0, false
+
+	case retryAfter:
+		return err.wait, true
+	}
+	return 0, true // Network errors.
+}
+
+func unwrap(e error) error {
+	switch err := e.(type) {
+	case permanent:
+		return err.error
+
+	case retryAfter:
+		return err.error
+	}
+	return e
+}
+
+// cancelCloser releases the request context when the body is closed.
+//
+type cancelCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (cc *cancelCloser) Close() error {
+	e := cc.ReadCloser.Close()
+	cc.cancel()
+	return e
+}
@@ -0,0 +1,150 @@
+package download
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testClient returns a client with a cache in a temp dir, and recorded sleeps.
+func testClient(t *testing.T) (*Client, *[]time.Duration, func()) {
+	dir, e := ioutil.TempDir("", "download")
+	if !assert.NoError(t, e, "TempDir") {
+		t.FailNow()
+	}
+	var sleeps []time.Duration
+	c := NewClient()
+	c.CacheDir = dir
+	c.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	return c, &sleeps, func() { os.RemoveAll(dir) }
+}
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/flaky":
+			if atomic.AddInt32(&calls, 1) < 3 {
+				http.Error(rw, "busy", http.StatusServiceUnavailable)
+				return
+			}
+			rw.Write([]byte("ok"))
+
+		case "/limited":
+			rw.Header().Set("Retry-After", "7")
+			http.Error(rw, "slow down", http.StatusTooManyRequests)
+
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			rw.Write([]byte("late"))
+
+		default:
+			http.NotFound(rw, req)
+		}
+	}))
+	defer srv.Close()
+	c, sleeps, clean := testClient(t)
+	defer clean()
+
+	body, e := c.Get(srv.URL + "/flaky")
+	assert.NoError(t, e, "flaky")
+	assert.Equal(t, "ok", string(body), "flaky body")
+	if assert.Len(t, *sleeps, 2, "flaky retries") {
+		assert.True(t, (*sleeps)[1] >= 2*DefaultBackoff, "backoff doubled")
+	}
+
+	*sleeps = nil
+	_, e = c.Get(srv.URL + "/missing")
+	if assert.Error(t, e, "missing") {
+		assert.Equal(t, http.StatusNotFound, e.(*StatusError).Code, "missing code")
+	}
+	assert.Empty(t, *sleeps, "missing not retried")
+
+	_, e = c.Get(srv.URL + "/limited")
+	assert.Error(t, e, "limited")
+	assert.Equal(t, []time.Duration{7 * time.Second, 7 * time.Second}, *sleeps, "Retry-After")
+
+	_, e = c.WithTimeout(50 * time.Millisecond).WithRetries(0).Get(srv.URL + "/slow")
+	assert.Error(t, e, "timeout")
+
+	reader, e := c.Reader(srv.URL + "/slow")
+	if assert.NoError(t, e, "Reader") {
+		body, _ = ioutil.ReadAll(reader)
+		reader.Close()
+		assert.Equal(t, "late", string(body), "Reader body")
+	}
+}
+
+func TestCache(t *testing.T) {
+	var full, notModified int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		etag := `"v1-` + req.Header.Get("X-User") + `"`
+		if req.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		rw.Header().Set("ETag", etag)
+		rw.Write([]byte("data " + req.Header.Get("X-User")))
+	}))
+	defer srv.Close()
+	c, _, clean := testClient(t)
+	defer clean()
+
+	for i := 0; i < 3; i++ {
+		body, e := c.WithHeader(Header{"X-User": "one"}).Get(srv.URL)
+		assert.NoError(t, e, "Get")
+		assert.Equal(t, "data one", string(body), "cached body")
+	}
+	assert.EqualValues(t, 1, full, "full answers")
+	assert.EqualValues(t, 2, notModified, "not modified answers")
+
+	body, _ := c.WithHeader(Header{"X-User": "two"}).Get(srv.URL)
+	assert.Equal(t, "data two", string(body), "headers in cache key")
+	assert.EqualValues(t, 2, full, "full answers")
+
+	assert.NoError(t, c.ClearCache(), "ClearCache")
+	c.WithHeader(Header{"X-User": "one"}).Get(srv.URL)
+	assert.EqualValues(t, 3, full, "cache cleared")
+
+	// Never stored.
+	assert.NoError(t, c.ClearCache(), "ClearCache")
+	c.WithHeader(Header{"Authorization": "Basic secret"}).Get(srv.URL)
+	files, _ := ioutil.ReadDir(c.CacheDir)
+	assert.Empty(t, files, "authenticated request not cached")
+
+	private := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Cache-Control", "Private, max-age=60")
+		rw.Write([]byte("data"))
+	}))
+	defer private.Close()
+	c.Get(private.URL)
+	files, _ = ioutil.ReadDir(c.CacheDir)
+	assert.Empty(t, files, "private answer not cached")
+}
+
+func TestProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte("proxied " + req.URL.String()))
+	}))
+	defer proxy.Close()
+	c, _, clean := testClient(t)
+	defer clean()
+
+	assert.NoError(t, c.SetProxy(proxy.URL), "SetProxy")
+	body, e := c.Get("http://godock.invalid/file")
+	assert.NoError(t, e, "Get")
+	assert.Equal(t, "proxied http://godock.invalid/file", string(body), "proxied")
+
+	assert.NoError(t, c.SetProxy(ProxyDirect), "SetProxy direct")
+	_, e = c.WithRetries(0).Get("http://godock.invalid/file")
+	assert.Error(t, e, "direct")
+}
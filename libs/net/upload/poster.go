@@ -4,6 +4,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/url"
+	"strings"
 )
 
 //
@@ -96,12 +97,17 @@ func NewRequester(method, uri string) *AsRequestHTTP {
 type AsRequestHTTP struct {
 	UpBase
 	Method string
+	Header string // Answer header added to the result on a new line, if set.
 }
 
 // Post send content to the hosting service and return the parsed result.
 //
 func (m *AsRequestHTTP) Post(r io.Reader, size int64, file string) (string, error) {
-	return Reader(m.Method, m.GetURL(file), r, size)
+	if m.Header == "" {
+		return Reader(m.Method, m.GetURL(file), r, size)
+	}
+	body, header, e := ReaderHeader(m.Method, m.GetURL(file), r, size, m.Header)
+	return strings.TrimRight(body, "\n") + "\n" + header, e
 }
 
 //
@@ -0,0 +1,503 @@
+package upload
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+//-----------------------------------------------------------[ URL TEMPLATES ]--
+
+// TemplateVars defines the fields replaced in upload locations and links
+// templates:
+//   {name}  file name, like photo.jpg
+//   {base}  file name without extension, like photo
+//   {ext}   file extension, like .jpg
+//   {date}  upload date, like 20170131
+//   {rand}  random hex string, to get links hard to guess
+//
+// Raw text uploads have no file name and are named {rand}.txt
+//
+type TemplateVars map[string]string
+
+// NewTemplateVars creates the template fields for a file upload.
+//
+func NewTemplateVars(file string) TemplateVars {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	tv := TemplateVars{
+		"date": time.Now().Format("20060102"),
+		"rand": hex.EncodeToString(buf),
+	}
+	tv["name"] = filepath.Base(file)
+	if file == "" {
+		tv["name"] = tv["rand"] + ".txt"
+	}
+	tv["ext"] = filepath.Ext(tv["name"])
+	tv["base"] = strings.TrimSuffix(tv["name"], tv["ext"])
+	return tv
+}
+
+// Expand replaces template fields in the string.
+//
+func (tv TemplateVars) Expand(tmpl string) string {
+	return tv.expand(tmpl, func(s string) string { return s })
+}
+
+// ExpandURL replaces template fields in the URL, with values escaped.
+//
+func (tv TemplateVars) ExpandURL(tmpl string) string {
+	return tv.expand(tmpl, url.PathEscape)
+}
+
+func (tv TemplateVars) expand(tmpl string, escape func(string) string) string {
+	var args []string
+	for key, value := range tv {
+		args = append(args, "{"+key+"}", escape(value))
+	}
+	return strings.NewReplacer(args...).Replace(tmpl)
+}
+
+// linkOr returns the expanded link template, or the fallback if not set.
+//
+func (tv TemplateVars) linkOr(link, fallback string) string {
+	if link == "" {
+		return fallback
+	}
+	return tv.ExpandURL(link)
+}
+
+//
+//------------------------------------------------------------------[ WEBDAV ]--
+
+// NewWebDAV creates a WebDAV Poster with the upload URL and public link
+// templates.
+//
+func NewWebDAV(uri, link, user, password string) *AsWebDAV {
+	return &AsWebDAV{
+		UpBase:   NewBaseURL(uri),
+		Link:     link,
+		User:     user,
+		Password: password,
+	}
+}
+
+// AsWebDAV implements a Poster with a WebDAV PUT request.
+//
+// Post returns the public link, or the upload URL if no link template is set.
+//
+type AsWebDAV struct {
+	UpBase
+	Link     string            // Public link template.
+	User     string            // Basic auth user, if needed.
+	Password string            // Basic auth password.
+	Headers  map[string]string // Extra request headers.
+
+	location string // URL of the last file sent.
+}
+
+// Post sends content to the WebDAV server and returns the public link.
+//
+func (m *AsWebDAV) Post(r io.Reader, size int64, file string) (string, error) {
+	tv := NewTemplateVars(file)
+	uri := tv.ExpandURL(m.GetURL(file))
+	req, e := newPutRequest(uri, r, size, m.Headers)
+	if e != nil {
+		return "", e
+	}
+	if m.User != "" {
+		req.SetBasicAuth(m.User, m.Password)
+	}
+	e = doRequest(req)
+	if e != nil {
+		return "", e
+	}
+	m.location = uri
+	return tv.linkOr(m.Link, uri), nil
+}
+
+// Location returns the URL of the last file sent.
+//
+func (m *AsWebDAV) Location() string { return m.location }
+
+// Delete deletes a file sent to the WebDAV server.
+//
+func (m *AsWebDAV) Delete(location string) error {
+	req, e := newRequest("DELETE", location, m.Headers)
+	if e != nil {
+		return e
+	}
+	if m.User != "" {
+		req.SetBasicAuth(m.User, m.Password)
+	}
+	return doRequest(req)
+}
+
+//
+//----------------------------------------------------------------------[ S3 ]--
+
+// NewS3 creates an S3 compatible Poster with the object URL and public link
+// templates. The object URL must use the path style: https://endpoint/bucket/key
+//
+func NewS3(uri, link, region, accessKey, secretKey string) *AsS3 {
+	return &AsS3{
+		UpBase:    NewBaseURL(uri),
+		Link:      link,
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	}
+}
+
+// AsS3 implements a Poster with an S3 compatible PUT object request, signed
+// with AWS signature version 4.
+//
+// Post returns the public link, or the object URL if no link template is set.
+//
+type AsS3 struct {
+	UpBase
+	Link      string            // Public link template.
+	Region    string            // Signing region. Default us-east-1.
+	AccessKey string            // Access key ID.
+	SecretKey string            // Secret access key.
+	Headers   map[string]string // Extra request headers, like x-amz-acl.
+
+	location string // URL of the last object sent.
+}
+
+// Post sends content to the S3 server and returns the public link.
+//
+func (m *AsS3) Post(r io.Reader, size int64, file string) (string, error) {
+	tv := NewTemplateVars(file)
+	uri := tv.ExpandURL(m.GetURL(file))
+	req, e := newPutRequest(uri, r, size, m.Headers)
+	if e != nil {
+		return "", e
+	}
+	m.sign(req)
+	e = doRequest(req)
+	if e != nil {
+		return "", e
+	}
+	m.location = uri
+	return tv.linkOr(m.Link, uri), nil
+}
+
+// Location returns the URL of the last object sent.
+//
+func (m *AsS3) Location() string { return m.location }
+
+// Delete deletes an object sent to the S3 server.
+//
+func (m *AsS3) Delete(location string) error {
+	req, e := newRequest("DELETE", location, nil)
+	if e != nil {
+		return e
+	}
+	m.sign(req)
+	return doRequest(req)
+}
+
+// sign signs the request with an unsigned payload.
+//
+func (m *AsS3) sign(req *http.Request) {
+	region := m.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	req.Header.Set("X-Amz-Content-Sha256", UnsignedPayload)
+	SignV4(req, UnsignedPayload, region, "s3", m.AccessKey, m.SecretKey, time.Now())
+}
+
+//
+//---------------------------------------------------------------------[ SCP ]--
+
+// NewSCP creates an SCP Poster with the destination and public link templates.
+// The destination format is the scp one: [user@]host:path
+//
+func NewSCP(dest, link string) *AsSCP {
+	return &AsSCP{
+		UpBase: NewBaseURL(dest),
+		Link:   link,
+	}
+}
+
+// AsSCP implements a Poster with the scp command, to send files over SSH.
+// The SFTP protocol is forced (OpenSSH 8.7 or later), so the remote path is
+// used as is and never interpreted by a remote shell. Authentication must be
+// done by key, as no password can be asked.
+//
+// Post returns the public link, or the destination if no link template is set.
+//
+type AsSCP struct {
+	UpBase
+	Link     string            // Public link template.
+	Command  string            // Command to use. Default scp.
+	Identity string            // Private key file, if not the default one.
+	Options  map[string]string // SSH options, like Port.
+}
+
+// Post sends content to the SSH server and returns the public link.
+//
+func (m *AsSCP) Post(r io.Reader, size int64, file string) (string, error) {
+	tv := NewTemplateVars(file)
+	dest := tv.Expand(m.GetURL(file))
+
+	// Content is copied to a temp file with the same name, as scp only sends files.
+	dir, e := ioutil.TempDir("", "uptoshare")
+	if e != nil {
+		return "", e
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, tv["name"])
+	tmp, e := os.Create(src)
+	if e != nil {
+		return "", e
+	}
+	_, e = io.Copy(tmp, r)
+	tmp.Close()
+	if e != nil {
+		return "", e
+	}
+
+	// Batch mode: never ask for passwords. SFTP mode: the remote path, built
+	// from the file name, must not go through the legacy protocol remote shell.
+	args := []string{"-B", "-q", "-s"}
+	if m.Identity != "" {
+		args = append(args, "-i", m.Identity)
+	}
+	for key, value := range m.Options {
+		args = append(args, "-o", key+"="+value)
+	}
+	command := m.Command
+	if command == "" {
+		command = "scp"
+	}
+	out, e := exec.Command(command, append(args, src, dest)...).CombinedOutput()
+	if e != nil {
+		return "", fmt.Errorf("%s: %s %s", command, e, strings.TrimSpace(string(out)))
+	}
+	return tv.linkOr(m.Link, dest), nil
+}
+
+//
+//-------------------------------------------------------------[ CHUNKED PUT ]--
+
+// DefaultChunkSize is the default size of chunks sent by AsChunkedPUT.
+//
+const DefaultChunkSize = 8 << 20
+
+// Resumer is a Poster able to resume an interrupted upload, when the same file
+// is posted again.
+//
+type Resumer interface {
+	Poster
+	Resumable() bool
+}
+
+// NewChunkedPUT creates a chunked PUT Poster with the file URL template.
+//
+func NewChunkedPUT(uri, link string) *AsChunkedPUT {
+	return &AsChunkedPUT{
+		UpBase:    NewBaseURL(uri),
+		Link:      link,
+		ChunkSize: DefaultChunkSize,
+	}
+}
+
+// AsChunkedPUT implements a resumable Poster with PUT requests of file chunks,
+// for transfer.sh like servers with partial uploads support.
+//
+// Each chunk is sent to the file URL with a Content-Range header. When posting
+// again a file that failed, a HEAD request on the URL gives the size already
+// received (as Content-Length) and the upload resumes from there.
+//
+// Post returns the link template if set, or the server answer to the last
+// chunk, or the file URL.
+//
+type AsChunkedPUT struct {
+	UpBase
+	Link      string            // Public link template.
+	ChunkSize int64             // Size of chunks, in bytes.
+	Headers   map[string]string // Extra request headers.
+
+	pending  map[string]TemplateVars // Unfinished uploads, to resume on the same URL.
+	location string                  // URL of the last file sent.
+}
+
+// Resumable returns true as interrupted uploads can be resumed.
+//
+func (m *AsChunkedPUT) Resumable() bool { return true }
+
+// Post sends content to the server by chunks and returns the public link.
+//
+func (m *AsChunkedPUT) Post(r io.Reader, size int64, file string) (string, error) {
+	if m.pending == nil {
+		m.pending = make(map[string]TemplateVars)
+	}
+	key := file + ":" + strconv.FormatInt(size, 10)
+	tv, ok := m.pending[key]
+	if !ok || file == "" { // Raw text can't be matched to resume.
+		tv = NewTemplateVars(file)
+	}
+	uri := tv.ExpandURL(m.GetURL(file))
+
+	var offset int64
+	if ok {
+		offset = m.received(uri)
+		if offset > size {
+			offset = 0
+		}
+		_, e := io.CopyN(ioutil.Discard, r, offset)
+		if e != nil {
+			return "", e
+		}
+	}
+	if file != "" {
+		m.pending[key] = tv
+	}
+
+	chunk := m.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+	// An empty content is sent with a single request. When resuming a complete
+	// upload, nothing is left to send.
+	var answer string
+	for first := size == 0; first || offset < size; first = false {
+		count := size - offset
+		if count > chunk {
+			count = chunk
+		}
+		req, e := newPutRequest(uri, io.LimitReader(r, count), count, m.Headers)
+		if e != nil {
+			return "", e
+		}
+		if size > 0 {
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+count-1, size))
+		}
+		answer, e = doRequestAnswer(req)
+		if e != nil {
+			return "", e
+		}
+		offset += count
+	}
+	delete(m.pending, key)
+	m.location = uri
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		answer = uri
+	}
+	return tv.linkOr(m.Link, answer), nil
+}
+
+// Location returns the URL of the last file sent.
+//
+func (m *AsChunkedPUT) Location() string { return m.location }
+
+// Delete deletes a file sent to the server.
+//
+func (m *AsChunkedPUT) Delete(location string) error {
+	return SendRequest("DELETE", location, m.Headers)
+}
+
+// received returns the size of the file already received by the server.
+//
+func (m *AsChunkedPUT) received(uri string) int64 {
+	resp, e := http.Head(uri)
+	if e != nil {
+		return 0
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || resp.ContentLength < 0 {
+		return 0
+	}
+	return resp.ContentLength
+}
+
+//
+//------------------------------------------------------------------[ COMMON ]--
+
+// Deleter is a Poster able to delete the files it sent.
+//
+type Deleter interface {
+	Poster
+	Location() string // Location of the last file sent, to use with Delete.
+	Delete(location string) error
+}
+
+// SendRequest sends a request without content to the URL, like a DELETE.
+// An error is returned if the status isn't a success.
+//
+func SendRequest(method, uri string, headers map[string]string) error {
+	req, e := newRequest(method, uri, headers)
+	if e != nil {
+		return e
+	}
+	return doRequest(req)
+}
+
+// newRequest creates a request without content, with the headers.
+//
+func newRequest(method, uri string, headers map[string]string) (*http.Request, error) {
+	req, e := http.NewRequest(method, uri, nil)
+	if e != nil {
+		return nil, e
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// newPutRequest creates a PUT request with the content and headers.
+//
+func newPutRequest(uri string, r io.Reader, size int64, headers map[string]string) (*http.Request, error) {
+	req, e := http.NewRequest("PUT", uri, r)
+	if e != nil {
+		return nil, e
+	}
+	req.ContentLength = size
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return req, nil
+}
+
+// doRequest sends the request and returns an error with the server answer if
+// the status isn't a success.
+//
+func doRequest(req *http.Request) error {
+	_, e := doRequestAnswer(req)
+	return e
+}
+
+// doRequestAnswer sends the request and returns the server answer.
+// An error is returned if the status isn't a success.
+//
+func doRequestAnswer(req *http.Request) (string, error) {
+	resp, e := http.DefaultClient.Do(req)
+	if e != nil {
+		return "", e
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", errors.New(req.Method + " " + req.URL.String() + ": " + resp.Status + " " + strings.TrimSpace(string(body)))
+	}
+	body, e := ioutil.ReadAll(resp.Body)
+	return string(body), e
+}
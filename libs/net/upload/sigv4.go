@@ -0,0 +1,123 @@
+package upload
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UnsignedPayload is the payload hash used to stream content without
+// computing its hash first.
+//
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// SignV4 signs the request with AWS signature version 4, as used by S3
+// compatible services. All request headers are signed, with the host.
+//
+// The X-Amz-Content-Sha256 header must be set for S3 with the payload hash.
+//
+// See https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+//
+func SignV4(req *http.Request, payloadHash, region, service, accessKey, secretKey string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	// Canonical headers, with the host that isn't in the headers list.
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonHeaders string
+	for _, name := range names {
+		canonHeaders += name + ":" + headers[name] + "\n"
+	}
+	signed := strings.Join(names, ";")
+
+	// Ensure the path is sent as signed.
+	path := awsEscape(req.URL.Path, false)
+	if path == "" {
+		path = "/"
+	}
+	req.URL.RawPath = path
+
+	canonRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req),
+		canonHeaders,
+		signed,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex(canonRequest)
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	for _, str := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, str)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+signature)
+}
+
+// canonicalQuery returns the request query sorted and escaped for signing.
+//
+func canonicalQuery(req *http.Request) string {
+	var list []string
+	for key, values := range req.URL.Query() {
+		for _, value := range values {
+			list = append(list, awsEscape(key, true)+"="+awsEscape(value, true))
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, "&")
+}
+
+// awsEscape escapes all characters except the unreserved ones (and the slash
+// if not encodeSlash), as required by the signature.
+//
+func awsEscape(str string, encodeSlash bool) string {
+	const hexChars = "0123456789ABCDEF"
+	var buf []byte
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~',
+			c == '/' && !encodeSlash:
+			buf = append(buf, c)
+
+		default:
+			buf = append(buf, '%', hexChars[c>>4], hexChars[c&15])
+		}
+	}
+	return string(buf)
+}
+
+func hashHex(str string) string {
+	sum := sha256.Sum256([]byte(str))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, str string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(str))
+	return mac.Sum(nil)
+}
@@ -48,33 +48,44 @@ func File(method, url, file string) (string, error) {
 // Reader creates a new http request with the given reader and method (PUT / POST).
 //
 func Reader(method, url string, r io.Reader, size int64) (string, error) {
+	ret, _, e := ReaderHeader(method, url, r, size, "")
+	return ret, e
+}
+
+// ReaderHeader creates a new http request with the given reader and method,
+// and returns the answer with the value of the given answer header.
+//
+func ReaderHeader(method, url string, r io.Reader, size int64, header string) (string, string, error) {
 	req, e := http.NewRequest(method, url, r)
 	if e != nil {
-		return "", e
+		return "", "", e
 	}
 	req.ContentLength = size
 	resp, e := http.DefaultClient.Do(req)
 	if e != nil {
-		return "", e
+		return "", "", e
 	}
 	defer resp.Body.Close()
 	ret, e := ioutil.ReadAll(resp.Body)
-	return string(ret), e
+	return string(ret), resp.Header.Get(header), e
 }
 
 // ReaderMultipart creates a new http multipart POST upload request with optional extra params.
 //
+// The content is streamed from the reader, so reading progress matches the
+// sending progress.
+//
 // Thanks to https://gist.github.com/mattetti/5914158/f4d1393d83ebedc682a3c8e7bdc6b49670083b84
 //
 func ReaderMultipart(url, fileref, filename string, limitRate int, rdr io.Reader, size int64, params map[string]string) (string, error) {
-	bodyOut := new(bytes.Buffer)
-	writer := multipart.NewWriter(bodyOut)
-	part, e := writer.CreateFormFile(fileref, filename)
+	// Multipart headers and params are prepared around the file content.
+	buf := new(bytes.Buffer)
+	writer := multipart.NewWriter(buf)
+	_, e := writer.CreateFormFile(fileref, filename)
 	if e != nil {
 		return "", e
 	}
-
-	io.Copy(part, rdr)
+	headSize := buf.Len()
 
 	for key, val := range params {
 		_ = writer.WriteField(key, val)
@@ -83,11 +94,16 @@ func ReaderMultipart(url, fileref, filename string, limitRate int, rdr io.Reader
 	if e != nil {
 		return "", e
 	}
+	head := bytes.NewReader(buf.Bytes()[:headSize])
+	tail := bytes.NewReader(buf.Bytes()[headSize:])
 
-	request, e := http.NewRequest("POST", url, bodyOut)
+	request, e := http.NewRequest("POST", url, io.MultiReader(head, rdr, tail))
 	if e != nil {
 		return "", e
 	}
+	if size >= 0 {
+		request.ContentLength = int64(buf.Len()) + size
+	}
 
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 
@@ -0,0 +1,326 @@
+package uptoshare
+
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/config"
+	"github.com/sqp/godock/libs/net/upload"
+
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HostsFile defines the name of the default user hosts file.
+//
+// Each group of the file declares an upload host, the group name is the site
+// name to use in the applet config. Example:
+//
+//   [Paste.example.com]
+//   type=text
+//   url=https://paste.example.com/api/new
+//   method=form
+//   field=content
+//   options=expire=1d;private=1
+//   json=result.url
+//
+//   [Files.example.com]
+//   type=file
+//   method=webdav
+//   url=https://dav.example.com/share/{rand}/{name}
+//   link=https://files.example.com/{rand}/{name}
+//   user=me
+//   password=secret
+//   expire=14d
+//
+// Self hosted targets (webdav, s3, scp and chunked methods) build the link from the
+// link template, see upload.TemplateVars for the fields.
+//
+const HostsFile = "uptoshare_hosts.conf"
+
+// Custom hosts post methods.
+//
+const (
+	MethodMultipart = "multipart" // Multipart POST with the file as field (default).
+	MethodForm      = "form"      // POST form with the content as field.
+	MethodWebDAV    = "webdav"    // WebDAV PUT to the url template.
+	MethodS3        = "s3"        // S3 compatible PUT to the url template, as https://endpoint/bucket/key
+	MethodSCP       = "scp"       // Copy over SSH to the url template, as [user@]host:path
+	MethodChunked   = "chunked"   // Resumable PUT by chunks to the url template.
+)
+
+// HostConfig declares a user defined upload host.
+//
+// Only one link extraction rule is used, tested in order: Regex, JSON, Begin.
+// Without rule, the whole answer is used as link.
+//
+type HostConfig struct {
+	Name    string   `conf:"-"`       // Site name, from the group name.
+	Type    string   `conf:"type"`    // Backend type: text, image, video or file.
+	URL     string   `conf:"url"`     // Upload URL.
+	Method  string   `conf:"method"`  // Post method: multipart, form, webdav, s3, scp or chunked.
+	Field   string   `conf:"field"`   // Field used to send the file or text content.
+	Options []string `conf:"options"` // Static fields as "key=value", separated by ";". Headers for webdav and s3, ssh options for scp.
+
+	// Self hosted targets.
+	Link     string `conf:"link"`     // Public link template. Default is the upload location.
+	User     string `conf:"user"`     // WebDAV user or S3 access key.
+	Password string `conf:"password"` // WebDAV password or S3 secret key.
+	Region   string `conf:"region"`   // S3 region.
+	Identity string `conf:"identity"` // SSH private key file for scp.
+	Chunk    string `conf:"chunk"`    // Chunk size in MiB for chunked. Default 8.
+
+	// Expire is the lifetime of uploaded files, as a number with unit: h, d,
+	// w, M (30 days) or y. Like 14d.
+	Expire string `conf:"expire"`
+
+	// Delete is the HTTP method to request on the del link to delete a file,
+	// like DELETE or GET. Files sent with webdav, s3 and chunked methods are
+	// deleted from their upload location.
+	Delete string `conf:"delete"`
+
+	// Regex matches the link in the answer. The first unnamed group is used
+	// as link if any, otherwise the whole match. Named groups are added as
+	// extra links with their name (like del or thumb).
+	Regex string `conf:"regex"`
+
+	JSON  string `conf:"json"`  // Dotted path to the link in a JSON answer, like upload.files.0.url
+	Begin string `conf:"begin"` // Start of the link in the answer, like http://...
+	End   string `conf:"end"`   // Block found after the link in the answer.
+}
+
+// LoadHosts loads user defined hosts from a config file.
+// A missing file isn't an error, it just declares no hosts.
+//
+func LoadHosts(log cdtype.Logger, file string) (list []HostConfig, e error) {
+	if _, e := os.Stat(file); os.IsNotExist(e) {
+		return nil, nil
+	}
+	e = config.GetFromFile(log, file, func(cfg cdtype.ConfUpdater) {
+		cfg.ParseGroups(func(group string, _ []cdtype.ConfKeyer) {
+			if group == "DEFAULT" { // Keys before the first group.
+				return
+			}
+			hc := HostConfig{Name: group}
+			cfg.UnmarshalGroup(&hc, group, config.GetTag)
+			list = append(list, hc)
+		})
+	})
+	return list, e
+}
+
+// FileType returns the backend type of the host.
+//
+func (hc HostConfig) FileType() FileType {
+	for ft := FileTypeText; ft <= FileTypeFile; ft++ {
+		if strings.EqualFold(hc.Type, ft.String()) {
+			return ft
+		}
+	}
+	return FileTypeUnknown
+}
+
+// NewCustomHost creates an upload host Sender from a user declaration.
+//
+func NewCustomHost(hc HostConfig) (*Host, error) {
+	switch {
+	case hc.FileType() == FileTypeUnknown:
+		return nil, errors.New("custom host " + hc.Name + ": bad type: " + hc.Type)
+
+	case hc.URL == "":
+		return nil, errors.New("custom host " + hc.Name + ": url missing")
+	}
+
+	options := make(map[string]string)
+	for _, opt := range hc.Options {
+		kv := strings.SplitN(opt, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.New("custom host " + hc.Name + ": bad option: " + opt)
+		}
+		options[kv[0]] = kv[1]
+	}
+
+	expire, e := parseExpire(hc.Expire)
+	if e != nil {
+		return nil, errors.New("custom host " + hc.Name + ": " + e.Error())
+	}
+
+	// Self hosted targets return the link, and can delete from the location.
+	parseLink := func(h *Host, data string) Links {
+		links := NewLinks(data)
+		if deleter, ok := h.Poster.(upload.Deleter); ok {
+			links.Add(LinkDelete, deleter.Location())
+		}
+		return links
+	}
+
+	host := &Host{Expire: expire}
+	if hc.Delete != "" {
+		method := strings.ToUpper(hc.Delete)
+		host.Remove = func(h *Host, links Links) error {
+			return upload.SendRequest(method, links[LinkDelete], nil)
+		}
+	}
+	method := strings.ToLower(hc.Method)
+	switch method {
+	case "", MethodMultipart, MethodForm:
+		if hc.Field == "" {
+			return nil, errors.New("custom host " + hc.Name + ": field missing")
+		}
+		if method == MethodForm {
+			host.Poster = &upload.AsPostFormHTTP{UpBase: upload.NewBaseURL(hc.URL), ContentRef: hc.Field, Options: options}
+		} else {
+			host.Poster = &upload.AsMultipart{UpBase: upload.NewBaseURL(hc.URL), FileRef: hc.Field, Options: options}
+		}
+
+	case MethodWebDAV:
+		dav := upload.NewWebDAV(hc.URL, hc.Link, hc.User, hc.Password)
+		dav.Headers = options
+		host.Poster, host.Parse = dav, parseLink
+		return host, nil
+
+	case MethodS3:
+		s3 := upload.NewS3(hc.URL, hc.Link, hc.Region, hc.User, hc.Password)
+		s3.Headers = options
+		host.Poster, host.Parse = s3, parseLink
+		return host, nil
+
+	case MethodSCP:
+		scp := upload.NewSCP(hc.URL, hc.Link)
+		scp.Identity = hc.Identity
+		scp.Options = options
+		host.Poster, host.Parse = scp, parseLink
+		return host, nil
+
+	case MethodChunked:
+		put := upload.NewChunkedPUT(hc.URL, hc.Link)
+		put.Headers = options
+		if size, e := strconv.Atoi(hc.Chunk); e == nil && size > 0 {
+			put.ChunkSize = int64(size) << 20
+		}
+		host.Poster, host.Parse = put, parseLink
+		return host, nil
+
+	default:
+		return nil, errors.New("custom host " + hc.Name + ": bad method: " + hc.Method)
+	}
+
+	switch {
+	case hc.Regex != "":
+		re, e := regexp.Compile(hc.Regex)
+		if e != nil {
+			return nil, errors.New("custom host " + hc.Name + ": regex: " + e.Error())
+		}
+		host.Parse = func(h *Host, data string) Links { return parseRegex(h, re, data) }
+
+	case hc.JSON != "":
+		host.Parse = func(h *Host, data string) Links {
+			link, e := findJSON(data, hc.JSON)
+			if e != nil {
+				return linkErr(e, h.Name())
+			}
+			return NewLinks(link)
+		}
+
+	case hc.Begin != "":
+		host.Parse = func(h *Host, data string) Links {
+			link := findLink(data, hc.Begin, hc.End)
+			if link == "" {
+				return linkWarn(h.Name() + ": parse failed\n" + data)
+			}
+			return NewLinks(link)
+		}
+
+	default:
+		host.Parse = func(h *Host, data string) Links {
+			data = strings.TrimSpace(data)
+			if data == "" {
+				return linkWarn(h.Name() + ": empty answer")
+			}
+			return NewLinks(data)
+		}
+	}
+	return host, nil
+}
+
+// parseExpire parses a lifetime as a number with unit: h, d, w, M (30 days)
+// or y. An empty string means no limit.
+//
+func parseExpire(str string) (time.Duration, error) {
+	if str == "" {
+		return 0, nil
+	}
+	unit, ok := map[byte]time.Duration{
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+		'M': 30 * 24 * time.Hour,
+		'y': 365 * 24 * time.Hour,
+	}[str[len(str)-1]]
+	nb, e := strconv.Atoi(str[:len(str)-1])
+	if !ok || e != nil || nb < 0 {
+		return 0, errors.New("bad expire: " + str)
+	}
+	return time.Duration(nb) * unit, nil
+}
+
+// parseRegex extracts links from the answer with a regex.
+//
+func parseRegex(h *Host, re *regexp.Regexp, data string) Links {
+	match := re.FindStringSubmatch(data)
+	if match == nil {
+		return linkWarn(h.Name() + ": parse failed\n" + data)
+	}
+	links := NewLinks(match[0])
+	main := false
+	for i, name := range re.SubexpNames()[1:] {
+		switch {
+		case name != "":
+			links.Add(name, match[i+1])
+
+		case !main:
+			main = true
+			links.Add("link", match[i+1])
+		}
+	}
+	return links
+}
+
+// findJSON returns the string or number value at the dotted path in JSON data.
+// Path elements are object keys or array indexes.
+//
+func findJSON(data, path string) (string, error) {
+	var value interface{}
+	e := json.Unmarshal([]byte(data), &value)
+	if e != nil {
+		return "", e
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch cast := value.(type) {
+		case map[string]interface{}:
+			value = cast[key]
+
+		case []interface{}:
+			id, e := strconv.Atoi(key)
+			if e != nil || id < 0 || id >= len(cast) {
+				return "", errors.New("json path: bad index: " + key)
+			}
+			value = cast[id]
+
+		default:
+			return "", errors.New("json path: not found: " + key)
+		}
+	}
+
+	switch cast := value.(type) {
+	case string:
+		return cast, nil
+
+	case float64:
+		return strconv.FormatFloat(cast, 'f', -1, 64), nil
+	}
+	return "", errors.New("json path: no value at " + path)
+}
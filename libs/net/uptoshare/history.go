@@ -0,0 +1,175 @@
+package uptoshare
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+//-------------------------------------------------------------[ LINKS INFO ]--
+
+// Type returns the file type of the upload.
+//
+func (li Links) Type() FileType {
+	typ, _ := strconv.Atoi(li["type"])
+	return FileType(typ)
+}
+
+// Date returns the upload date, or a zero time if unknown.
+//
+func (li Links) Date() time.Time {
+	return parseDate(li["date"])
+}
+
+// Expiry returns the date the host will remove the file, or a zero time if
+// not limited.
+//
+func (li Links) Expiry() time.Time {
+	return parseDate(li[LinkExpire])
+}
+
+// Expired returns whether the file was removed by the host at the given time.
+//
+func (li Links) Expired(now time.Time) bool {
+	expiry := li.Expiry()
+	return !expiry.IsZero() && now.After(expiry)
+}
+
+func parseDate(str string) time.Time {
+	date, _ := time.ParseInLocation(DateFormat, str, time.Local)
+	return date
+}
+
+//
+//-------------------------------------------------------------------[ QUERY ]--
+
+// Query defines a search in the upload history. Zero fields match all.
+//
+type Query struct {
+	Type        FileType
+	After       time.Time // Uploaded after.
+	Before      time.Time // Uploaded before.
+	Name        string    // Part of the file location or link, case insensitive.
+	HideExpired bool      // Skip files removed by the host.
+}
+
+// ParseQuery creates a query from text fields, as used by the Dbus API:
+//   type     text, image, video or file
+//   after    date as 20060102
+//   before   date as 20060102 (included)
+//   name     part of the file location or link
+//   expired  false to hide expired files
+//
+func ParseQuery(fields map[string]string) (q Query, e error) {
+	for key, value := range fields {
+		switch key {
+		case "type":
+			q.Type = HostConfig{Type: value}.FileType()
+			if q.Type == FileTypeUnknown {
+				return q, errors.New("query: bad type: " + value)
+			}
+
+		case "after", "before":
+			date, e := time.ParseInLocation("20060102", value, time.Local)
+			if e != nil {
+				return q, errors.New("query: bad date: " + value)
+			}
+			if key == "after" {
+				q.After = date
+			} else {
+				q.Before = date.AddDate(0, 0, 1)
+			}
+
+		case "name":
+			q.Name = value
+
+		case "expired":
+			q.HideExpired = value == "false" || value == "no" || value == "0"
+
+		default:
+			return q, errors.New("query: unknown field: " + key)
+		}
+	}
+	return q, nil
+}
+
+// Match returns whether the links match the query at the given time.
+//
+func (q Query) Match(links Links, now time.Time) bool {
+	date := links.Date()
+	name := strings.ToLower(q.Name)
+	switch {
+	case q.Type != FileTypeUnknown && links.Type() != q.Type,
+		!q.After.IsZero() && date.Before(q.After),
+		!q.Before.IsZero() && !date.Before(q.Before),
+		name != "" && !strings.Contains(strings.ToLower(links["file"]), name) && !strings.Contains(strings.ToLower(links["link"]), name),
+		q.HideExpired && links.Expired(now):
+
+		return false
+	}
+	return true
+}
+
+// Search returns the history entries matching the query, newest first.
+//
+func (up *Uploader) Search(q Query) (list []Links) {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
+	now := time.Now()
+	for i := len(up.history) - 1; i >= 0; i-- {
+		if q.Match(up.history[i], now) {
+			list = append(list, up.history[i])
+		}
+	}
+	return list
+}
+
+//
+//------------------------------------------------------------------[ DELETE ]--
+
+// CanDelete returns whether the remote copy of the uploaded file can be deleted.
+//
+func (up *Uploader) CanDelete(links Links) bool {
+	deleter, ok := up.findSite(links[LinkSite]).(Deleter)
+	return ok && deleter.CanDelete(links)
+}
+
+// Delete deletes the remote copy of an uploaded file, and removes it from the
+// history.
+//
+func (up *Uploader) Delete(link string) error {
+	for _, links := range up.ListHistory() { // The remote delete is done without the lock.
+		if links["link"] != link {
+			continue
+		}
+		deleter, ok := up.findSite(links[LinkSite]).(Deleter)
+		if !ok {
+			return ErrNoDelete
+		}
+		e := deleter.Delete(links)
+		if e != nil {
+			return e
+		}
+		e = up.ListRemove(link)
+		if e == nil {
+			up.historyMu.Lock()
+			up.saveHistory()
+			up.historyMu.Unlock()
+		}
+		return e
+	}
+	return errors.New("link not found: " + link)
+}
+
+// findSite returns the backend with the name, or nil if not found.
+//
+func (up *Uploader) findSite(name string) Sender {
+	for _, backends := range up.sites {
+		if sender, ok := backends[name]; ok {
+			return sender
+		}
+	}
+	return nil
+}
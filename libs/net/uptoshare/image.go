@@ -0,0 +1,302 @@
+package uptoshare
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+)
+
+// JPEGQuality is the quality used when images have to be encoded again.
+//
+const JPEGQuality = 90
+
+//
+//----------------------------------------------------------[ IMAGE METADATA ]--
+
+// StripMetadata returns a transform that removes metadata from JPEG and PNG
+// images: EXIF (with GPS position and camera info), XMP, IPTC and text chunks.
+//
+// Images are unchanged except when the EXIF orientation must be applied on
+// pixels, as the tag is removed.
+//
+func StripMetadata() Transform {
+	return func(c *Content) error {
+		data, e := ioutil.ReadAll(c.Reader)
+		if e != nil {
+			return e
+		}
+		switch {
+		case bytes.HasPrefix(data, []byte("\xff\xd8")):
+			var orientation int
+			data, orientation, e = stripJPEG(data)
+			if e == nil && orientation > 1 {
+				data, e = rotateJPEG(data, orientation)
+			}
+
+		case bytes.HasPrefix(data, pngHeader):
+			data, e = stripPNG(data)
+		}
+		if e != nil {
+			return e
+		}
+		c.Reader = bytes.NewReader(data)
+		c.Size = int64(len(data))
+		return nil
+	}
+}
+
+// stripJPEG removes APP1 (EXIF, XMP) and APP13 (IPTC) segments and comments
+// from a JPEG file. The EXIF orientation found is returned (0 if none).
+//
+func stripJPEG(data []byte) (out []byte, orientation int, e error) {
+	out = append(out, data[:2]...) // SOI.
+	pos := 2
+	for {
+		if pos+4 > len(data) || data[pos] != 0xff {
+			return nil, 0, errors.New("jpeg: bad segment")
+		}
+		marker := data[pos+1]
+		if marker == 0xda { // Start of scan: image data until the end.
+			return append(out, data[pos:]...), orientation, nil
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:])) + 2
+		if pos+size > len(data) {
+			return nil, 0, errors.New("jpeg: bad segment size")
+		}
+		segment := data[pos : pos+size]
+		switch {
+		case marker == 0xe1 && bytes.HasPrefix(segment[4:], []byte("Exif\x00\x00")):
+			orientation = exifOrientation(segment[10:])
+
+		case marker == 0xe1, marker == 0xed, marker == 0xfe:
+			// Dropped.
+
+		default:
+			out = append(out, segment...)
+		}
+		pos += size
+	}
+}
+
+// exifOrientation returns the orientation tag value of EXIF data (0 if none).
+//
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if tiff[0] == 'I' {
+		order = binary.LittleEndian
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 { // Orientation, type short.
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// rotateJPEG applies the EXIF orientation on the image pixels.
+//
+func rotateJPEG(data []byte, orientation int) ([]byte, error) {
+	img, e := jpeg.Decode(bytes.NewReader(data))
+	if e != nil {
+		return nil, e
+	}
+	buf := new(bytes.Buffer)
+	e = jpeg.Encode(buf, orient(img, orientation), &jpeg.Options{Quality: JPEGQuality})
+	return buf.Bytes(), e
+}
+
+// orient returns the image with the EXIF orientation applied.
+//
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // Rotated by 90°.
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored.
+				dx, dy = w-1-x, y
+			case 3: // Rotated 180°.
+				dx, dy = w-1-x, h-1-y
+			case 4: // Flipped.
+				dx, dy = x, h-1-y
+			case 5: // Transposed.
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise.
+				dx, dy = h-1-y, x
+			case 7: // Transversed.
+				dx, dy = h-1-y, w-1-x
+			case 8: // Rotated 90° counter clockwise.
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+// stripPNG removes the text and EXIF chunks from a PNG file.
+//
+func stripPNG(data []byte) ([]byte, error) {
+	out := append([]byte{}, pngHeader...)
+	pos := len(pngHeader)
+	for pos < len(data) {
+		if pos+12 > len(data) {
+			return nil, errors.New("png: bad chunk")
+		}
+		size := int(binary.BigEndian.Uint32(data[pos:])) + 12 // Length, type and CRC.
+		if size < 12 || pos+size > len(data) {
+			return nil, errors.New("png: bad chunk size")
+		}
+		switch string(data[pos+4 : pos+8]) {
+		case "tEXt", "zTXt", "iTXt", "eXIf", "tIME":
+			// Dropped.
+
+		default:
+			out = append(out, data[pos:pos+size]...)
+		}
+		pos += size
+	}
+	return out, nil
+}
+
+//
+//------------------------------------------------------------[ IMAGE RESIZE ]--
+
+// ResizeImage returns a transform that downscales JPEG and PNG images to fit
+// in the given size. Smaller images and other formats are unchanged.
+//
+// Images are encoded again, so metadata is removed. The JPEG EXIF orientation
+// is applied on pixels first, as the tag is lost.
+//
+func ResizeImage(maxWidth, maxHeight int) Transform {
+	return func(c *Content) error {
+		data, e := ioutil.ReadAll(c.Reader)
+		if e != nil {
+			return e
+		}
+		c.Reader = bytes.NewReader(data)
+
+		cfg, format, e := image.DecodeConfig(bytes.NewReader(data))
+		if e != nil || (format != "jpeg" && format != "png") {
+			return nil // Not an image we can resize.
+		}
+		orientation := 0
+		if format == "jpeg" {
+			_, orientation, _ = stripJPEG(data)
+		}
+		if orientation >= 5 { // Rotated by 90°.
+			cfg.Width, cfg.Height = cfg.Height, cfg.Width
+		}
+		w, h := fitSize(cfg.Width, cfg.Height, maxWidth, maxHeight)
+		if w == cfg.Width && h == cfg.Height {
+			return nil
+		}
+
+		img, _, e := image.Decode(bytes.NewReader(data))
+		if e != nil {
+			return e
+		}
+		buf := new(bytes.Buffer)
+		small := resize(orient(img, orientation), w, h)
+		if format == "png" {
+			e = png.Encode(buf, small)
+		} else {
+			e = jpeg.Encode(buf, small, &jpeg.Options{Quality: JPEGQuality})
+		}
+		if e != nil {
+			return e
+		}
+		c.Reader = bytes.NewReader(buf.Bytes())
+		c.Size = int64(buf.Len())
+		return nil
+	}
+}
+
+// fitSize returns the size that fits in the max size, keeping the ratio.
+// A max of 0 means no limit.
+//
+func fitSize(w, h, maxW, maxH int) (int, int) {
+	if maxW > 0 && w > maxW {
+		w, h = maxW, (h*maxW+w/2)/w
+	}
+	if maxH > 0 && h > maxH {
+		w, h = (w*maxH+h/2)/h, maxH
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// resize downscales the image with an area average filter.
+//
+func resize(img image.Image, w, h int) *image.RGBA {
+	src := toRGBA(img)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, (y+1)*sh/h
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, (x+1)*sw/w
+			if x1 == x0 {
+				x1++
+			}
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				pix := src.Pix[src.PixOffset(x0, sy):]
+				for i := 0; i < (x1-x0)*4; i++ {
+					sum[i%4] += int(pix[i])
+				}
+			}
+			count := (x1 - x0) * (y1 - y0)
+			offset := dst.PixOffset(x, y)
+			for i := range sum {
+				dst.Pix[offset+i] = uint8(sum[i] / count)
+			}
+		}
+	}
+	return dst
+}
+
+// toRGBA converts the image to RGBA with bounds starting at 0.
+//
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
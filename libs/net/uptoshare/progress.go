@@ -0,0 +1,132 @@
+package uptoshare
+
+import (
+	"github.com/sqp/godock/libs/text/bytesize"
+
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressDelay is the minimum delay between two progress reports of an upload.
+//
+const ProgressDelay = 500 * time.Millisecond
+
+// ErrCanceled is returned when reading the content of a canceled upload.
+//
+var ErrCanceled = errors.New("upload canceled")
+
+//
+//----------------------------------------------------------------[ PROGRESS ]--
+
+// Progress defines the sending state of an upload.
+//
+type Progress struct {
+	ID   int           // Upload reference in the queue.
+	Name string        // File location or text start.
+	Sent int64         // Bytes sent.
+	Size int64         // Bytes to send.
+	Rate float64       // Average sending rate, in bytes per second.
+	ETA  time.Duration // Estimated time left.
+}
+
+// Fraction returns the sent fraction, from 0 to 1.
+//
+func (p Progress) Fraction() float64 {
+	if p.Size <= 0 {
+		return 0
+	}
+	return float64(p.Sent) / float64(p.Size)
+}
+
+// Format returns the sent percent with the rate and time left.
+//
+func (p Progress) Format() string {
+	return fmt.Sprintf("%d%%  %s/s  %s", int(p.Fraction()*100), bytesize.ByteSize(p.Rate), p.ETA/time.Second*time.Second)
+}
+
+//
+//------------------------------------------------------------------[ UPLOAD ]--
+
+// Upload defines an item of the upload queue.
+//
+type Upload struct {
+	Progress
+	Data  string // File location or text to upload.
+	Error string // Error of the last try, when the upload can be resumed.
+
+	canceled int32
+}
+
+// newUpload creates an upload item for the data.
+//
+func newUpload(id int, data string) *Upload {
+	name := strings.Replace(data, "\n", " ", -1)
+	if runes := []rune(name); len(runes) > 40 {
+		name = string(runes[:40]) + "..."
+	}
+	return &Upload{
+		Progress: Progress{ID: id, Name: name},
+		Data:     data,
+	}
+}
+
+// Cancel cancels the upload. Reading content will fail from now.
+//
+func (item *Upload) Cancel() { atomic.StoreInt32(&item.canceled, 1) }
+
+// Canceled returns whether the upload was canceled.
+//
+func (item *Upload) Canceled() bool { return atomic.LoadInt32(&item.canceled) == 1 }
+
+//
+//---------------------------------------------------------[ PROGRESS READER ]--
+
+// progressReader wraps the content reader of an upload to report progress,
+// and stop reading when canceled.
+//
+type progressReader struct {
+	io.Reader
+	item     *Upload
+	sent     int64
+	reported int64 // Bytes sent at the last report.
+	start    time.Time
+	last     time.Time
+	report   func(Progress)
+}
+
+// Read implements io.Reader.
+//
+func (pr *progressReader) Read(p []byte) (int, error) {
+	if pr.item.Canceled() {
+		return 0, ErrCanceled
+	}
+	n, e := pr.Reader.Read(p)
+	pr.sent += int64(n)
+
+	now := time.Now()
+	done := e == io.EOF || pr.sent >= pr.item.Size
+	if pr.sent != pr.reported && (done || now.Sub(pr.last) >= ProgressDelay) {
+		pr.last = now
+		pr.reported = pr.sent
+		pr.report(pr.progress(now))
+	}
+	return n, e
+}
+
+// progress returns the upload progress at the given time.
+//
+func (pr *progressReader) progress(now time.Time) Progress {
+	p := pr.item.Progress
+	p.Sent = pr.sent
+	if elapsed := now.Sub(pr.start).Seconds(); elapsed > 0 {
+		p.Rate = float64(pr.sent) / elapsed
+	}
+	if p.Rate > 0 && p.Size > p.Sent {
+		p.ETA = time.Duration(float64(p.Size-p.Sent) / p.Rate * float64(time.Second))
+	}
+	return p
+}
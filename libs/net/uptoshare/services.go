@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 var (
@@ -73,6 +74,7 @@ var PasteBinCom = &Host{
 		}
 		return NewLinks(data)
 	},
+	Expire: 30 * 24 * time.Hour, // api_paste_expire_date.
 }
 
 //
@@ -132,6 +134,7 @@ var PasteBinMozillaOrg = &Host{
 		return NewLinks(uri+ID).
 			Add("dl", uri+"/"+ID[5:])
 	},
+	Expire: 24 * time.Hour, // expiry.
 }
 
 //
@@ -443,12 +446,21 @@ var TransferSh = &Host{
 		UpBase: upload.NewBaseCB(func(file string) string {
 			return "https://transfer.sh/" + filepath.Base(file)
 		}),
+		Header: "X-Url-Delete",
 	},
 	Parse: func(h *Host, data string) Links {
-		data = strings.Trim(data, " \n")
-		if data == "Not Found" {
-			return linkWarn(h.Name() + " has answered: " + data)
+		lines := strings.SplitN(strings.Trim(data, " \n"), "\n", 2)
+		if lines[0] == "Not Found" {
+			return linkWarn(h.Name() + " has answered: " + lines[0])
 		}
-		return NewLinks(data)
+		links := NewLinks(lines[0])
+		if len(lines) > 1 {
+			links.Add(LinkDelete, lines[1])
+		}
+		return links
+	},
+	Expire: 14 * 24 * time.Hour,
+	Remove: func(h *Host, links Links) error {
+		return upload.SendRequest("DELETE", links[LinkDelete], nil)
 	},
 }
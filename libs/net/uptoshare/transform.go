@@ -0,0 +1,275 @@
+package uptoshare
+
+import (
+	"github.com/sqp/godock/libs/files"
+
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Content defines the data to upload, as changed by transforms.
+//
+type Content struct {
+	Reader   io.Reader
+	Size     int64
+	Name     string   // File name sent to the host. Empty for raw text.
+	Type     FileType // Backend type used to send the content.
+	Fragment string   // Added to the link as #fragment, like a decryption key.
+
+	// Resumable is false when the content changes at each try, so the upload
+	// can't be resumed.
+	Resumable bool
+
+	closers []func() error
+}
+
+// OnClose adds a call when the content is closed, to release resources.
+//
+func (c *Content) OnClose(call func() error) {
+	c.closers = append(c.closers, call)
+}
+
+// Close releases the content resources.
+//
+func (c *Content) Close() (e error) {
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		if err := c.closers[i](); err != nil {
+			e = err
+		}
+	}
+	c.closers = nil
+	return e
+}
+
+// Transform changes the content before the upload.
+//
+type Transform func(*Content) error
+
+// SetTransforms sets the transforms applied to content of the type before the
+// upload, in the given order.
+//
+func (up *Uploader) SetTransforms(typ FileType, list ...Transform) {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	if up.transforms == nil {
+		up.transforms = make(map[FileType][]Transform)
+	}
+	up.transforms[typ] = list
+}
+
+//
+//-----------------------------------------------------------------[ ARCHIVE ]--
+
+// archiveContent creates a tar gz archive of the files and directories, in a
+// temp file removed when the content is closed.
+//
+func archiveContent(paths []string) (*Content, error) {
+	tmp, e := ioutil.TempFile("", "uptoshare")
+	if e != nil {
+		return nil, e
+	}
+	content := &Content{
+		Name:      strings.TrimSuffix(paths[0], "/") + ".tar.gz",
+		Type:      FileTypeFile,
+		Resumable: true,
+	}
+	content.OnClose(func() error { return os.Remove(tmp.Name()) })
+	content.OnClose(tmp.Close)
+
+	e = files.TarGz(tmp, paths...)
+	if e != nil {
+		content.Close()
+		return nil, e
+	}
+	content.Size, e = tmp.Seek(0, io.SeekCurrent)
+	if e == nil {
+		_, e = tmp.Seek(0, io.SeekStart)
+	}
+	if e != nil {
+		content.Close()
+		return nil, e
+	}
+	content.Reader = tmp
+	return content, nil
+}
+
+//
+//---------------------------------------------------------------[ ENCRYPTION ]--
+
+// Encryption format: a random nonce prefix, then the content split in segments
+// encrypted with AES-256-GCM. The segment nonce is the prefix with the segment
+// counter. The last segment is flagged in the counter to detect truncation.
+const (
+	cryptSegment    = 64 << 10
+	cryptPrefixSize = 7
+	cryptLastFlag   = 1 << 31
+	cryptExt        = ".enc"
+)
+
+// Encrypt returns a transform that encrypts the content with a new random key
+// for each upload. The key is set as link fragment, so it's never sent to the
+// host, and the content is uploaded as file.
+//
+// Content can be decrypted with Decrypt. Recipients use the cdc decrypt
+// command with the full link, as the format is specific to this package.
+//
+func Encrypt() Transform {
+	return func(c *Content) error {
+		key := make([]byte, 32)
+		prefix := make([]byte, cryptPrefixSize)
+		if _, e := rand.Read(key); e != nil {
+			return e
+		}
+		if _, e := rand.Read(prefix); e != nil {
+			return e
+		}
+		aead, e := newAEAD(key)
+		if e != nil {
+			return e
+		}
+
+		segments := (c.Size + cryptSegment - 1) / cryptSegment
+		if segments == 0 {
+			segments = 1
+		}
+		c.Reader = io.MultiReader(
+			bytes.NewReader(prefix),
+			&encryptReader{src: c.Reader, aead: aead, prefix: prefix, left: c.Size})
+		c.Size += cryptPrefixSize + segments*int64(aead.Overhead())
+
+		if c.Name == "" {
+			c.Name = "text.txt"
+		}
+		c.Name += cryptExt
+		c.Type = FileTypeFile
+		c.Fragment = base64.RawURLEncoding.EncodeToString(key)
+		c.Resumable = false
+		return nil
+	}
+}
+
+// Decrypt decrypts content encrypted by Encrypt with the key from the link
+// fragment.
+//
+func Decrypt(w io.Writer, r io.Reader, fragment string) error {
+	key, e := base64.RawURLEncoding.DecodeString(fragment)
+	if e != nil {
+		return e
+	}
+	aead, e := newAEAD(key)
+	if e != nil {
+		return e
+	}
+	prefix := make([]byte, cryptPrefixSize)
+	if _, e := io.ReadFull(r, prefix); e != nil {
+		return e
+	}
+
+	buf := make([]byte, cryptSegment+aead.Overhead()+1) // Read one more byte to find the last segment.
+	size, e := io.ReadFull(r, buf)
+	for counter := uint32(0); ; counter++ {
+		last := e == io.EOF || e == io.ErrUnexpectedEOF
+		if e != nil && !last {
+			return e
+		}
+		seg := size
+		if !last {
+			seg--
+		}
+		flag := counter
+		if last {
+			flag |= cryptLastFlag
+		}
+		plain, err := aead.Open(nil, cryptNonce(prefix, flag), buf[:seg], nil)
+		if err != nil {
+			return errors.New("decrypt: " + err.Error())
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+
+		buf[0] = buf[seg] // Keep the extra byte read.
+		size, e = io.ReadFull(r, buf[1:])
+		size++
+	}
+}
+
+// encryptReader encrypts the source by segments.
+//
+type encryptReader struct {
+	src     io.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	left    int64  // Bytes to read from source.
+	counter uint32 // Segment number.
+	out     []byte // Encrypted data not read yet.
+	done    bool
+}
+
+// Read implements io.Reader.
+//
+func (er *encryptReader) Read(p []byte) (int, error) {
+	if len(er.out) == 0 {
+		if er.done {
+			return 0, io.EOF
+		}
+		size := int64(cryptSegment)
+		if er.left < size {
+			size = er.left
+		}
+		plain := make([]byte, size)
+		if _, e := io.ReadFull(er.src, plain); e != nil {
+			return 0, e
+		}
+		er.left -= size
+
+		flag := er.counter
+		if er.left == 0 {
+			flag |= cryptLastFlag
+			er.done = true
+		}
+		er.out = er.aead.Seal(nil, cryptNonce(er.prefix, flag), plain, nil)
+		er.counter++
+	}
+	n := copy(p, er.out)
+	er.out = er.out[n:]
+	return n, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, e := aes.NewCipher(key)
+	if e != nil {
+		return nil, e
+	}
+	return cipher.NewGCM(block)
+}
+
+// cryptNonce returns the nonce of a segment: prefix, zero byte and counter.
+//
+func cryptNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, cryptPrefixSize+5)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[cryptPrefixSize+1:], counter)
+	return nonce
+}
+
+// linkFragment adds the fragment to the link.
+//
+func linkFragment(link, fragment string) string {
+	if fragment == "" || link == "" {
+		return link
+	}
+	return link + "#" + fragment
+}
@@ -12,8 +12,10 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -59,15 +61,32 @@ func (f FileType) String() string {
 //
 type Sender interface {
 	SetName(string)
+	Name() string
 	SetConfig(*upload.Config)
 	Send(r io.Reader, size int64, filename string) Links
 }
 
+// Deleter is a Sender able to delete uploaded files.
+//
+type Deleter interface {
+	CanDelete(Links) bool
+	Delete(Links) error
+}
+
+// ErrNoDelete is returned when the host can't delete an uploaded file.
+//
+var ErrNoDelete = errors.New("delete not supported by the host")
+
 // Host describes an upload service to implement a Sender.
 //
 type Host struct {
 	upload.Poster
-	Parse func(*Host, string) Links
+	Parse  func(*Host, string) Links
+	Expire time.Duration // Lifetime of uploaded files, if limited by the host.
+
+	// Remove deletes an uploaded file. Optional, a Poster implementing the
+	// upload.Deleter interface is used by default.
+	Remove func(*Host, Links) error
 }
 
 // Send sends the file over the network to the hosting website.
@@ -80,9 +99,51 @@ func (h *Host) Send(r io.Reader, size int64, filename string) Links {
 	return h.Parse(h, data)
 }
 
+// Resumable returns whether an interrupted upload can be resumed.
+//
+func (h *Host) Resumable() bool {
+	resumer, ok := h.Poster.(upload.Resumer)
+	return ok && resumer.Resumable()
+}
+
+// Expiry returns the lifetime of uploaded files, or 0 if not limited.
+//
+func (h *Host) Expiry() time.Duration { return h.Expire }
+
+// CanDelete returns whether the uploaded file can be deleted from the host.
+//
+func (h *Host) CanDelete(links Links) bool {
+	_, ok := h.Poster.(upload.Deleter)
+	return links[LinkDelete] != "" && (h.Remove != nil || ok)
+}
+
+// Delete deletes the uploaded file from the host.
+//
+func (h *Host) Delete(links Links) error {
+	if !h.CanDelete(links) {
+		return ErrNoDelete
+	}
+	if h.Remove != nil {
+		return h.Remove(h, links)
+	}
+	return h.Poster.(upload.Deleter).Delete(links[LinkDelete])
+}
+
 //
 //-------------------------------------------------------------------[ LINKS ]--
 
+// Links fields added to the main link.
+//
+const (
+	LinkDelete = "del"    // Deletion page or location, depending on the host.
+	LinkExpire = "expire" // Expiry date, if limited by the host.
+	LinkSite   = "site"   // Host name.
+)
+
+// DateFormat is the format of dates in links.
+//
+const DateFormat = "20060102 15:04:05"
+
 // Links contains the result data of an upload.
 //
 type Links map[string]string
@@ -120,28 +181,38 @@ type Uploader struct {
 	// Log provides a common logger for the uptoshare service.
 	Log cdtype.Logger
 
-	queue   chan string
+	queue   chan *Upload
 	active  bool
+	items   []*Upload // Queued and active uploads, and failed ones that can be resumed.
+	lastID  int
+	mu      sync.Mutex
 	upFile  Sender
 	upImage Sender
 	upText  Sender
 	upVideo Sender
+	sites   map[FileType]map[string]Sender // Backends available, by type.
+
+	transforms map[FileType][]Transform // Content changes before the upload, by type.
 
 	actionPre  func()
 	actionPost func()
 	onResult   func(Links)
+	onProgress func(Progress)
 
 	history     []Links
 	historyMax  int
 	historyFile string
+	historyMu   sync.Mutex // Guards the history: used by the worker, the menu and the Dbus API.
 }
 
 // New creates an Uploader.
 //
 func New() *Uploader {
-	return &Uploader{
-		queue: make(chan string, 10),
+	up := &Uploader{
+		queue: make(chan *Upload, 10),
 	}
+	up.resetSites()
+	return up
 }
 
 //
@@ -165,28 +236,35 @@ func (up *Uploader) SetOnResult(call func(Links)) {
 	up.onResult = call
 }
 
+// SetOnProgress sets the upload progress callback. It's called in the upload
+// goroutine at most every ProgressDelay, and when the content is sent.
+//
+func (up *Uploader) SetOnProgress(call func(Progress)) {
+	up.onProgress = call
+}
+
 // SiteImage sets the image upload backend.
 //
 func (up *Uploader) SiteImage(site string) error {
-	return up.setSite(&up.upImage, backendImage, site)
+	return up.setSite(&up.upImage, up.sites[FileTypeImage], site)
 }
 
 // SiteText sets the text upload backend.
 //
 func (up *Uploader) SiteText(site string) error {
-	return up.setSite(&up.upText, backendText, site)
+	return up.setSite(&up.upText, up.sites[FileTypeText], site)
 }
 
 // SiteVideo sets the text upload backend.
 //
 func (up *Uploader) SiteVideo(site string) error {
-	return up.setSite(&up.upVideo, backendVideo, site)
+	return up.setSite(&up.upVideo, up.sites[FileTypeVideo], site)
 }
 
 // SiteFile sets the text upload backend.
 //
 func (up *Uploader) SiteFile(site string) error {
-	e := up.setSite(&up.upFile, backendFile, site)
+	e := up.setSite(&up.upFile, up.sites[FileTypeFile], site)
 	up.Log.Err(e, "set SiteFile")
 	return e
 }
@@ -214,12 +292,61 @@ func (up *Uploader) setSite(call *Sender, backends map[string]Sender, site strin
 	return nil
 }
 
+// SetHostsFile loads user defined hosts from the file and adds them to the
+// available backends, replacing those from a previous load.
+// Must be called before setting the sites.
+//
+func (up *Uploader) SetHostsFile(file string) error {
+	up.resetSites()
+	hosts, e := LoadHosts(up.Log, file)
+	if e != nil {
+		return e
+	}
+	for _, hc := range hosts {
+		host, e := NewCustomHost(hc)
+		if up.Log.Err(e, "uptoshare hosts") {
+			continue
+		}
+		up.sites[hc.FileType()][hc.Name] = host
+	}
+	return nil
+}
+
+// Sites returns the names of the backends available for the type.
+//
+func (up *Uploader) Sites(typ FileType) (list []string) {
+	for name := range up.sites[typ] {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// resetSites sets the available backends to the builtin ones.
+//
+func (up *Uploader) resetSites() {
+	up.sites = make(map[FileType]map[string]Sender)
+	for typ, backends := range map[FileType]map[string]Sender{
+		FileTypeText:  backendText,
+		FileTypeImage: backendImage,
+		FileTypeVideo: backendVideo,
+		FileTypeFile:  backendFile,
+	} {
+		up.sites[typ] = make(map[string]Sender, len(backends))
+		for name, sender := range backends {
+			up.sites[typ][name] = sender
+		}
+	}
+}
+
 //
 //-----------------------------------------------------------------[ HISTORY ]--
 
 // SetHistoryFile sets the location of the history file.
 //
 func (up *Uploader) SetHistoryFile(file string) {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
 	up.historyFile = file
 	up.loadHistory()
 }
@@ -227,6 +354,8 @@ func (up *Uploader) SetHistoryFile(file string) {
 // SetHistorySize sets the size of the history.
 //
 func (up *Uploader) SetHistorySize(nb int) {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
 	up.historyMax = nb
 	up.trimHistory()
 }
@@ -234,6 +363,8 @@ func (up *Uploader) SetHistorySize(nb int) {
 // ListRemove removes an item from the history..
 //
 func (up *Uploader) ListRemove(uri string) error {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
 	found := -1
 	for i, link := range up.history {
 		if link["link"] == uri {
@@ -253,33 +384,42 @@ func (up *Uploader) ListRemove(uri string) error {
 // Walk parse the links list.
 //
 func (up *Uploader) Walk(call func(Links) bool) {
-	for _, l := range up.history {
+	for _, l := range up.ListHistory() {
 		if !call(l) {
 			return
 		}
 	}
 }
 
-// ListHistory returns the history content.
+// ListHistory returns a copy of the history content.
 //
 func (up *Uploader) ListHistory() []Links {
-	return up.history
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
+	return append([]Links{}, up.history...)
 }
 
 // SetHistory sets the new history content.
 //
 func (up *Uploader) SetHistory(links []Links) {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
 	up.history = links
 	up.trimHistory()
 	up.saveHistory()
 }
 
 func (up *Uploader) addHistory(list map[string]string) {
+	up.historyMu.Lock()
+	defer up.historyMu.Unlock()
 	up.history = append(up.history, list)
 	up.trimHistory()
 	up.saveHistory()
 }
 
+// trimHistory drops the oldest entries over the history size.
+// Like loadHistory and saveHistory, it must be called with the history lock.
+//
 func (up *Uploader) trimHistory() {
 	if up.historyMax > 0 && len(up.history) > up.historyMax {
 		up.history = up.history[len(up.history)-up.historyMax:]
@@ -311,7 +451,7 @@ func (up *Uploader) saveHistory() {
 
 	cfg := config.NewEmpty(up.Log, up.historyFile)
 	defer cfg.Save()
-	for _, hist := range up.ListHistory() {
+	for _, hist := range up.history {
 		group := hist["link"]
 		for key, link := range hist {
 			cfg.Set(group, key, link)
@@ -328,8 +468,26 @@ func (up *Uploader) UploadGuess(data string) {
 	if data == "" {
 		return
 	}
-	up.queue <- data
+	up.mu.Lock()
+	up.lastID++
+	item := newUpload(up.lastID, data)
+	up.items = append(up.items, item)
+	up.mu.Unlock()
+
+	up.queue <- item
+	up.work()
+}
+
+// UploadFiles sends the files and directories as one archive. A single file is
+// sent as is.
+//
+func (up *Uploader) UploadFiles(paths ...string) {
+	up.UploadGuess(strings.Join(paths, "\n"))
+}
 
+// work uploads queued items, unless a worker is already active.
+//
+func (up *Uploader) work() {
 	if !up.active { // Only one worker.
 		up.active = true
 		if up.actionPre != nil {
@@ -337,16 +495,28 @@ func (up *Uploader) UploadGuess(data string) {
 		}
 
 		for len(up.queue) > 0 {
-			data := <-up.queue
-			links := up.uploadOne(data)
+			item := <-up.queue
+			links := linkWarn("upload canceled: " + item.Name)
+			resumable := false
+			if !item.Canceled() {
+				links, resumable = up.uploadOne(item)
+			}
 
-			if _, ok := links["error"]; !ok {
+			errmsg, fail := links["error"]
+			if !fail {
 				up.addHistory(links)
 			}
+			up.mu.Lock()
+			if fail && resumable && !item.Canceled() { // Keep it to resume later.
+				item.Error = errmsg
+			} else {
+				up.removeItem(item.ID)
+			}
+			up.mu.Unlock()
+
 			if up.onResult != nil {
 				up.onResult(links)
 			}
-
 		}
 
 		if up.actionPost != nil {
@@ -356,74 +526,180 @@ func (up *Uploader) UploadGuess(data string) {
 	}
 }
 
-func (up *Uploader) uploadOne(data string) Links {
-	var fileType FileType
-	var filePath string
-	var isFile bool
+// Uploads returns a copy of the queued and active uploads, and the failed ones
+// that can be resumed (with their Error set).
+//
+func (up *Uploader) Uploads() []Upload {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	list := make([]Upload, len(up.items))
+	for i, item := range up.items {
+		list[i] = Upload{Progress: item.Progress, Data: item.Data, Error: item.Error}
+	}
+	return list
+}
+
+// Cancel cancels a queued or active upload, or drops a failed one.
+//
+func (up *Uploader) Cancel(id int) error {
+	up.mu.Lock()
+	defer up.mu.Unlock()
+	item := up.findItem(id)
+	if item == nil {
+		return errors.New("upload not found: " + strconv.Itoa(id))
+	}
+	item.Cancel()
+	if item.Error != "" { // Not in the queue.
+		up.removeItem(id)
+	}
+	return nil
+}
+
+// Resume restarts a failed upload. The host continues the upload from the data
+// it already received.
+//
+func (up *Uploader) Resume(id int) error {
+	up.mu.Lock()
+	item := up.findItem(id)
+	if item == nil || item.Error == "" {
+		up.mu.Unlock()
+		return errors.New("no failed upload: " + strconv.Itoa(id))
+	}
+	item.Error = ""
+	up.mu.Unlock()
+
+	up.queue <- item
+	up.work()
+	return nil
+}
 
-	// File reference from desktop drop. Some cleaning to do.
-	if strings.HasPrefix(data, "file://") {
-		data = FileNameFromURI(data)
+func (up *Uploader) findItem(id int) *Upload {
+	for _, item := range up.items {
+		if item.ID == id {
+			return item
+		}
 	}
+	return nil
+}
 
-	// Get full file path if needed.
-	abs, e := filepath.Abs(data)
-	if e == nil && files.IsExist(abs) {
-		data = abs
+func (up *Uploader) removeItem(id int) {
+	for i, item := range up.items {
+		if item.ID == id {
+			up.items = append(up.items[:i], up.items[i+1:]...)
+			return
+		}
 	}
+}
+
+// setProgress updates the item progress and forwards it to the callback.
+//
+func (up *Uploader) setProgress(item *Upload, p Progress) {
+	up.mu.Lock()
+	item.Progress = p
+	up.mu.Unlock()
+	if up.onProgress != nil {
+		up.onProgress(p)
+	}
+}
+
+// uploadOne sends the item data and returns the links. On failure, resumable
+// is true when the upload can be resumed.
+//
+func (up *Uploader) uploadOne(item *Upload) (links Links, resumable bool) {
+	var fileType FileType
+	var filePath string
+	var content *Content
+	var e error
+	paths := filePaths(item.Data)
 
-	// Test file and get type.
-	clean := filepath.Clean(data)
 	switch {
-	case files.IsExist(clean): // use input as file location.
-		isFile = true
-		filePath = clean
+	case len(paths) > 1 || (len(paths) == 1 && files.IsDir(paths[0])): // archive of files and directories.
+		filePath = paths[0]
+		fileType = FileTypeFile
+		content, e = archiveContent(paths)
+		if e != nil {
+			return linkErr(e, "archive:"+filePath), false
+		}
+
+	case len(paths) == 1: // use input as file location.
+		filePath = paths[0]
 		fileType = getFileType(filePath)
 		if fileType == FileTypeUnknown {
 			up.Log.NewWarn(filePath, "file type unknown, uploaded as 'file'")
 			fileType = FileTypeFile
 		}
+		rdr, size, close, e := files.Reader(filePath)
+		if e != nil {
+			return linkErr(e, "open file:"+filePath), false
+		}
+		content = &Content{Reader: rdr, Size: size, Name: filePath, Type: fileType, Resumable: true}
+		content.OnClose(close)
 
 	default: // use input as text as default/fallback.
 		fileType = FileTypeText
+		content = &Content{Reader: strings.NewReader(item.Data), Size: int64(len(item.Data)), Type: fileType}
 	}
+	defer func() { up.Log.Err(content.Close(), "upload close") }()
 
 	up.Log.Debug("file upload", "type:", fileType, "path:", filePath)
 
+	// Apply transforms for the type.
+	up.mu.Lock()
+	transforms := up.transforms[fileType]
+	up.mu.Unlock()
+	for _, transform := range transforms {
+		e = transform(content)
+		if e != nil {
+			return linkErr(e, "transform:"+item.Name), false
+		}
+	}
+
 	// Get the sender for the type.
-	sender := up.getSender(&fileType)
+	sender := up.getSender(&content.Type)
 	if sender == nil {
-		return linkWarn("nothing to do with " + filePath)
+		return linkWarn("nothing to do with " + filePath), false
 	}
 
-	// Get the data reader.
-	var rdr io.Reader
-	var size int64
-	if isFile {
-		var close func() error
-		rdr, size, close, e = files.Reader(data)
-		if e != nil {
-			return linkErr(e, "open file:"+data)
-		}
-		defer func() { up.Log.Err(close(), "file close") }()
-
-	} else {
-		rdr = strings.NewReader(data)
-		size = int64(len(data))
+	// Report progress and stop reading if canceled.
+	up.mu.Lock()
+	item.Size = content.Size
+	up.mu.Unlock()
+	now := time.Now()
+	rdr := &progressReader{
+		Reader: content.Reader,
+		item:   item,
+		start:  now,
+		last:   now,
+		report: func(p Progress) { up.setProgress(item, p) },
 	}
 
 	// And try to send.
-	list := sender.Send(rdr, size, filePath)
+	list := sender.Send(rdr, content.Size, content.Name)
 
 	if len(list) == 0 {
-		return linkWarn("upload: nothing returned for " + filePath)
+		return linkWarn("upload: nothing returned for " + filePath), false
+	}
+
+	if _, ok := list["error"]; ok {
+		if item.Canceled() {
+			return linkWarn("upload canceled: " + item.Name), false
+		}
+		return list, content.Resumable && isResumable(sender)
 	}
 
 	// Should be a valid link. Add common info.
+	list["link"] = linkFragment(list["link"], content.Fragment)
 	list["file"] = filePath
-	list["type"] = strconv.Itoa(int(fileType))
-	list["date"] = time.Now().Format("20060102 15:04:05") // Time isn't used for now. Just display something readable. "YMD H:M:S"
-	return list
+	list["type"] = strconv.Itoa(int(content.Type))
+	list[LinkSite] = sender.Name()
+	now = time.Now()
+	list["date"] = now.Format(DateFormat)
+	if expirer, ok := sender.(interface {
+		Expiry() time.Duration
+	}); ok && expirer.Expiry() > 0 {
+		list[LinkExpire] = now.Add(expirer.Expiry()).Format(DateFormat)
+	}
+	return list, false
 }
 
 // Get the sender for the type.
@@ -439,6 +715,40 @@ func (up *Uploader) getSender(typ *FileType) Sender {
 	}[*typ]
 }
 
+// isResumable returns whether the sender can resume interrupted uploads.
+//
+func isResumable(sender Sender) bool {
+	resumer, ok := sender.(interface {
+		Resumable() bool
+	})
+	return ok && resumer.Resumable()
+}
+
+// filePaths returns the locations of existing files in the data, one per line.
+// Nil is returned if one line isn't a file, so data is handled as text.
+//
+func filePaths(data string) (paths []string) {
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		line = strings.TrimSpace(line)
+
+		// File reference from desktop drop. Some cleaning to do.
+		if strings.HasPrefix(line, "file://") {
+			line = FileNameFromURI(line)
+		}
+
+		// Get full file path if needed.
+		if line == "" {
+			return nil
+		}
+		abs, e := filepath.Abs(line)
+		if e != nil || !files.IsExist(abs) {
+			return nil
+		}
+		paths = append(paths, filepath.Clean(abs))
+	}
+	return paths
+}
+
 func getFileType(filePath string) FileType {
 	mimetype := mime.TypeByExtension(filepath.Ext(filePath))
 	switch {
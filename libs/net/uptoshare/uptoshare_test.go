@@ -4,11 +4,26 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sqp/godock/libs/log"
+	"github.com/sqp/godock/libs/net/upload"
 	"github.com/sqp/godock/libs/net/uptoshare"
 
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -117,3 +132,302 @@ func TestLeopard(t *testing.T) {
 	links := uptoshare.LeopardHosting.Parse(uptoshare.LeopardHosting, str)
 	assert.NotEmpty(t, links, "LeopardHostingAnswer")
 }
+
+func TestCustomHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/form":
+			fmt.Fprintf(w, `{"files":[{"url":"http://host/%s","size":42}]}`, r.FormValue("content")+r.FormValue("expire"))
+
+		case "/multipart":
+			_, head, e := r.FormFile("upfile")
+			if e != nil {
+				http.Error(w, e.Error(), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, "<a href='http://host/f/%s'>view</a> <a href='http://host/del/%s'>", head.Filename, r.FormValue("key"))
+		}
+	}))
+	defer srv.Close()
+
+	for _, test := range []struct {
+		hc    uptoshare.HostConfig
+		links uptoshare.Links
+	}{
+		{uptoshare.HostConfig{Type: "text", Method: "form", URL: srv.URL + "/form", Field: "content", Options: []string{"expire=1d"}, JSON: "files.0.url"},
+			uptoshare.Links{"link": "http://host/data1d"}},
+		{uptoshare.HostConfig{Type: "file", URL: srv.URL + "/multipart", Field: "upfile", Options: []string{"key=k1"}, Regex: `'(http://host/f/[^']+)'.*(?P<del>http://host/del/\w+)`},
+			uptoshare.Links{"link": "http://host/f/file.txt", "del": "http://host/del/k1"}},
+		{uptoshare.HostConfig{Type: "image", URL: srv.URL + "/multipart", Field: "upfile", Options: []string{"key=k1"}, Begin: "http://host/f/", End: "'"},
+			uptoshare.Links{"link": "http://host/f/file.txt"}},
+	} {
+		test.hc.Name = "test " + test.hc.Type
+		host, e := uptoshare.NewCustomHost(test.hc)
+		if !assert.NoError(t, e, test.hc.Name) {
+			continue
+		}
+		host.SetName(test.hc.Name)
+		host.SetConfig(&upload.Config{})
+		links := host.Send(strings.NewReader("data"), 4, "file.txt")
+		assert.Equal(t, test.links, links, test.hc.Name)
+	}
+
+	_, e := uptoshare.NewCustomHost(uptoshare.HostConfig{Type: "sound", URL: srv.URL, Field: "file"})
+	assert.Error(t, e, "bad type")
+	_, e = uptoshare.NewCustomHost(uptoshare.HostConfig{Type: "file", URL: srv.URL, Field: "file", Method: "PATCH"})
+	assert.Error(t, e, "bad method")
+}
+
+func TestUploadResume(t *testing.T) {
+	fail := true // Stand-in for a chunked upload server, failing the first upload.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "HEAD":
+			http.NotFound(w, r)
+
+		case fail:
+			fail = false
+			http.Error(w, "connection lost", http.StatusInternalServerError)
+
+		default:
+			ioutil.ReadAll(r.Body)
+			fmt.Fprint(w, "http://public"+r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	hosts, e := ioutil.TempFile("", "test_hosts.crap.")
+	if !assert.NoError(t, e, "temp file") {
+		return
+	}
+	defer os.Remove(hosts.Name())
+	fmt.Fprintf(hosts, "[Test.chunked]\ntype=file\nmethod=chunked\nurl=%s/{name}\n", srv.URL)
+	hosts.Close()
+
+	up, e := newUpclt()
+	if !assert.NoError(t, e, "temp file") {
+		return
+	}
+	assert.NoError(t, up.SetHostsFile(hosts.Name()), "SetHostsFile")
+	assert.Contains(t, up.Sites(uptoshare.FileTypeFile), "Test.chunked")
+	up.FileForAll = true
+	assert.NoError(t, up.SiteFile("Test.chunked"), "SiteFile")
+
+	var progress []uptoshare.Progress
+	var results []uptoshare.Links
+	up.SetOnProgress(func(p uptoshare.Progress) { progress = append(progress, p) })
+	up.SetOnResult(func(links uptoshare.Links) { results = append(results, links) })
+
+	up.UploadGuess(hosts.Name())
+	uploads := up.Uploads()
+	if !assert.Len(t, uploads, 1, "failed upload kept") {
+		return
+	}
+	assert.NotEmpty(t, uploads[0].Error, "failed upload error")
+	assert.Contains(t, results[0], "error", "first result")
+
+	assert.NoError(t, up.Resume(uploads[0].ID), "Resume")
+	assert.Empty(t, up.Uploads(), "upload done")
+	if assert.Len(t, results, 2, "results") {
+		assert.Equal(t, "http://public/"+filepath.Base(hosts.Name()), results[1]["link"], "link")
+	}
+	if assert.NotEmpty(t, progress, "progress") {
+		last := progress[len(progress)-1]
+		assert.Equal(t, last.Size, last.Sent, "progress sent")
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	// Encryption round trip, with multiple segments and empty content.
+	for _, size := range []int{0, 10, 150 << 10} {
+		plain := strings.Repeat("x", size)
+		content := &uptoshare.Content{Reader: strings.NewReader(plain), Size: int64(size), Name: "file.txt"}
+		assert.NoError(t, uptoshare.Encrypt()(content), "Encrypt")
+		crypted, _ := ioutil.ReadAll(content.Reader)
+		assert.Equal(t, content.Size, int64(len(crypted)), "crypted size")
+		assert.Equal(t, "file.txt.enc", content.Name, "crypted name")
+
+		buf := new(bytes.Buffer)
+		assert.NoError(t, uptoshare.Decrypt(buf, bytes.NewReader(crypted), content.Fragment), "Decrypt")
+		assert.Equal(t, plain, buf.String(), "decrypted")
+		assert.Error(t, uptoshare.Decrypt(ioutil.Discard, bytes.NewReader(crypted[:len(crypted)-1]), content.Fragment), "truncated")
+	}
+
+	// JPEG with EXIF: orientation rotated 90° clockwise and GPS data.
+	buf := new(bytes.Buffer)
+	jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil)
+	exif := "Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00GPS"
+	data := append([]byte("\xff\xd8\xff\xe1\x00\x23"+exif), buf.Bytes()[2:]...) // SOI, APP1 of 35 bytes.
+	content := &uptoshare.Content{Reader: bytes.NewReader(data), Size: int64(len(data))}
+	assert.NoError(t, uptoshare.StripMetadata()(content), "StripMetadata jpeg")
+	data, _ = ioutil.ReadAll(content.Reader)
+	assert.False(t, bytes.Contains(data, []byte("GPS")), "exif removed")
+	cfg, e := jpeg.DecodeConfig(bytes.NewReader(data))
+	if assert.NoError(t, e, "decode jpeg") {
+		assert.Equal(t, []int{2, 4}, []int{cfg.Width, cfg.Height}, "rotated")
+	}
+
+	// JPEG with EXIF orientation, resized: the orientation is applied first.
+	buf.Reset()
+	jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 100, 50)), nil)
+	data = append([]byte("\xff\xd8\xff\xe1\x00\x23"+exif), buf.Bytes()[2:]...)
+	content = &uptoshare.Content{Reader: bytes.NewReader(data), Size: int64(len(data))}
+	assert.NoError(t, uptoshare.ResizeImage(40, 40)(content), "ResizeImage jpeg")
+	data, _ = ioutil.ReadAll(content.Reader)
+	cfg, e = jpeg.DecodeConfig(bytes.NewReader(data))
+	if assert.NoError(t, e, "decode jpeg") {
+		assert.Equal(t, []int{20, 40}, []int{cfg.Width, cfg.Height}, "rotated and resized")
+	}
+
+	// PNG with text, resized.
+	buf.Reset()
+	png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 100, 50)))
+	data = buf.Bytes()
+	iend := len(data) - 12
+	data = append(append(append([]byte{}, data[:iend]...), pngChunk("tEXt", []byte("Author\x00secret"))...), data[iend:]...)
+	content = &uptoshare.Content{Reader: bytes.NewReader(data), Size: int64(len(data))}
+	assert.NoError(t, uptoshare.StripMetadata()(content), "StripMetadata png")
+	assert.NoError(t, uptoshare.ResizeImage(40, 40)(content), "ResizeImage")
+	data, _ = ioutil.ReadAll(content.Reader)
+	assert.Equal(t, content.Size, int64(len(data)), "png size")
+	assert.False(t, bytes.Contains(data, []byte("secret")), "text removed")
+	pcfg, e := png.DecodeConfig(bytes.NewReader(data))
+	if assert.NoError(t, e, "decode png") {
+		assert.Equal(t, []int{40, 20}, []int{pcfg.Width, pcfg.Height}, "resized")
+	}
+}
+
+func TestUploadArchive(t *testing.T) {
+	var got []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	dir, e := ioutil.TempDir("", "uptoshare_test")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "folder", "sub"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "folder", "sub", "file.txt"), []byte("data"), 0644)
+	hosts := filepath.Join(dir, "hosts.conf")
+	ioutil.WriteFile(hosts, []byte("[Test.webdav]\ntype=file\nmethod=webdav\nurl="+srv.URL+"/{name}\nlink=http://public/{name}\n"), 0644)
+
+	up, e := newUpclt()
+	if !assert.NoError(t, e, "newUpclt") {
+		return
+	}
+	assert.NoError(t, up.SetHostsFile(hosts), "SetHostsFile")
+	assert.NoError(t, up.SiteFile("Test.webdav"), "SiteFile")
+	up.SetTransforms(uptoshare.FileTypeFile, uptoshare.Encrypt())
+	var results []uptoshare.Links
+	up.SetOnResult(func(links uptoshare.Links) { results = append(results, links) })
+
+	up.UploadFiles(filepath.Join(dir, "folder"))
+	if !assert.Len(t, results, 1, "results") {
+		return
+	}
+	link := strings.SplitN(results[0]["link"], "#", 2)
+	if !assert.Len(t, link, 2, "link with key", results[0]) {
+		return
+	}
+	assert.Equal(t, "http://public/folder.tar.gz.enc", link[0], "link")
+
+	buf := new(bytes.Buffer)
+	if !assert.NoError(t, uptoshare.Decrypt(buf, bytes.NewReader(got), link[1]), "Decrypt") {
+		return
+	}
+	gz, e := gzip.NewReader(buf)
+	if !assert.NoError(t, e, "gzip") {
+		return
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for head, e := tr.Next(); e == nil; head, e = tr.Next() {
+		names = append(names, head.Name)
+	}
+	assert.Equal(t, []string{"folder/", "folder/sub/", "folder/sub/file.txt"}, names, "archive")
+}
+
+func TestHistory(t *testing.T) {
+	var deleted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			deleted = r.URL.Path
+		}
+	}))
+	defer srv.Close()
+
+	dir, e := ioutil.TempDir("", "uptoshare_test")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "notes.txt")
+	ioutil.WriteFile(file, []byte("data"), 0644)
+	hosts := filepath.Join(dir, "hosts.conf")
+	ioutil.WriteFile(hosts, []byte("[Test.webdav]\ntype=file\nmethod=webdav\nurl="+srv.URL+"/{name}\nexpire=2w\n"), 0644)
+
+	up, e := newUpclt()
+	if !assert.NoError(t, e, "newUpclt") {
+		return
+	}
+	assert.NoError(t, up.SetHostsFile(hosts), "SetHostsFile")
+	assert.NoError(t, up.SiteFile("Test.webdav"), "SiteFile")
+	up.FileForAll = true
+	up.SetHistory([]uptoshare.Links{
+		{"link": "http://old/1", "file": "/tmp/old.png", "type": "2", "date": "20170101 10:00:00", "expire": "20170115 10:00:00"},
+		{"link": "http://old/2", "type": "1", "date": "20170201 10:00:00"},
+	})
+	up.UploadFiles(file)
+
+	all := up.Search(uptoshare.Query{})
+	if !assert.Len(t, all, 3, "history") {
+		return
+	}
+	last := all[0]
+	assert.Equal(t, srv.URL+"/notes.txt", last["link"], "newest first")
+	assert.Equal(t, "Test.webdav", last[uptoshare.LinkSite], "site")
+	assert.Equal(t, 14*24*time.Hour, last.Expiry().Sub(last.Date()), "expiry")
+
+	for _, test := range []struct {
+		query map[string]string
+		links []string
+	}{
+		{map[string]string{"type": "image"}, []string{"http://old/1"}},
+		{map[string]string{"name": "NOTES"}, []string{last["link"]}},
+		{map[string]string{"after": "20170115", "before": "20170201"}, []string{"http://old/2"}},
+		{map[string]string{"expired": "false"}, []string{last["link"], "http://old/2"}},
+	} {
+		q, e := uptoshare.ParseQuery(test.query)
+		assert.NoError(t, e, "ParseQuery", test.query)
+		var links []string
+		for _, hist := range up.Search(q) {
+			links = append(links, hist["link"])
+		}
+		assert.Equal(t, test.links, links, "Search", test.query)
+	}
+	_, e = uptoshare.ParseQuery(map[string]string{"size": "1"})
+	assert.Error(t, e, "ParseQuery unknown field")
+
+	assert.False(t, up.CanDelete(all[1]), "CanDelete unknown host")
+	assert.Equal(t, uptoshare.ErrNoDelete, up.Delete("http://old/2"), "Delete unknown host")
+	if assert.True(t, up.CanDelete(last), "CanDelete") {
+		assert.NoError(t, up.Delete(last["link"]), "Delete")
+		assert.Equal(t, "/notes.txt", deleted, "deleted")
+		assert.Len(t, up.ListHistory(), 2, "removed from history")
+	}
+}
+
+// pngChunk returns a PNG chunk with its CRC.
+//
+func pngChunk(typ string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], typ)
+	chunk = append(chunk, data...)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, crc32.ChecksumIEEE(chunk[4:]))
+	return append(chunk, crc...)
+}
@@ -0,0 +1,377 @@
+package videodl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APIPath is the location of the JSON API, relative to the web service path.
+//
+// Calls, with JSON answers:
+//   GET    queue             List of queued videos, with download progress.
+//   GET    history           List of downloaded videos.
+//   GET    state             Download manager state.
+//   GET    events            Server-Sent Events stream of progress events.
+//   POST   videos            Add a video. Body: APIAdd.
+//   DELETE videos?url=URL    Remove a video from the queue or the history.
+//   POST   viewed            Set the viewed state. Body: APIViewed.
+//   POST   move              Move a video in the queue. Body: APIMove.
+//   POST   pause             Pause downloads.
+//   POST   resume            Resume downloads.
+//
+// Calls changing the state (POST and DELETE) need the "Content-Type:
+// application/json" header: browsers can't send it to another site without
+// its approval, so web pages can't forge them. The web service access token,
+// if set, is also checked for all calls.
+//
+// Errors are returned as APIError with the matching HTTP status code.
+//
+const APIPath = "/api/v1"
+
+// APIEventDelay is the delay between progress checks for the events stream.
+//
+var APIEventDelay = time.Second
+
+// APIVideo defines a video provided by the JSON API.
+//
+type APIVideo struct {
+	Name      string
+	URL       string
+	Category  string
+	DateAdded time.Time
+	DateDone  *time.Time
+	Format    *Format
+	Fail      bool
+	Viewed    bool
+	Active    bool // Downloading.
+	Progress  int  // Download progress in percent.
+}
+
+// APIAdd defines the API request to add a video.
+//
+type APIAdd struct {
+	URL      string
+	Itag     int    // Format to download. If not set, the best format matching TypeDL is used.
+	TypeDL   string // Media type: all, audio, video or videoaudio. Default: from config.
+	Quality  string // Set to "ask" to select the format with the dock dialog.
+	Category string
+}
+
+// APIViewed defines the API request to set the viewed state of a video.
+//
+type APIViewed struct {
+	URL    string
+	Viewed bool
+}
+
+// APIMove defines the API request to move a video in the queue.
+//
+type APIMove struct {
+	URL  string
+	Move string // top, up, down or bottom.
+}
+
+// APIProgress defines a progress event sent on the events stream.
+//
+type APIProgress struct {
+	State State
+	Queue []APIVideo
+}
+
+// APIError defines an error returned by the API.
+//
+type APIError struct {
+	Error string
+}
+
+// Errors returned by the API.
+var (
+	ErrAPINotFound  = errors.New("video not found")
+	ErrAPIURLNeeded = errors.New("url missing")
+	ErrAPIQueued    = errors.New("video already queued")
+	ErrAPINotJSON   = errors.New("content type must be application/json")
+)
+
+//
+//------------------------------------------------------------------[ ROUTES ]--
+
+// serveAPI dispatches the JSON API calls.
+//
+func (m *Manager) serveAPI(w http.ResponseWriter, r *http.Request) {
+	route := strings.TrimPrefix(r.URL.Path, "/"+WebPath+APIPath)
+	if r.Method != "GET" && !isJSON(r) {
+		apiJSON(w, http.StatusUnsupportedMediaType, APIError{ErrAPINotJSON.Error()})
+		return
+	}
+	switch r.Method + " " + strings.TrimSuffix(route, "/") {
+	case "GET /queue":
+		apiJSON(w, http.StatusOK, m.apiList(m.history.QueueList()))
+
+	case "GET /history":
+		apiJSON(w, http.StatusOK, m.apiList(m.history.DoneList()))
+
+	case "GET /state":
+		apiJSON(w, http.StatusOK, m.State())
+
+	case "GET /events":
+		m.apiEvents(w, r)
+
+	case "POST /videos":
+		var add APIAdd
+		if !apiDecode(w, r, &add) {
+			return
+		}
+		vid, code, e := m.apiAdd(add)
+		if e != nil {
+			apiJSON(w, code, APIError{e.Error()})
+			return
+		}
+		apiJSON(w, code, m.apiList([]*Video{vid})[0])
+
+	case "DELETE /videos":
+		vid, code, e := m.apiFind(r.URL.Query().Get("url"))
+		if e == nil {
+			if m.history.IsQueued(vid) {
+				e = m.RemoveQueued(vid)
+			} else {
+				e = m.history.RemoveDone(vid)
+			}
+			code = http.StatusInternalServerError
+		}
+		apiResult(w, code, e)
+
+	case "POST /viewed":
+		var req APIViewed
+		if !apiDecode(w, r, &req) {
+			return
+		}
+		vid, code, e := m.apiFind(req.URL)
+		if e == nil {
+			e = m.history.SetViewed(vid, req.Viewed)
+			code = http.StatusInternalServerError
+		}
+		apiResult(w, code, e)
+
+	case "POST /move":
+		var req APIMove
+		if !apiDecode(w, r, &req) {
+			return
+		}
+		move, ok := ParseQueueMove(req.Move)
+		if !ok {
+			apiJSON(w, http.StatusBadRequest, APIError{"bad move: " + req.Move})
+			return
+		}
+		vid, code, e := m.apiFind(req.URL)
+		if e == nil {
+			e = m.MoveQueued(vid, move)
+			code = http.StatusBadRequest
+		}
+		apiResult(w, code, e)
+
+	case "POST /pause":
+		m.Pause()
+		apiJSON(w, http.StatusOK, m.State())
+
+	case "POST /resume":
+		m.Resume()
+		apiJSON(w, http.StatusOK, m.State())
+
+	default:
+		apiJSON(w, http.StatusNotFound, APIError{"unknown call: " + r.Method + " " + route})
+	}
+}
+
+//
+//-----------------------------------------------------------------[ ACTIONS ]--
+
+// apiAdd creates a video for the request and adds it to the queue. Returns the
+// HTTP status code to use.
+//
+func (m *Manager) apiAdd(add APIAdd) (*Video, int, error) {
+	if add.URL == "" {
+		return nil, http.StatusBadRequest, ErrAPIURLNeeded
+	}
+	if vid := m.history.Find(add.URL); vid != nil && m.history.IsQueued(vid) {
+		return nil, http.StatusConflict, ErrAPIQueued
+	}
+
+	typ := m.TypeDL
+	if add.TypeDL != "" {
+		var ok bool
+		typ, ok = ParseTypeDL(add.TypeDL)
+		if !ok {
+			return nil, http.StatusBadRequest, errors.New("bad type: " + add.TypeDL)
+		}
+	}
+
+	filer, e := m.backend.New(m.log, add.URL)
+	if e != nil {
+		return nil, http.StatusBadGateway, e
+	}
+	vid, e := NewVideo(add.URL, filer)
+	if e != nil {
+		return nil, http.StatusBadGateway, e
+	}
+	vid.Category = add.Category
+
+	if add.Quality == "ask" {
+		m.log.GoTry(func() {
+			m.DialogQuality(m.filterTypeDL(typ), m.control.PopupDialog, func(vid *Video, format *Format) {
+				vid.Format = format
+				m.EnqueueAndStart(vid)
+			}, vid)
+		})
+		return vid, http.StatusAccepted, nil
+	}
+
+	if add.Itag == 0 {
+		vid.Format, e = m.bestFormat(vid, typ)
+		if e != nil {
+			return nil, http.StatusBadRequest, e
+		}
+
+	} else {
+		formats, e := filer.Formats()
+		if e != nil {
+			return nil, http.StatusBadGateway, e
+		}
+		for _, format := range formats {
+			if format.Itag == add.Itag {
+				vid.Format = format
+			}
+		}
+		if vid.Format == nil {
+			return nil, http.StatusBadRequest, errors.New("format not found")
+		}
+	}
+
+	m.EnqueueAndStart(vid)
+	return vid, http.StatusCreated, nil
+}
+
+// apiFind returns the video matching the url, with the HTTP status code to use
+// if not found.
+//
+func (m *Manager) apiFind(url string) (*Video, int, error) {
+	if url == "" {
+		return nil, http.StatusBadRequest, ErrAPIURLNeeded
+	}
+	vid := m.history.Find(url)
+	if vid == nil {
+		return nil, http.StatusNotFound, ErrAPINotFound
+	}
+	return vid, http.StatusOK, nil
+}
+
+// apiList converts the list of videos for the API, with download progress.
+//
+func (m *Manager) apiList(list []*Video) []APIVideo {
+	out := make([]APIVideo, len(list))
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history.mu.Lock()
+	defer m.history.mu.Unlock()
+	for i, vid := range list {
+		out[i] = APIVideo{
+			Name:      vid.Name,
+			URL:       vid.URL,
+			Category:  vid.Category,
+			DateAdded: vid.DateAdded,
+			DateDone:  vid.DateDone,
+			Format:    vid.Format,
+			Fail:      vid.Fail,
+			Viewed:    vid.Viewed,
+		}
+		if j, ok := m.jobs[vid]; ok {
+			out[i].Active = true
+			out[i].Progress = int(j.progress.Fraction() * 100)
+		}
+	}
+	return out
+}
+
+// apiEvents streams progress events, sent when the queue or its progress
+// changes, until the client disconnects.
+//
+func (m *Manager) apiEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apiJSON(w, http.StatusInternalServerError, APIError{"streaming not supported"})
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ticker := time.NewTicker(APIEventDelay)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		data, e := json.Marshal(APIProgress{
+			State: m.State(),
+			Queue: m.apiList(m.history.QueueList()),
+		})
+		if m.log.Err(e, "videodl: json.Marshal progress") {
+			return
+		}
+		if !bytes.Equal(data, last) {
+			_, e = w.Write([]byte("event: progress\ndata: " + string(data) + "\n\n"))
+			if e != nil {
+				return
+			}
+			flusher.Flush()
+			last = data
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//
+//-----------------------------------------------------------------[ HELPERS ]--
+
+// apiDecode decodes the JSON request body, or sends the error.
+//
+func apiDecode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	e := json.NewDecoder(r.Body).Decode(v)
+	if e != nil {
+		apiJSON(w, http.StatusBadRequest, APIError{"bad request: " + e.Error()})
+	}
+	return e == nil
+}
+
+// isJSON returns whether the request body is declared as JSON.
+//
+func isJSON(r *http.Request) bool {
+	typ, _, e := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return e == nil && typ == "application/json"
+}
+
+// apiResult sends the error with its status code, or a no content status.
+//
+func apiResult(w http.ResponseWriter, code int, e error) {
+	if e != nil {
+		apiJSON(w, code, APIError{e.Error()})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiJSON sends the data as JSON with the status code.
+//
+func apiJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,264 @@
+package videodl
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/log"
+
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeApp provides the history data dir.
+type fakeApp struct{ dir string }
+
+func (app fakeApp) FileDataDir(path ...string) string {
+	return filepath.Join(append([]string{app.dir}, path...)...)
+}
+
+// fakeDler is a video backend with downloads blocked until released, or
+// cancelled if cancellable.
+type fakeDler struct {
+	release     chan struct{}
+	started     chan struct{} // Optional, notified when a download starts.
+	cancellable bool
+}
+
+func (dl fakeDler) New(log cdtype.Logger, url string) (Filer, error) {
+	f := &fakeFiler{url: url, release: dl.release, started: dl.started}
+	if dl.cancellable {
+		f.cancel = make(chan struct{})
+	}
+	return f, nil
+}
+
+func (dl fakeDler) MenuQuality() []Quality { return nil }
+
+type fakeFiler struct {
+	url     string
+	release chan struct{}
+	started chan struct{}
+	cancel  chan struct{} // Nil when the download can't be cancelled.
+	once    sync.Once
+}
+
+func (f *fakeFiler) Title() (string, error) { return "title " + f.url, nil }
+
+func (f *fakeFiler) Cancel() {
+	if f.cancel != nil {
+		f.once.Do(func() { close(f.cancel) })
+	}
+}
+
+func (f *fakeFiler) Formats() ([]*Format, error) {
+	return []*Format{
+		{Itag: 22, Extension: "mp4", VideoEncoding: "H.264", AudioEncoding: "aac"},
+		{Itag: 140, Extension: "m4a", AudioEncoding: "aac"},
+	}, nil
+}
+
+func (f *fakeFiler) DownloadCmd(path string, format *Format, progress *Progress, limit Limit) func() error {
+	return func() error {
+		progress.SetMax(4)
+		progress.Write([]byte("ab"))
+		if f.started != nil {
+			f.started <- struct{}{}
+		}
+		select {
+		case <-f.release:
+			return nil
+		case <-f.cancel:
+			return errors.New("cancelled")
+		}
+	}
+}
+
+func TestAPI(t *testing.T) {
+	dir, e := ioutil.TempDir("", "videodl")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	dler := fakeDler{release: make(chan struct{})}
+	hist := NewHistoryVideo(fakeApp{dir}, HistoryFile)
+	m := NewManager(nil, log.NewLog(log.Logs).SetName("videodl_test"), hist)
+	m.SetConfig(&Config{EnabledDL: true, Workers: 1})
+	m.backend = dler
+
+	srv := httptest.NewServer(http.HandlerFunc(m.ServeHTTP))
+	defer srv.Close()
+	api := srv.URL + "/" + WebPath + APIPath
+
+	call := func(method, route string, body, answer interface{}) int {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, api+route, bytes.NewReader(data))
+		if method != "GET" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, e := http.DefaultClient.Do(req)
+		if !assert.NoError(t, e, method+" "+route) {
+			return 0
+		}
+		defer resp.Body.Close()
+		if answer != nil {
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(answer), "decode "+route)
+		}
+		return resp.StatusCode
+	}
+
+	// Add.
+	var vid APIVideo
+	assert.Equal(t, http.StatusCreated, call("POST", "/videos", APIAdd{URL: "one", TypeDL: "audio"}, &vid), "add")
+	assert.Equal(t, "title one", vid.Name, "add name")
+	assert.Equal(t, 140, vid.Format.Itag, "add best audio format")
+
+	assert.Equal(t, http.StatusCreated, call("POST", "/videos", APIAdd{URL: "two", Itag: 22}, nil), "add itag")
+	assert.Equal(t, http.StatusConflict, call("POST", "/videos", APIAdd{URL: "two"}, nil), "add queued")
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/videos", APIAdd{URL: "three", Itag: 5}, nil), "add bad itag")
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/videos", APIAdd{URL: "three", TypeDL: "text"}, nil), "add bad type")
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/videos", APIAdd{}, nil), "add no url")
+
+	resp, e := http.Post(api+"/videos", "text/plain", strings.NewReader(`{"URL": "three"}`))
+	if assert.NoError(t, e, "add form") {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode, "add without json type")
+	}
+
+	// Queue with progress.
+	var queue []APIVideo
+	assert.Equal(t, http.StatusOK, call("GET", "/queue", nil, &queue), "queue")
+	if assert.Len(t, queue, 2, "queue") {
+		assert.True(t, queue[0].Active, "queue active")
+		assert.Equal(t, 50, queue[0].Progress, "queue progress")
+		assert.False(t, queue[1].Active, "queue waiting")
+	}
+
+	assert.Equal(t, http.StatusNoContent, call("POST", "/move", APIMove{URL: "two", Move: "top"}, nil), "move")
+	assert.Equal(t, "two", hist.QueueList()[0].URL, "move")
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/move", APIMove{URL: "two", Move: "left"}, nil), "move bad")
+
+	// Events.
+	resp, e = http.Get(api + "/events")
+	if assert.NoError(t, e, "events") {
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"), "events type")
+		read := bufio.NewReader(resp.Body)
+		line, _ := read.ReadString('\n')
+		assert.Equal(t, "event: progress\n", line, "events name")
+		line, _ = read.ReadString('\n')
+		var progress APIProgress
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &progress), "events data")
+		assert.Equal(t, 2, progress.State.Queued, "events queued")
+		assert.Equal(t, 1, progress.State.Active, "events active")
+		resp.Body.Close()
+	}
+
+	// Done.
+	dler.release <- struct{}{}
+	dler.release <- struct{}{}
+	var list []APIVideo
+	for i := 0; i < 100 && len(list) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		call("GET", "/history", nil, &list)
+	}
+	assert.Len(t, list, 2, "history")
+	assert.Equal(t, 0, hist.Queued(), "queue empty")
+
+	assert.Equal(t, http.StatusNoContent, call("POST", "/viewed", APIViewed{URL: "one", Viewed: true}, nil), "viewed")
+	assert.True(t, hist.Find("one").Viewed, "viewed")
+	assert.Equal(t, http.StatusNotFound, call("POST", "/viewed", APIViewed{URL: "four"}, nil), "viewed unknown")
+
+	assert.Equal(t, http.StatusNoContent, call("DELETE", "/videos?url=one", nil, nil), "delete")
+	assert.Nil(t, hist.Find("one"), "deleted")
+	assert.Equal(t, http.StatusNotFound, call("GET", "/nothing", nil, nil), "unknown call")
+}
+
+func TestPause(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		cancellable bool
+		queued      int
+		done        int
+	}{
+		{"cancelled", true, 1, 0},
+		{"finished before the cancel", false, 0, 1},
+	} {
+		dir, e := ioutil.TempDir("", "videodl")
+		if !assert.NoError(t, e, "TempDir") {
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		dler := fakeDler{release: make(chan struct{}), started: make(chan struct{}), cancellable: test.cancellable}
+		hist := NewHistoryVideo(fakeApp{dir}, HistoryFile)
+		m := NewManager(nil, log.NewLog(log.Logs).SetName("videodl_test"), hist)
+		m.SetConfig(&Config{EnabledDL: true, Workers: 1})
+		m.backend = dler
+
+		_, code, e := m.apiAdd(APIAdd{URL: "one", TypeDL: "audio"})
+		assert.NoError(t, e, "add", test.name)
+		assert.Equal(t, http.StatusCreated, code, "add", test.name)
+		<-dler.started
+
+		m.Pause()
+		if !test.cancellable {
+			dler.release <- struct{}{}
+		}
+		for i := 0; i < 100 && m.State().Active > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, 0, m.State().Active, "stopped", test.name)
+		assert.Equal(t, test.queued, hist.Queued(), "queued", test.name)
+		assert.Len(t, hist.DoneList(), test.done, "done", test.name)
+	}
+}
+
+func TestRemoveQueued(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		cancellable bool
+		done        int
+	}{
+		{"cancelled", true, 0},
+		{"finished before the cancel", false, 1},
+	} {
+		dir, e := ioutil.TempDir("", "videodl")
+		if !assert.NoError(t, e, "TempDir") {
+			return
+		}
+		defer os.RemoveAll(dir)
+
+		dler := fakeDler{release: make(chan struct{}), started: make(chan struct{}), cancellable: test.cancellable}
+		hist := NewHistoryVideo(fakeApp{dir}, HistoryFile)
+		m := NewManager(nil, log.NewLog(log.Logs).SetName("videodl_test"), hist)
+		m.SetConfig(&Config{EnabledDL: true, Workers: 1})
+		m.backend = dler
+
+		vid, _, e := m.apiAdd(APIAdd{URL: "one", TypeDL: "audio"})
+		assert.NoError(t, e, "add", test.name)
+		<-dler.started
+
+		assert.NoError(t, m.RemoveQueued(vid), "remove", test.name)
+		if !test.cancellable {
+			dler.release <- struct{}{}
+		}
+		for i := 0; i < 100 && m.State().Active > 0; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.Equal(t, 0, m.State().Active, "stopped", test.name)
+		assert.Equal(t, 0, hist.Queued(), "queued", test.name)
+		assert.Len(t, hist.DoneList(), test.done, "done", test.name)
+	}
+}
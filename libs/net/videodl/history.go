@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"sync"
 	"time"
 )
 
@@ -15,6 +16,8 @@ type HistoryVideo struct {
 	history.History
 	Queue []*Video // still to do.
 	List  []*Video // already done.
+
+	mu sync.Mutex
 }
 
 // NewHistoryVideo creates a videodl history manager with the given file location.
@@ -28,33 +31,104 @@ func NewHistoryVideo(app history.AppletLike, filename string) *HistoryVideo {
 // Add adds a video to the download queue.
 //
 func (h *HistoryVideo) Add(q *Video) error {
+	h.mu.Lock()
 	h.Queue = append(h.Queue, q)
 	h.trim()
+	h.mu.Unlock()
 	return h.Save()
 }
 
-// Next returns the first video in the download queue.
+// Next returns the first video in the download queue not already downloading,
+// and marks it as downloading.
 //
 func (h *HistoryVideo) Next() (*Video, bool) {
-	if len(h.Queue) == 0 {
-		return nil, false
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, vid := range h.Queue {
+		if !vid.active {
+			vid.active = true
+			return vid, true
+		}
 	}
-	return h.Queue[0], true
+	return nil, false
 }
 
 // Queued returns the size of the download queue.
 //
 func (h *HistoryVideo) Queued() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	return len(h.Queue)
 }
 
-// Done moves the first item in the queue to the done list.
+// QueueList returns a copy of the download queue.
+//
+func (h *HistoryVideo) QueueList() []*Video {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*Video{}, h.Queue...)
+}
+
+// Done moves the video from the queue to the done list.
 //
-func (h *HistoryVideo) Done() error {
-	now := time.Now()
-	h.Queue[0].DateDone = &now
-	h.List = append(h.List, h.Queue[0])
-	h.Queue = h.Queue[1:]
+func (h *HistoryVideo) Done(vid *Video) error {
+	h.mu.Lock()
+	vid.active = false
+	e := h.Remove(vid, &h.Queue)
+	if e == nil {
+		now := time.Now()
+		vid.DateDone = &now
+		h.List = append(h.List, vid)
+	}
+	h.mu.Unlock()
+	if e != nil {
+		return e
+	}
+	return h.Save()
+}
+
+// Release releases a video that was not downloaded, and keeps it in the queue.
+//
+func (h *HistoryVideo) Release(vid *Video) {
+	h.mu.Lock()
+	vid.active = false
+	h.mu.Unlock()
+}
+
+// Move moves the video in the download queue.
+//
+func (h *HistoryVideo) Move(vid *Video, move QueueMove) error {
+	h.mu.Lock()
+	pos := -1
+	for i, test := range h.Queue {
+		if test == vid {
+			pos = i
+		}
+	}
+	if pos == -1 {
+		h.mu.Unlock()
+		return errors.New("HistoryVideo Move: item not found in queue")
+	}
+
+	newpos := pos
+	switch move {
+	case QueueMoveTop:
+		newpos = 0
+	case QueueMoveUp:
+		newpos = pos - 1
+	case QueueMoveDown:
+		newpos = pos + 1
+	case QueueMoveBottom:
+		newpos = len(h.Queue) - 1
+	}
+	if newpos < 0 || newpos >= len(h.Queue) || newpos == pos {
+		h.mu.Unlock()
+		return nil
+	}
+
+	h.Queue = append(h.Queue[:pos], h.Queue[pos+1:]...)
+	h.Queue = append(h.Queue[:newpos], append([]*Video{vid}, h.Queue[newpos:]...)...)
+	h.mu.Unlock()
 	return h.Save()
 }
 
@@ -70,9 +144,53 @@ func (h *HistoryVideo) Remove(vid *Video, list *[]*Video) error {
 	return errors.New("HistoryVideo Remove: item not found in list")
 }
 
+// DoneList returns a copy of the done list.
+//
+func (h *HistoryVideo) DoneList() []*Video {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*Video{}, h.List...)
+}
+
+// IsQueued returns whether the video is in the download queue.
+//
+func (h *HistoryVideo) IsQueued(vid *Video) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, test := range h.Queue {
+		if test == vid {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveDone removes the video from the done list.
+//
+func (h *HistoryVideo) RemoveDone(vid *Video) error {
+	h.mu.Lock()
+	e := h.Remove(vid, &h.List)
+	h.mu.Unlock()
+	if e != nil {
+		return e
+	}
+	return h.Save()
+}
+
+// SetViewed sets the viewed state of the video.
+//
+func (h *HistoryVideo) SetViewed(vid *Video, viewed bool) error {
+	h.mu.Lock()
+	vid.Viewed = viewed
+	h.mu.Unlock()
+	return h.Save()
+}
+
 // Find finds the video patching the given source url.
 //
 func (h *HistoryVideo) Find(url string) *Video {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	for i, vid := range h.List {
 		if vid.URL == url {
 			return h.List[i]
@@ -106,9 +224,10 @@ func (h *HistoryVideo) load() error {
 }
 
 func (h *HistoryVideo) save() error {
-	relist := append(h.List, h.Queue...)
-
-	data, e := json.MarshalIndent(relist, "", "\t")
+	h.mu.Lock()
+	relist := append(append([]*Video{}, h.List...), h.Queue...)
+	data, e := json.MarshalIndent(relist, "", "\t") // With the lock, like SetViewed.
+	h.mu.Unlock()
 	if e != nil {
 		return e
 	}
@@ -9,203 +9,246 @@ import (
 // indexHTML returns raw, uncompressed file data.
 func indexHTML() []byte {
 	gz, err := gzip.NewReader(bytes.NewBuffer([]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xc4, 0x59,
-		0xef, 0x6e, 0xdb, 0x38, 0x12, 0xff, 0x6c, 0x3f, 0x05, 0xab, 0xe6, 0x6a,
-		0x09, 0x49, 0xe4, 0xa4, 0x4d, 0x7b, 0x7b, 0x76, 0xec, 0x20, 0xf5, 0xa6,
-		0xb8, 0x02, 0xb9, 0xee, 0x6e, 0xaf, 0xb7, 0x87, 0x45, 0xd1, 0x0f, 0xb4,
-		0x44, 0xc5, 0x4c, 0x14, 0x51, 0x95, 0xa8, 0x38, 0xbe, 0x6e, 0xde, 0xfd,
-		0x66, 0x48, 0x8a, 0xa2, 0x24, 0xa7, 0x69, 0xba, 0x0b, 0x6c, 0x81, 0x46,
-		0x22, 0xe7, 0x37, 0xc3, 0xe1, 0xfc, 0xe3, 0x50, 0x3e, 0x7e, 0x12, 0x8b,
-		0x48, 0x6e, 0x72, 0x46, 0x56, 0xf2, 0x3a, 0x9d, 0x0f, 0x8f, 0xeb, 0x07,
-		0xa3, 0x31, 0x3c, 0x4a, 0xb9, 0x49, 0xd9, 0x7c, 0xb8, 0x14, 0xf1, 0x86,
-		0x7c, 0x19, 0x0e, 0x12, 0x91, 0xc9, 0xfd, 0x84, 0x5e, 0xf3, 0x74, 0x33,
-		0x21, 0x25, 0xcd, 0xca, 0xfd, 0x92, 0x15, 0x3c, 0x99, 0x0e, 0xef, 0x86,
-		0xc3, 0xa7, 0x79, 0x4a, 0x37, 0xac, 0x40, 0xd8, 0x9a, 0xc7, 0x72, 0x35,
-		0x79, 0x79, 0x70, 0x90, 0xdf, 0x22, 0xe9, 0xe9, 0x52, 0xdc, 0xa6, 0xbc,
-		0x94, 0x48, 0x5a, 0x8a, 0x22, 0x66, 0xc5, 0x7e, 0x41, 0x63, 0x5e, 0x95,
-		0x13, 0x72, 0xa8, 0x20, 0x83, 0x9c, 0xc6, 0x31, 0xcf, 0x2e, 0x26, 0xe4,
-		0x65, 0x7e, 0xab, 0xfe, 0xbf, 0xd0, 0xcf, 0x69, 0x2d, 0x8b, 0xbc, 0xd0,
-		0xc2, 0x06, 0x49, 0x2a, 0xa8, 0x9c, 0x90, 0x94, 0x25, 0x12, 0x46, 0xd7,
-		0xb4, 0xb8, 0xe0, 0xd9, 0x7e, 0xc1, 0x2f, 0x56, 0x30, 0x79, 0xa8, 0x39,
-		0xc4, 0x0d, 0x2b, 0x00, 0xb6, 0x9e, 0x10, 0x5a, 0x49, 0xa1, 0x75, 0x5b,
-		0x16, 0x62, 0x0d, 0xaa, 0xee, 0x91, 0xa7, 0x9f, 0x2b, 0x56, 0x31, 0x78,
-		0xd2, 0x48, 0x72, 0x91, 0xa1, 0x5a, 0x22, 0x83, 0x71, 0xc8, 0xb3, 0x44,
-		0x7c, 0x97, 0x82, 0xdf, 0xb0, 0x1c, 0xca, 0x5d, 0x31, 0xad, 0x64, 0x63,
-		0x95, 0x96, 0x06, 0x2e, 0xe4, 0xd0, 0xec, 0xd5, 0xa8, 0xa2, 0x2d, 0xf0,
-		0xdc, 0x9d, 0x8a, 0x44, 0x2a, 0x8a, 0x49, 0xc1, 0x62, 0x25, 0x28, 0xe1,
-		0x29, 0x53, 0xea, 0x97, 0x39, 0xcd, 0x1c, 0xfb, 0xbf, 0xfc, 0x9b, 0x35,
-		0x98, 0xb6, 0x97, 0x8b, 0x8d, 0xf9, 0x4d, 0x03, 0x3d, 0x72, 0xa0, 0xca,
-		0x98, 0xad, 0x6d, 0x10, 0x6a, 0x37, 0x42, 0x91, 0x27, 0xe6, 0x25, 0xfa,
-		0x7a, 0x42, 0x96, 0xa9, 0x88, 0xae, 0x80, 0x31, 0xaa, 0x8a, 0x12, 0xf4,
-		0x21, 0xb9, 0xe0, 0x99, 0x64, 0x85, 0x6b, 0x2f, 0x50, 0x9b, 0x1c, 0xb9,
-		0xaa, 0xd7, 0x86, 0xed, 0x1a, 0x6f, 0xc5, 0xe3, 0x98, 0x65, 0x8d, 0x4f,
-		0x97, 0x42, 0x4a, 0x71, 0xad, 0x04, 0xe8, 0x18, 0x32, 0xba, 0x7c, 0x21,
-		0x4b, 0x1a, 0x5d, 0x5d, 0x14, 0xa2, 0xca, 0xe2, 0x09, 0x79, 0xfa, 0xc3,
-		0xe2, 0x1f, 0xa7, 0xcf, 0x0f, 0xa6, 0xc4, 0x41, 0xd0, 0x30, 0xe6, 0x3d,
-		0xdc, 0xeb, 0xb3, 0xc5, 0x8f, 0x2f, 0x5f, 0x4c, 0x89, 0xb6, 0x1c, 0x68,
-		0x0e, 0xc4, 0x0e, 0x17, 0x9a, 0xa6, 0xcb, 0xf6, 0xf2, 0xf4, 0xd5, 0xd1,
-		0xc1, 0xa9, 0x65, 0x5b, 0xaf, 0xb8, 0x64, 0x5d, 0x36, 0xca, 0xd3, 0x0e,
-		0x1b, 0x3a, 0xe6, 0x7e, 0x96, 0xb0, 0x64, 0x29, 0x8b, 0x24, 0x8b, 0x3b,
-		0x5c, 0xcb, 0xb4, 0x62, 0x5b, 0xd8, 0x4c, 0x04, 0x75, 0xb6, 0xfd, 0xc3,
-		0xe1, 0xe2, 0xe8, 0x95, 0x43, 0xa7, 0x5d, 0xc4, 0xe2, 0xe8, 0xe8, 0xcd,
-		0xdf, 0x17, 0x2e, 0x22, 0x44, 0xa7, 0x81, 0x53, 0xba, 0xc8, 0x17, 0xaf,
-		0x0f, 0xcf, 0x5e, 0x2d, 0xbe, 0xb6, 0xc7, 0xc9, 0x0a, 0xfd, 0xd4, 0x04,
-		0x81, 0x1e, 0x77, 0xe5, 0x3c, 0x3f, 0x38, 0x38, 0x45, 0x9d, 0x7a, 0x72,
-		0x22, 0xa8, 0x1a, 0x85, 0x48, 0x4b, 0x60, 0x30, 0xce, 0x95, 0x22, 0x37,
-		0xa9, 0xd5, 0xa2, 0xd3, 0x6e, 0x5e, 0xaa, 0x19, 0x1b, 0xb2, 0x2a, 0xf8,
-		0xfa, 0xb1, 0xd6, 0x52, 0x82, 0x31, 0xd6, 0x49, 0xd7, 0x7b, 0x42, 0xef,
-		0x6e, 0xa8, 0x52, 0xfe, 0x4b, 0x9b, 0x5f, 0x14, 0x34, 0xbb, 0x60, 0x48,
-		0x3d, 0x1e, 0x9b, 0xda, 0x77, 0x5c, 0x46, 0x05, 0xcf, 0x25, 0x29, 0x8b,
-		0x68, 0xe6, 0xad, 0xa4, 0xcc, 0x27, 0xe3, 0xf1, 0x7a, 0xbd, 0x0e, 0x2f,
-		0x84, 0xb8, 0x48, 0x59, 0x18, 0x89, 0xeb, 0xf1, 0x65, 0x49, 0x73, 0xee,
-		0x11, 0x2c, 0xa3, 0x33, 0x4f, 0xb2, 0x5b, 0x39, 0xbe, 0xa4, 0x37, 0x54,
-		0xf3, 0x79, 0x73, 0x90, 0xa4, 0xde, 0x1a, 0x51, 0xf7, 0x00, 0x87, 0x37,
-		0xb4, 0x20, 0x85, 0x10, 0x92, 0xcc, 0x88, 0x37, 0xbe, 0xe1, 0x31, 0x13,
-		0x63, 0x6f, 0xaa, 0x66, 0x73, 0x2a, 0x57, 0x30, 0xfb, 0xf1, 0x93, 0x1e,
-		0x46, 0x34, 0x5a, 0x31, 0x18, 0x7f, 0xb9, 0xd3, 0x63, 0x1b, 0x51, 0x7a,
-		0x2a, 0xa9, 0x32, 0x65, 0x45, 0xc2, 0x33, 0x2e, 0xfd, 0x00, 0xcd, 0x06,
-		0xa9, 0x1d, 0xfb, 0x28, 0x25, 0x00, 0x7b, 0xec, 0xf8, 0x23, 0x53, 0xad,
-		0x47, 0x41, 0xb8, 0xe4, 0x59, 0xec, 0x8f, 0x58, 0x16, 0xb3, 0x78, 0xb4,
-		0x47, 0x32, 0x50, 0xaa, 0x86, 0x80, 0x11, 0x69, 0x9a, 0x02, 0x24, 0x4a,
-		0x79, 0x74, 0xe5, 0xc3, 0xf0, 0x34, 0x4d, 0x6b, 0x22, 0x02, 0x2d, 0xc9,
-		0xe5, 0x42, 0xd7, 0x15, 0xec, 0x1a, 0x22, 0x04, 0x1c, 0x68, 0x11, 0xad,
-		0xd9, 0x1a, 0x1a, 0xa5, 0x8c, 0x16, 0x48, 0xb1, 0x30, 0x3b, 0x53, 0x43,
-		0x44, 0xce, 0x32, 0x65, 0x09, 0x0b, 0xb1, 0x33, 0x35, 0x84, 0xc5, 0x5c,
-		0x7e, 0xae, 0x68, 0xca, 0xe5, 0xc6, 0x82, 0x9c, 0x39, 0x84, 0x95, 0x4c,
-		0xa6, 0x3c, 0xbb, 0x82, 0x1d, 0xf8, 0x01, 0x3a, 0xd7, 0x1a, 0xc8, 0x25,
-		0xa0, 0x99, 0x76, 0x42, 0x7a, 0x49, 0x6f, 0x7d, 0x78, 0x1b, 0x54, 0x45,
-		0x3a, 0xb1, 0x6e, 0x30, 0x20, 0x6f, 0x0f, 0x08, 0x65, 0x15, 0x45, 0xac,
-		0x84, 0x38, 0xaa, 0xa5, 0xf8, 0x31, 0x95, 0x14, 0xd8, 0x89, 0xde, 0x7e,
-		0x76, 0x15, 0x89, 0x7c, 0x43, 0x28, 0x28, 0x43, 0xa5, 0x2c, 0xfc, 0xd1,
-		0xaa, 0x60, 0x09, 0x98, 0x56, 0xa1, 0x20, 0xe0, 0x41, 0xc6, 0x5d, 0x5b,
-		0x8b, 0xc6, 0x39, 0xb5, 0xab, 0xce, 0xc1, 0x04, 0xd6, 0x5d, 0x38, 0xf1,
-		0x0b, 0x66, 0x5e, 0x3d, 0xe3, 0xb0, 0xe6, 0x22, 0xaf, 0x52, 0x2a, 0x99,
-		0x8f, 0x59, 0x52, 0x2a, 0x7e, 0x0c, 0x88, 0x9d, 0x25, 0x84, 0x02, 0xaa,
-		0x63, 0x72, 0x18, 0x74, 0x61, 0xd7, 0xb9, 0xdc, 0xe0, 0xf6, 0x07, 0x96,
-		0x19, 0xb7, 0xcd, 0xf7, 0x48, 0xa2, 0xd8, 0x06, 0x3c, 0x21, 0x7e, 0x12,
-		0xbe, 0xa3, 0xd7, 0xec, 0xe3, 0xc1, 0x27, 0x32, 0x9b, 0x91, 0x51, 0x38,
-		0x22, 0xbf, 0xff, 0x4e, 0xda, 0x73, 0x93, 0x51, 0x00, 0xf5, 0x4d, 0x56,
-		0x05, 0xd4, 0x6a, 0x02, 0xff, 0x70, 0x35, 0xac, 0xb7, 0x33, 0xc0, 0xbd,
-		0x2d, 0x7f, 0xe4, 0x98, 0x91, 0x4a, 0x85, 0x28, 0x85, 0x39, 0xa4, 0x9c,
-		0x10, 0x0f, 0x1e, 0x1e, 0x01, 0x6b, 0xa2, 0x92, 0x1e, 0x02, 0x92, 0xf0,
-		0x67, 0x1d, 0xd1, 0xb8, 0xa3, 0xf0, 0x12, 0x72, 0xd9, 0x1f, 0x8d, 0x47,
-		0x81, 0x26, 0x51, 0xad, 0xfb, 0x31, 0x85, 0xd4, 0xa1, 0x73, 0x50, 0x1d,
-		0x33, 0xc5, 0xa8, 0x16, 0x84, 0x68, 0x46, 0x7f, 0x84, 0x92, 0x46, 0xa8,
-		0x3a, 0x70, 0x0c, 0x42, 0xd8, 0xc8, 0x22, 0xa5, 0x65, 0x09, 0xf1, 0x03,
-		0x46, 0xcf, 0x21, 0x42, 0xe2, 0x0f, 0xc2, 0xdf, 0x59, 0x6a, 0xaa, 0x0e,
-		0x09, 0xd0, 0xe1, 0x44, 0xbd, 0x81, 0x8e, 0x13, 0xf5, 0xf2, 0x06, 0x64,
-		0xa8, 0x25, 0xf5, 0xce, 0xdf, 0x40, 0x21, 0x0f, 0x60, 0x17, 0xb4, 0x11,
-		0xe7, 0x61, 0x71, 0xf7, 0x10, 0x73, 0x37, 0x1c, 0x8c, 0xc7, 0x44, 0x19,
-		0x39, 0x84, 0xfa, 0x03, 0xea, 0xd4, 0xce, 0x87, 0x12, 0xb5, 0x54, 0x06,
-		0x04, 0xfa, 0x00, 0x55, 0xa7, 0x4a, 0xd1, 0x50, 0x8a, 0x73, 0xb1, 0x66,
-		0xc5, 0x82, 0x96, 0x4c, 0x59, 0x1d, 0xc9, 0xe8, 0x95, 0xe5, 0xfd, 0x64,
-		0xd4, 0x83, 0x92, 0x39, 0xca, 0xd3, 0x26, 0x26, 0x87, 0x2d, 0xca, 0xb1,
-		0x43, 0xd9, 0xaf, 0x49, 0x66, 0x7c, 0xa0, 0x87, 0x18, 0x59, 0xea, 0x65,
-		0x67, 0x69, 0x0c, 0xe1, 0x57, 0xb9, 0x1f, 0xa8, 0x5c, 0x09, 0x19, 0x94,
-		0x0d, 0x1d, 0x28, 0x7b, 0xe8, 0xfb, 0x7e, 0x10, 0x36, 0x61, 0xb7, 0x2d,
-		0x19, 0xb0, 0x34, 0xed, 0x7a, 0x98, 0x9d, 0x63, 0x6f, 0xb7, 0xed, 0x38,
-		0xcc, 0x0b, 0x74, 0xcc, 0x07, 0xa8, 0x6c, 0xe0, 0xe7, 0xcb, 0x52, 0x64,
-		0x5f, 0xcf, 0x15, 0x74, 0x8c, 0x36, 0x68, 0x0a, 0xa1, 0x5d, 0x6c, 0xfc,
-		0x76, 0xb4, 0x92, 0x3a, 0x5d, 0x10, 0x67, 0x63, 0x5c, 0x4d, 0x19, 0x4e,
-		0x55, 0x02, 0x3f, 0x82, 0x5e, 0x9f, 0x30, 0xcc, 0x80, 0x80, 0xd0, 0xbb,
-		0xad, 0xa9, 0xe5, 0xe4, 0xce, 0xfd, 0xdb, 0x52, 0x47, 0xdb, 0x1f, 0xdf,
-		0x97, 0xb3, 0x23, 0xdc, 0x90, 0x92, 0x0a, 0x62, 0x6c, 0xf2, 0xf7, 0x14,
-		0x6c, 0xf0, 0xd0, 0x25, 0xef, 0x91, 0x26, 0x8d, 0x61, 0xf8, 0x70, 0xce,
-		0x7e, 0x25, 0x8f, 0x54, 0x19, 0xf8, 0x8e, 0x54, 0xaa, 0xb3, 0x07, 0xd6,
-		0x6f, 0xaa, 0x32, 0xfc, 0x55, 0x4a, 0x9a, 0x54, 0x78, 0x20, 0x94, 0x1a,
-		0x3c, 0x56, 0x55, 0x87, 0x80, 0xa1, 0x88, 0x7a, 0x9b, 0x98, 0x55, 0x9a,
-		0x01, 0x1a, 0x52, 0x6d, 0xa6, 0x6a, 0xc4, 0x3c, 0x0c, 0x8d, 0x9e, 0x7a,
-		0x61, 0x6b, 0x5d, 0xbd, 0x5b, 0xb5, 0x49, 0x88, 0x06, 0x65, 0x91, 0xf6,
-		0x91, 0x76, 0xb7, 0x45, 0x03, 0x48, 0x74, 0x9f, 0x29, 0x4e, 0xcd, 0x58,
-		0x95, 0x2b, 0x70, 0x0a, 0xa4, 0x1e, 0x34, 0x21, 0x4c, 0xb6, 0xb6, 0x1f,
-		0x68, 0x8b, 0x4c, 0xdb, 0x07, 0x65, 0x57, 0x20, 0x16, 0x0c, 0x23, 0x11,
-		0xad, 0x9b, 0x28, 0xe3, 0x6e, 0x97, 0xa7, 0xce, 0x1c, 0xc4, 0xbf, 0x85,
-		0x26, 0xc3, 0x4f, 0x4c, 0x86, 0xc3, 0xdc, 0xaf, 0x9c, 0xad, 0x59, 0xac,
-		0x67, 0xf0, 0xfc, 0xaa, 0x8f, 0xee, 0x90, 0x06, 0xa1, 0x3e, 0x20, 0x4d,
-		0xe9, 0xa9, 0x09, 0x5e, 0xe0, 0xd6, 0x23, 0x2c, 0xa0, 0x4a, 0xb8, 0x3d,
-		0xf0, 0x93, 0x21, 0x96, 0xcc, 0x36, 0xaf, 0x86, 0x39, 0x7c, 0x8d, 0x30,
-		0x30, 0x06, 0x9c, 0xfc, 0x19, 0x5b, 0xfb, 0xe8, 0x70, 0xd0, 0x09, 0x40,
-		0x1f, 0xc4, 0xcf, 0x30, 0x87, 0x89, 0x7d, 0xaf, 0x7d, 0x94, 0x35, 0x86,
-		0xad, 0x83, 0xd3, 0xd9, 0x9d, 0xba, 0x15, 0x88, 0xa8, 0xba, 0x66, 0x99,
-		0x0c, 0x81, 0xf7, 0x2c, 0x65, 0xf8, 0xfa, 0x7a, 0xf3, 0x36, 0xf6, 0xbd,
-		0x1b, 0xb5, 0x63, 0x50, 0x87, 0x67, 0x19, 0x2b, 0xfe, 0xf9, 0xe1, 0x5f,
-		0xe7, 0x33, 0xcf, 0x23, 0x04, 0x96, 0x5e, 0xe0, 0x69, 0x4f, 0x34, 0x3d,
-		0xbc, 0xff, 0xf0, 0xad, 0x1b, 0xc0, 0xf1, 0x09, 0xfa, 0x65, 0xd6, 0xcd,
-		0xd1, 0x5d, 0xef, 0x19, 0xc0, 0x61, 0x3a, 0x09, 0xff, 0xf3, 0xfe, 0xfc,
-		0xbb, 0x2a, 0x51, 0x12, 0x6a, 0xb7, 0xcc, 0x70, 0xce, 0xbc, 0xe3, 0x7c,
-		0xdb, 0x5f, 0x38, 0x83, 0x49, 0x5d, 0xb0, 0x52, 0xa4, 0x15, 0xca, 0xa8,
-		0x13, 0x4a, 0xb1, 0xbd, 0xb7, 0xd3, 0x41, 0x8d, 0x54, 0xfa, 0xb3, 0x2c,
-		0x12, 0xd8, 0x8d, 0xb6, 0xc0, 0xbf, 0x22, 0xe5, 0xcc, 0x50, 0x2c, 0x9e,
-		0x56, 0x31, 0xdf, 0x8e, 0x3f, 0x45, 0xca, 0x76, 0xfc, 0x92, 0xcb, 0x02,
-		0xaa, 0x63, 0x1f, 0xfe, 0x5a, 0x13, 0x2c, 0x1a, 0xa8, 0x2c, 0x2b, 0xbb,
-		0x6a, 0x9f, 0xd5, 0xb3, 0x16, 0x57, 0xf2, 0xff, 0xb5, 0xa5, 0xfd, 0x1b,
-		0x26, 0x02, 0xb7, 0xbc, 0xb6, 0xe2, 0xc0, 0x5a, 0x08, 0x6c, 0x59, 0x77,
-		0x04, 0xbc, 0xd4, 0x5e, 0x55, 0x6d, 0x81, 0x06, 0x4c, 0x4d, 0x67, 0x12,
-		0xb3, 0x52, 0x9a, 0xe6, 0x44, 0x63, 0x46, 0xa6, 0x91, 0xeb, 0x14, 0x29,
-		0xdf, 0xca, 0x80, 0x06, 0xe2, 0x37, 0x56, 0x7a, 0x13, 0xef, 0x9d, 0xf0,
-		0x82, 0x7e, 0x36, 0x38, 0x07, 0x3e, 0xca, 0xee, 0xd5, 0x0e, 0x4c, 0x58,
-		0x02, 0xda, 0x3b, 0xe1, 0xf5, 0x07, 0xe2, 0x0b, 0x46, 0x5a, 0x2d, 0x98,
-		0x78, 0x52, 0xab, 0x88, 0x01, 0xb6, 0x35, 0xea, 0xbe, 0x1e, 0x76, 0xed,
-		0xb8, 0x53, 0x33, 0xbd, 0xaa, 0xa1, 0xac, 0xae, 0xcc, 0xde, 0xb3, 0x7d,
-		0x3b, 0x73, 0x93, 0xa6, 0xf9, 0xcb, 0x8d, 0x7d, 0xcd, 0xe9, 0x63, 0x2c,
-		0xaf, 0xf2, 0x5e, 0xac, 0x81, 0xe6, 0xef, 0xe4, 0x70, 0xdb, 0xc5, 0xb6,
-		0x1f, 0x9b, 0xd4, 0x94, 0x65, 0x17, 0x78, 0x86, 0xcc, 0xc8, 0x41, 0x60,
-		0x9d, 0xf4, 0xc8, 0x73, 0xc3, 0xf1, 0x41, 0xae, 0xc6, 0xdb, 0x7c, 0x80,
-		0x0a, 0x34, 0xe5, 0x65, 0xaa, 0x7a, 0x15, 0xd5, 0xd9, 0x18, 0xcd, 0x02,
-		0x58, 0xd7, 0x30, 0xb6, 0x8b, 0xaf, 0xbe, 0x79, 0x98, 0x0e, 0xbd, 0x69,
-		0x13, 0xcc, 0x9d, 0x1d, 0x7b, 0x5b, 0x75, 0x1a, 0xd5, 0x8b, 0xc1, 0xf1,
-		0xa8, 0x8b, 0xf4, 0xa0, 0x57, 0xdb, 0x7a, 0x45, 0xad, 0x7b, 0x70, 0x68,
-		0x1d, 0x53, 0x6b, 0xcb, 0x0c, 0x36, 0xac, 0xcb, 0x7c, 0xba, 0xe5, 0xc0,
-		0xa8, 0x2d, 0xab, 0xcf, 0xe0, 0x2d, 0x20, 0x3c, 0x9d, 0x0d, 0x08, 0x62,
-		0x08, 0x30, 0xba, 0xd9, 0x40, 0xaa, 0xe9, 0x35, 0x76, 0x21, 0xc4, 0x76,
-		0x33, 0x2d, 0xcb, 0xfa, 0x4c, 0x5d, 0x1e, 0xdc, 0x8a, 0x3e, 0x32, 0x77,
-		0x77, 0x93, 0x2b, 0xb8, 0x8e, 0x4d, 0x83, 0x86, 0xd6, 0xb9, 0xd9, 0xe9,
-		0xdb, 0x07, 0xdc, 0x5b, 0xc1, 0x4b, 0xb0, 0x7a, 0x7f, 0xa3, 0xfa, 0x14,
-		0x80, 0xad, 0xaa, 0x83, 0xe0, 0x9b, 0xb6, 0x6b, 0x70, 0x8f, 0xde, 0x31,
-		0xe6, 0x89, 0x4a, 0xd7, 0x5d, 0xef, 0x81, 0x2c, 0xb3, 0x07, 0x22, 0x24,
-		0x9b, 0x5e, 0xf0, 0x1b, 0xcd, 0xa2, 0x90, 0x7f, 0x82, 0x65, 0xf0, 0x32,
-		0xeb, 0x37, 0xc9, 0x84, 0x43, 0x37, 0x9f, 0x9a, 0x0f, 0x29, 0x20, 0x48,
-		0x63, 0x4d, 0x18, 0x2b, 0xa8, 0x49, 0xa7, 0x40, 0x47, 0xd2, 0x8e, 0xb9,
-		0x19, 0xbb, 0x1d, 0xa9, 0x7b, 0x13, 0x7e, 0xe8, 0xd6, 0xe9, 0x40, 0x1f,
-		0xaa, 0x4e, 0xae, 0xdd, 0xbe, 0xeb, 0x10, 0x6c, 0x5f, 0x00, 0xb6, 0x76,
-		0xd3, 0xf6, 0x7a, 0xee, 0x7f, 0xd3, 0x4d, 0xb3, 0xcb, 0xa9, 0xec, 0xf7,
-		0x68, 0x56, 0x7b, 0xe1, 0x77, 0x38, 0x59, 0x97, 0x73, 0xea, 0xb4, 0x64,
-		0xac, 0xd3, 0x89, 0x35, 0xb1, 0x58, 0x1b, 0xd6, 0x8a, 0x7c, 0x8c, 0x51,
-		0xa7, 0x7d, 0x47, 0xc1, 0x9f, 0x47, 0x9b, 0xba, 0x6f, 0x55, 0xe7, 0x63,
-		0xc5, 0x9f, 0xb7, 0x45, 0x47, 0xe8, 0x5f, 0xb9, 0x49, 0xf3, 0x95, 0x4c,
-		0x75, 0xd5, 0xde, 0xe5, 0x67, 0xbc, 0x13, 0x78, 0x7b, 0xc4, 0x3b, 0xc4,
-		0x4b, 0xb5, 0xa1, 0xc1, 0x91, 0xf7, 0x53, 0x76, 0x0e, 0x80, 0x05, 0x4d,
-		0x53, 0xfc, 0x18, 0xe7, 0xe3, 0x67, 0x2b, 0xa0, 0x3b, 0x1f, 0xce, 0xc6,
-		0xe6, 0xf7, 0x08, 0xfc, 0x21, 0x02, 0x1e, 0x6a, 0x8b, 0x84, 0xc3, 0x19,
-		0xac, 0x53, 0xda, 0x23, 0xcd, 0x37, 0xc4, 0x4a, 0x8a, 0x65, 0x95, 0x24,
-		0xac, 0x00, 0x5c, 0x3e, 0xff, 0xef, 0x8a, 0xca, 0x13, 0xf2, 0x9b, 0xa8,
-		0x0a, 0x52, 0x9f, 0x1a, 0xb1, 0x60, 0x65, 0x36, 0x92, 0xa4, 0xac, 0xf2,
-		0x1c, 0x6e, 0xf2, 0xe4, 0x59, 0x2a, 0xa7, 0x4a, 0xe0, 0xb3, 0x0b, 0x39,
-		0x3d, 0x79, 0x42, 0xce, 0xf1, 0x7a, 0x7e, 0x3c, 0xce, 0x71, 0x59, 0x35,
-		0x0f, 0x2f, 0xf8, 0xb5, 0x1c, 0x97, 0x33, 0xbf, 0x64, 0x78, 0xf3, 0xe1,
-		0xc0, 0xce, 0x35, 0x5f, 0x2c, 0xa5, 0xc8, 0x91, 0x32, 0x80, 0x4b, 0x0d,
-		0x12, 0x5a, 0xe9, 0xeb, 0xcd, 0xdf, 0xab, 0x57, 0xfb, 0xad, 0x0e, 0x0f,
-		0xd6, 0x06, 0x6b, 0x33, 0xcb, 0x9b, 0xeb, 0xae, 0x18, 0x33, 0x51, 0x43,
-		0x8e, 0xc7, 0xb0, 0x92, 0xbb, 0xa0, 0xd9, 0x08, 0x7e, 0x59, 0xec, 0x50,
-		0x5a, 0x1f, 0x4f, 0x5d, 0x55, 0x6c, 0xc0, 0x7b, 0xf3, 0x9f, 0xe0, 0x95,
-		0xa8, 0xf6, 0xb3, 0xad, 0x81, 0x13, 0x30, 0xde, 0xfc, 0x0c, 0x06, 0xe4,
-		0x17, 0x3d, 0xba, 0x47, 0x8b, 0xba, 0x6b, 0xd2, 0xcb, 0xe0, 0x4f, 0x0f,
-		0x73, 0xdd, 0xcc, 0x80, 0xdb, 0x70, 0x60, 0x81, 0xa6, 0xff, 0xb7, 0xda,
-		0x1a, 0x70, 0xd3, 0x2e, 0x77, 0x19, 0x9a, 0xfe, 0xba, 0xc7, 0xd4, 0x6a,
-		0x9b, 0xfb, 0x0b, 0x39, 0xdd, 0x76, 0x8f, 0xb5, 0xd5, 0x41, 0x77, 0x59,
-		0x5b, 0x8d, 0xf7, 0x76, 0x56, 0xd3, 0x4d, 0x6f, 0xe5, 0x34, 0x2d, 0x78,
-		0x8f, 0xd1, 0xf6, 0xd6, 0x5d, 0x2e, 0xdb, 0x8a, 0xf7, 0x58, 0xb0, 0xd7,
-		0xee, 0xa2, 0xb1, 0x21, 0x77, 0xbc, 0xad, 0x9f, 0xf5, 0xa3, 0x06, 0xa9,
-		0xe2, 0x6a, 0x51, 0xc7, 0x4f, 0xf6, 0xf7, 0x1b, 0x5a, 0x9d, 0x1d, 0xe0,
-		0x2b, 0xe3, 0x6d, 0xfd, 0x6d, 0xd7, 0x9b, 0x9f, 0xc6, 0x31, 0x81, 0x17,
-		0xe5, 0x63, 0x43, 0xc2, 0x23, 0xcb, 0x9b, 0xbf, 0x83, 0xbf, 0x7a, 0x56,
-		0x0b, 0xdc, 0xdf, 0x37, 0x6b, 0x99, 0xeb, 0x7a, 0xed, 0xfa, 0x73, 0x9e,
-		0x5d, 0x11, 0x29, 0x88, 0xfa, 0xe6, 0x09, 0xcf, 0x0d, 0xa6, 0x5a, 0xb9,
-		0x82, 0xc4, 0x8a, 0x2a, 0x59, 0x92, 0x25, 0x2d, 0x26, 0xc4, 0x89, 0x9a,
-		0xfa, 0xf3, 0x28, 0x28, 0x4a, 0xb5, 0x37, 0x91, 0x09, 0xee, 0x8e, 0x57,
-		0xb8, 0x58, 0x67, 0x6b, 0x63, 0x93, 0xee, 0x63, 0xfd, 0xa3, 0xe4, 0xff,
-		0x03, 0x00, 0x00, 0xff, 0xff, 0x5a, 0xd0, 0xc0, 0x43, 0xac, 0x1c, 0x00,
-		0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xc5, 0x1a,
+		0x6d, 0x53, 0xdb, 0x46, 0xf3, 0x33, 0xfe, 0x15, 0x17, 0x85, 0x62, 0x79,
+		0x0c, 0x32, 0x24, 0x90, 0xa6, 0x36, 0x36, 0x43, 0x08, 0x99, 0x66, 0x86,
+		0xa6, 0x69, 0x92, 0xa7, 0x99, 0x4e, 0xa6, 0x1f, 0xce, 0xd2, 0x19, 0x0b,
+		0x84, 0x4e, 0x91, 0x4e, 0x18, 0x9e, 0x94, 0xff, 0xfe, 0xec, 0xee, 0x9d,
+		0x4e, 0x27, 0xd9, 0x4e, 0x20, 0xcd, 0xcc, 0xd3, 0x99, 0x60, 0xe9, 0xf6,
+		0xe5, 0xf6, 0x7d, 0xf7, 0x4e, 0x3d, 0x7c, 0x14, 0xc9, 0x50, 0xdd, 0x66,
+		0x82, 0xcd, 0xd5, 0x55, 0x32, 0xe9, 0x1c, 0x56, 0x3f, 0x82, 0x47, 0xf0,
+		0x53, 0xa8, 0xdb, 0x44, 0x4c, 0x3a, 0x53, 0x19, 0xdd, 0xb2, 0x2f, 0x9d,
+		0x8d, 0x99, 0x4c, 0xd5, 0xce, 0x8c, 0x5f, 0xc5, 0xc9, 0xed, 0x90, 0x15,
+		0x3c, 0x2d, 0x76, 0x0a, 0x91, 0xc7, 0xb3, 0x51, 0xe7, 0xae, 0xd3, 0x79,
+		0x9c, 0x25, 0xfc, 0x56, 0xe4, 0x88, 0xb6, 0x88, 0x23, 0x35, 0x1f, 0x1e,
+		0xec, 0xee, 0x66, 0x37, 0x08, 0x7a, 0x3c, 0x95, 0x37, 0x49, 0x5c, 0x28,
+		0x04, 0x4d, 0x65, 0x1e, 0x89, 0x7c, 0x27, 0xe7, 0x51, 0x5c, 0x16, 0x43,
+		0xb6, 0x47, 0x28, 0x1b, 0x19, 0x8f, 0xa2, 0x38, 0x3d, 0x1f, 0xb2, 0x83,
+		0xec, 0x86, 0xfe, 0x3d, 0xd5, 0xbf, 0xa3, 0x8a, 0x17, 0x7b, 0xaa, 0x99,
+		0x6d, 0xcc, 0x12, 0xc9, 0xd5, 0x90, 0x25, 0x62, 0xa6, 0xe0, 0xed, 0x8a,
+		0xe7, 0xe7, 0x71, 0xba, 0x93, 0xc7, 0xe7, 0x73, 0x58, 0xdc, 0xd3, 0x14,
+		0xf2, 0x5a, 0xe4, 0x80, 0xb6, 0x18, 0x32, 0x5e, 0x2a, 0xa9, 0x65, 0x9b,
+		0xe6, 0x72, 0x01, 0xa2, 0x6e, 0xb3, 0xc7, 0x9f, 0x4b, 0x51, 0x0a, 0xf8,
+		0xe5, 0xa1, 0x8a, 0x65, 0x8a, 0x62, 0xc9, 0x14, 0xde, 0x83, 0x38, 0x9d,
+		0xc9, 0xef, 0x12, 0xf0, 0x1e, 0xdb, 0x21, 0xdf, 0xb9, 0xd0, 0x42, 0xd6,
+		0x56, 0x69, 0x48, 0xe0, 0xa2, 0xec, 0x19, 0x5d, 0x8d, 0x28, 0xda, 0x02,
+		0x4f, 0xdc, 0xa5, 0x50, 0x26, 0x32, 0x1f, 0xe6, 0x22, 0x22, 0x46, 0xb3,
+		0x38, 0x11, 0x24, 0x7e, 0x91, 0xf1, 0xd4, 0xb1, 0xff, 0xc1, 0x4f, 0xd6,
+		0x60, 0xda, 0x5e, 0x2e, 0x6e, 0x14, 0x5f, 0xd7, 0xa8, 0xfb, 0x0e, 0x2a,
+		0x19, 0xb3, 0xa1, 0x06, 0xe3, 0x56, 0x11, 0x8e, 0x34, 0x51, 0x5c, 0xa0,
+		0xaf, 0x87, 0x6c, 0x9a, 0xc8, 0xf0, 0x12, 0x08, 0xc3, 0x32, 0x2f, 0x40,
+		0x1e, 0x96, 0xc9, 0x38, 0x55, 0x22, 0x77, 0xed, 0x05, 0x62, 0xb3, 0x7d,
+		0x57, 0xf4, 0xca, 0xb0, 0x6d, 0xe3, 0xcd, 0xe3, 0x28, 0x12, 0x69, 0xed,
+		0xd3, 0xa9, 0x54, 0x4a, 0x5e, 0x11, 0x03, 0x1d, 0x43, 0x46, 0x96, 0x2f,
+		0x6c, 0xca, 0xc3, 0xcb, 0xf3, 0x5c, 0x96, 0x69, 0x34, 0x64, 0x8f, 0x9f,
+		0x9f, 0xfc, 0x72, 0xfc, 0x64, 0x77, 0xc4, 0x1c, 0x0c, 0x1e, 0x44, 0xf1,
+		0x12, 0xde, 0x8b, 0xd3, 0x93, 0x97, 0x07, 0x4f, 0x47, 0x4c, 0x5b, 0x0e,
+		0x24, 0x07, 0x60, 0x8b, 0x0a, 0x4d, 0xd3, 0x26, 0x3b, 0x38, 0x7e, 0xb6,
+		0xbf, 0x7b, 0x6c, 0xc9, 0x16, 0xf3, 0x58, 0x89, 0x36, 0x19, 0x8f, 0x93,
+		0x16, 0x19, 0x3a, 0x66, 0x3d, 0x49, 0x50, 0x88, 0x44, 0x84, 0x4a, 0x44,
+		0x2d, 0xaa, 0x69, 0x52, 0x8a, 0x15, 0x64, 0x26, 0x82, 0x5a, 0x6a, 0x3f,
+		0xdf, 0x3b, 0xd9, 0x7f, 0xe6, 0xc0, 0x79, 0x1b, 0xe3, 0x64, 0x7f, 0xff,
+		0xd5, 0xcf, 0x27, 0x2e, 0x46, 0x80, 0x4e, 0x03, 0xa7, 0xb4, 0x31, 0x9f,
+		0xbe, 0xd8, 0x3b, 0x7d, 0x76, 0xb2, 0x76, 0x67, 0x1e, 0x60, 0xa0, 0x5e,
+		0x8b, 0x07, 0x92, 0x61, 0x2c, 0x06, 0x57, 0x92, 0xe8, 0x4c, 0xd2, 0xea,
+		0xc8, 0x5a, 0x85, 0x32, 0x05, 0xa4, 0x76, 0x10, 0x31, 0x1b, 0x43, 0xbb,
+		0x98, 0x6d, 0x35, 0x5d, 0x08, 0x45, 0x28, 0x97, 0x49, 0xf1, 0x5d, 0xd9,
+		0xda, 0x8a, 0xae, 0x03, 0x13, 0x5d, 0x4d, 0xc6, 0x14, 0xe5, 0xcb, 0x41,
+		0xdd, 0x50, 0x5f, 0x08, 0xd1, 0xda, 0x69, 0x4d, 0x8c, 0xbb, 0xc1, 0x32,
+		0x9c, 0x63, 0xc0, 0xd7, 0xd9, 0xa4, 0xdf, 0xdb, 0x96, 0x7d, 0xb2, 0xbb,
+		0x7b, 0x8c, 0xce, 0x5d, 0xb2, 0x6c, 0xad, 0x39, 0x33, 0x7a, 0x28, 0x99,
+		0x19, 0xad, 0x1b, 0x70, 0xde, 0x2e, 0x70, 0xb4, 0x62, 0x73, 0xff, 0x07,
+		0xeb, 0x47, 0xb5, 0xf3, 0x4b, 0x93, 0x5e, 0xe6, 0x3c, 0x3d, 0x17, 0x08,
+		0x3d, 0x1c, 0x98, 0x26, 0x72, 0x58, 0x84, 0x79, 0x9c, 0x29, 0x56, 0xe4,
+		0xe1, 0xd8, 0x9b, 0x2b, 0x95, 0x0d, 0x07, 0x83, 0xc5, 0x62, 0x11, 0x9c,
+		0x4b, 0x79, 0x9e, 0x88, 0x20, 0x94, 0x57, 0x83, 0x8b, 0x82, 0x67, 0xb1,
+		0xc7, 0xb0, 0x1f, 0x8d, 0x3d, 0x25, 0x6e, 0xd4, 0xe0, 0x82, 0x5f, 0x73,
+		0x4d, 0xe7, 0x4d, 0x80, 0x13, 0x3d, 0xd5, 0xac, 0xd6, 0x20, 0x76, 0xae,
+		0x79, 0xce, 0x72, 0x29, 0x15, 0x1b, 0x33, 0x6f, 0x70, 0x1d, 0x47, 0x42,
+		0x0e, 0xbc, 0x11, 0xad, 0x66, 0x5c, 0xcd, 0x61, 0xf5, 0xd3, 0xdf, 0xfa,
+		0x35, 0xe4, 0xe1, 0x5c, 0xc0, 0xfb, 0x97, 0x3b, 0xfd, 0x6e, 0x53, 0x53,
+		0x2f, 0xcd, 0xca, 0x94, 0xac, 0xc8, 0xe2, 0x34, 0x56, 0x7e, 0x0f, 0xcd,
+		0x06, 0xa1, 0x1c, 0xf9, 0xc8, 0xa5, 0x07, 0xf6, 0xd8, 0xf4, 0xbb, 0xa6,
+		0xed, 0x75, 0x7b, 0xc1, 0x34, 0x4e, 0x23, 0xbf, 0x2b, 0xd2, 0x48, 0x44,
+		0xdd, 0x6d, 0x96, 0x82, 0x50, 0x15, 0x0a, 0x18, 0x91, 0x27, 0x09, 0xa0,
+		0x84, 0x49, 0x1c, 0x5e, 0xfa, 0xf0, 0x7a, 0x9c, 0x24, 0x15, 0x10, 0x11,
+		0x2d, 0xc8, 0xa5, 0x42, 0xd7, 0xe5, 0x02, 0xf3, 0x03, 0x1c, 0x68, 0x31,
+		0x1a, 0xab, 0x15, 0x6a, 0x98, 0x08, 0x9e, 0x23, 0xc4, 0xa2, 0xd9, 0x95,
+		0x0a, 0x45, 0x66, 0x22, 0x25, 0x4b, 0x58, 0x14, 0xbb, 0x52, 0xa1, 0x88,
+		0x28, 0x56, 0x9f, 0x4b, 0x9e, 0xc4, 0xea, 0xd6, 0x22, 0x39, 0x6b, 0x56,
+		0x61, 0x5e, 0x16, 0xb5, 0x3c, 0x4a, 0x9e, 0x83, 0xf7, 0xde, 0xe2, 0x1a,
+		0x22, 0x14, 0x42, 0x25, 0x71, 0x7a, 0x09, 0x2a, 0xfa, 0xf8, 0x8a, 0xd6,
+		0x7a, 0xaf, 0xb8, 0x12, 0xbe, 0x01, 0xbe, 0xc6, 0x58, 0xbb, 0xe6, 0x89,
+		0x5f, 0x99, 0x16, 0xad, 0xca, 0x10, 0xed, 0x0f, 0x4c, 0x09, 0x63, 0x59,
+		0xe6, 0xd2, 0xb1, 0xbb, 0x6d, 0xec, 0x9a, 0xbb, 0x3d, 0x8c, 0x26, 0xeb,
+		0x11, 0x77, 0x23, 0xf4, 0xcb, 0x66, 0xc0, 0x2f, 0xf8, 0x8d, 0x0f, 0x4f,
+		0x1b, 0x65, 0x9e, 0x0c, 0xad, 0xdf, 0x0d, 0x92, 0xb7, 0x0d, 0x80, 0xa2,
+		0x0c, 0x43, 0x51, 0x40, 0xe0, 0xda, 0xcd, 0x23, 0xae, 0x38, 0x0a, 0xa0,
+		0xed, 0x9d, 0x5e, 0x86, 0x32, 0xbb, 0x65, 0x1c, 0x94, 0xe3, 0x4a, 0xe5,
+		0x7e, 0x77, 0x9e, 0x8b, 0x19, 0xf8, 0x92, 0xb0, 0x50, 0x8e, 0xce, 0xc6,
+		0x5d, 0x53, 0x8a, 0x3a, 0x1a, 0xaa, 0xd8, 0x38, 0x03, 0x9b, 0xdb, 0xf8,
+		0x68, 0xeb, 0xe5, 0x92, 0x66, 0x32, 0x2b, 0x13, 0xd4, 0x10, 0xd3, 0xb2,
+		0x20, 0x7a, 0x8c, 0xc0, 0xcd, 0x29, 0xc4, 0x1e, 0x8a, 0x63, 0x8a, 0x06,
+		0xc8, 0x22, 0xae, 0x32, 0x75, 0x4b, 0x06, 0xb4, 0xc4, 0xa8, 0x76, 0xbc,
+		0xcd, 0x66, 0x44, 0xb6, 0x11, 0xcf, 0x98, 0x3f, 0x0b, 0xde, 0xf0, 0x2b,
+		0xf1, 0x69, 0xf7, 0x6f, 0x36, 0x1e, 0xb3, 0x6e, 0xd0, 0x65, 0xff, 0xfc,
+		0xc3, 0x9a, 0x6b, 0xc3, 0x6e, 0x0f, 0x3a, 0x93, 0x2a, 0x73, 0xe8, 0xb2,
+		0x0c, 0xfe, 0xc3, 0xdd, 0xb0, 0x53, 0x8e, 0x01, 0xef, 0x75, 0xf1, 0x32,
+		0xc6, 0x12, 0x40, 0x22, 0x84, 0x09, 0xac, 0x21, 0xe4, 0x88, 0x79, 0xf0,
+		0xe3, 0x31, 0xb0, 0x26, 0x0a, 0xe9, 0x21, 0xc2, 0x2c, 0x78, 0xab, 0x53,
+		0x08, 0x35, 0x0a, 0x2e, 0xa0, 0x78, 0xf8, 0xdd, 0x41, 0xb7, 0xa7, 0x41,
+		0x5c, 0xcb, 0x7e, 0xc8, 0x21, 0x57, 0xf9, 0x04, 0x44, 0xc7, 0xd4, 0x34,
+		0xa2, 0xf5, 0x02, 0x34, 0xa3, 0xdf, 0x45, 0x4e, 0x5d, 0x14, 0x1d, 0x28,
+		0x36, 0x02, 0x50, 0xe4, 0x24, 0xe1, 0x45, 0x01, 0x01, 0x0b, 0x46, 0xcf,
+		0x20, 0x24, 0xa3, 0x0f, 0xd2, 0xdf, 0x9c, 0x6a, 0xa8, 0x0e, 0x31, 0x90,
+		0xe1, 0x88, 0x9e, 0x40, 0xc6, 0x21, 0x3d, 0xbc, 0x02, 0x1e, 0xb4, 0xa5,
+		0xd6, 0xfc, 0x15, 0xb4, 0xe0, 0x1e, 0x68, 0xc1, 0x6b, 0x76, 0x1e, 0xb6,
+		0x65, 0x0f, 0x71, 0xee, 0x3a, 0x1b, 0x83, 0x01, 0x23, 0x23, 0x07, 0x50,
+		0xf0, 0x54, 0x1d, 0x79, 0x50, 0x13, 0xa7, 0x64, 0x40, 0x80, 0x6f, 0xa0,
+		0xe8, 0x9c, 0x04, 0x0d, 0x94, 0x3c, 0x93, 0x0b, 0x91, 0x9f, 0xf0, 0x42,
+		0x87, 0x2d, 0x82, 0xd1, 0x2b, 0xd3, 0xf5, 0x60, 0x94, 0x83, 0xb3, 0x09,
+		0xf2, 0xd3, 0x26, 0x66, 0x7b, 0x0d, 0xc8, 0xa1, 0x03, 0xd9, 0xa9, 0x40,
+		0xe6, 0x7d, 0x57, 0xbf, 0x62, 0x64, 0xd1, 0xc3, 0xe6, 0xd4, 0x18, 0xc2,
+		0x2f, 0x33, 0xbf, 0x47, 0x59, 0x17, 0x08, 0xa8, 0x53, 0x3a, 0x50, 0xb6,
+		0xd1, 0xf7, 0xcb, 0x41, 0x58, 0x87, 0xdd, 0xaa, 0x64, 0xc0, 0x5a, 0xd8,
+		0xf7, 0xb0, 0x1c, 0x0c, 0xbc, 0x7e, 0xd3, 0x71, 0x98, 0x17, 0xe8, 0x98,
+		0x0f, 0x50, 0x4a, 0xc1, 0xcf, 0x17, 0x85, 0x4c, 0xbf, 0x9e, 0x2b, 0xe8,
+		0x18, 0x6d, 0xd0, 0x04, 0x42, 0x3b, 0xbf, 0xf5, 0x9b, 0xd1, 0xca, 0xaa,
+		0x74, 0x41, 0x3c, 0x1b, 0xe3, 0xb4, 0x64, 0x28, 0xa9, 0xe6, 0x7e, 0x02,
+		0xb9, 0xfe, 0xc6, 0x30, 0x03, 0x00, 0xa2, 0xde, 0xad, 0x4c, 0x2d, 0x27,
+		0x77, 0xd6, 0xab, 0x45, 0xbd, 0xf4, 0xdf, 0xeb, 0xe5, 0x68, 0x84, 0x0a,
+		0x11, 0x57, 0x60, 0x63, 0x93, 0x7f, 0x49, 0xc0, 0x1a, 0x1f, 0xce, 0x37,
+		0xdb, 0xac, 0x4e, 0x63, 0x78, 0xfd, 0x76, 0xce, 0x7e, 0x25, 0x8f, 0x30,
+		0x07, 0x31, 0x71, 0x28, 0x33, 0x8f, 0xf5, 0xf0, 0x75, 0x64, 0x92, 0xb9,
+		0xef, 0x31, 0xe6, 0xf5, 0x81, 0x38, 0x97, 0xe7, 0x39, 0xe8, 0xd1, 0xf7,
+		0x7e, 0xc2, 0xfc, 0xd4, 0xc0, 0x8a, 0x78, 0x73, 0x75, 0x1e, 0xe2, 0x9f,
+		0x35, 0x59, 0x58, 0x25, 0x1e, 0x88, 0x5e, 0x77, 0x10, 0xf8, 0x4b, 0xfa,
+		0x39, 0x99, 0xa6, 0xa5, 0xe9, 0xc1, 0x0e, 0x4e, 0xaa, 0xe9, 0xf9, 0xd0,
+		0xb3, 0xb2, 0x6f, 0x5e, 0x99, 0xed, 0xe9, 0x3c, 0x12, 0x22, 0xd2, 0xd8,
+		0xc3, 0x8e, 0x45, 0x2d, 0x1c, 0xd6, 0x50, 0xa2, 0x2c, 0x17, 0x55, 0xb2,
+		0xeb, 0x60, 0x31, 0x41, 0xfe, 0xc5, 0x83, 0x99, 0xc6, 0x03, 0x8f, 0x6d,
+		0x3d, 0xbe, 0x79, 0xb2, 0x77, 0xf2, 0x7c, 0xe4, 0x6d, 0x33, 0xaf, 0xac,
+		0x57, 0x7e, 0xd9, 0xa3, 0x95, 0x48, 0x2e, 0xd2, 0x7a, 0xed, 0x29, 0xad,
+		0xe9, 0xa1, 0xae, 0xa6, 0x3d, 0x1e, 0x79, 0xd0, 0x3a, 0xac, 0x9f, 0x51,
+		0x82, 0x6d, 0x16, 0xc3, 0x90, 0x64, 0xa2, 0x18, 0x45, 0x9c, 0x82, 0x48,
+		0x53, 0x94, 0x07, 0xcf, 0xbd, 0x3e, 0x01, 0x4d, 0xed, 0x57, 0xb1, 0x22,
+		0x13, 0x79, 0xbf, 0xe1, 0x84, 0xea, 0xf5, 0x91, 0xdc, 0x2d, 0x51, 0x57,
+		0x64, 0xba, 0xaa, 0x46, 0x35, 0xda, 0x19, 0xc5, 0xce, 0xb1, 0x7e, 0xd7,
+		0xe1, 0x49, 0xda, 0x83, 0x2c, 0xc0, 0x6e, 0x0b, 0x9f, 0xc7, 0x86, 0xdf,
+		0xa8, 0x2a, 0x0a, 0x33, 0x9e, 0x14, 0x38, 0xe4, 0x91, 0x29, 0xf4, 0x5f,
+		0x2d, 0x1e, 0xaa, 0xf2, 0xf3, 0xde, 0xc1, 0xc8, 0x88, 0xd9, 0x92, 0xed,
+		0x9d, 0xe6, 0xbc, 0x2c, 0xd7, 0xfd, 0xc4, 0xca, 0x5d, 0xc1, 0xbc, 0xd5,
+		0xd2, 0xdc, 0x7d, 0xb3, 0xfe, 0xb8, 0x7c, 0xf5, 0xd4, 0x49, 0x0c, 0x61,
+		0x42, 0x2d, 0xd6, 0xa7, 0xad, 0x46, 0xec, 0x7b, 0x83, 0x23, 0x4c, 0x80,
+		0x71, 0x3b, 0x7d, 0xfb, 0xde, 0x16, 0xe0, 0x8e, 0x31, 0xd4, 0xff, 0xf3,
+		0xee, 0xac, 0x8f, 0xbc, 0x56, 0x67, 0xef, 0xca, 0xf1, 0x61, 0x75, 0x35,
+		0x31, 0x03, 0xc5, 0xfa, 0x4a, 0x52, 0x20, 0x82, 0xf7, 0xcd, 0xca, 0x01,
+		0xa3, 0x07, 0xb1, 0xaa, 0x36, 0xa1, 0xc1, 0x11, 0x17, 0xda, 0x53, 0x63,
+		0x85, 0x58, 0x97, 0xce, 0x0a, 0xcd, 0x94, 0x3d, 0x27, 0xd3, 0x71, 0x25,
+		0x38, 0x4d, 0xf9, 0x34, 0x81, 0xe9, 0x13, 0x1a, 0xef, 0x4b, 0x08, 0x70,
+		0x14, 0x1a, 0xa6, 0x6f, 0x6a, 0xc0, 0x34, 0x61, 0x45, 0xd8, 0x82, 0x31,
+		0x13, 0x1b, 0xd8, 0x5b, 0x5b, 0x9a, 0xfa, 0x23, 0x8f, 0x15, 0xa0, 0xf7,
+		0x2a, 0x96, 0x9e, 0x59, 0x60, 0x33, 0x99, 0x43, 0x00, 0x13, 0xce, 0x7b,
+		0xa8, 0xbd, 0x51, 0x99, 0x60, 0xa9, 0x20, 0xac, 0x3e, 0xa0, 0x31, 0xdf,
+		0x00, 0x75, 0x76, 0x83, 0x4f, 0xcc, 0xfb, 0x47, 0x99, 0x5f, 0x8a, 0x1c,
+		0x6a, 0x0c, 0xd3, 0x29, 0xbe, 0x5d, 0x71, 0x21, 0x63, 0x47, 0xb0, 0x4e,
+		0xae, 0x8f, 0x7a, 0x9e, 0x19, 0x0d, 0x49, 0xbd, 0x46, 0xb9, 0x69, 0xcd,
+		0x8c, 0x04, 0x68, 0xab, 0x4a, 0xaa, 0x91, 0x92, 0xef, 0x44, 0x51, 0x5e,
+		0x51, 0x25, 0x71, 0x5c, 0xe7, 0x4c, 0x98, 0x5f, 0x71, 0x9e, 0x4f, 0x7b,
+		0xbb, 0x6c, 0x33, 0xcb, 0x36, 0x37, 0x6c, 0x1f, 0xde, 0x13, 0x9a, 0x1e,
+		0x1c, 0xdd, 0x27, 0xd2, 0xea, 0xba, 0x89, 0xd2, 0x3a, 0x00, 0xec, 0xe6,
+		0x28, 0xbe, 0xc9, 0x31, 0xaa, 0xcf, 0x55, 0x75, 0xc4, 0x31, 0x6b, 0x12,
+		0x04, 0xa6, 0x5a, 0x2f, 0xe7, 0x2e, 0x08, 0x49, 0xf9, 0x01, 0x0d, 0x95,
+		0x9a, 0x4a, 0xf3, 0x18, 0xb2, 0x4a, 0x02, 0x98, 0x95, 0x7c, 0x41, 0x94,
+		0x9a, 0xb0, 0x2c, 0xe6, 0xd0, 0xd7, 0x60, 0x7a, 0x81, 0x54, 0x12, 0xcd,
+		0x36, 0xd0, 0xd3, 0xf3, 0xd9, 0xa8, 0x79, 0xb8, 0x69, 0x33, 0xc4, 0x99,
+		0xcb, 0x70, 0xc4, 0xb0, 0x9d, 0x51, 0x8d, 0x5f, 0xcd, 0x8f, 0x26, 0x7d,
+		0xc4, 0x7f, 0x0d, 0x07, 0x43, 0x7f, 0x66, 0x86, 0x24, 0x58, 0xfb, 0x33,
+		0x16, 0x0b, 0x11, 0xe9, 0x15, 0x0c, 0x8c, 0xea, 0xb8, 0x15, 0xf0, 0x5e,
+		0xa0, 0x6b, 0x91, 0x69, 0x29, 0x15, 0x00, 0x2b, 0x5b, 0x3d, 0xd2, 0xe1,
+		0x0c, 0x4a, 0xcc, 0xed, 0x21, 0x6d, 0xd6, 0xc1, 0xa9, 0xb3, 0x49, 0xab,
+		0xd1, 0x1c, 0xba, 0x9a, 0x19, 0x18, 0x03, 0x4e, 0x6b, 0xa9, 0x58, 0xf8,
+		0xd8, 0xf8, 0x40, 0x26, 0x40, 0xfa, 0x20, 0xdf, 0xc2, 0x1a, 0xce, 0x46,
+		0x6b, 0xed, 0x43, 0xd6, 0xe8, 0x34, 0xf2, 0xda, 0xd1, 0x8e, 0xae, 0xc4,
+		0x64, 0x08, 0x11, 0x96, 0xaa, 0x00, 0x68, 0x4f, 0x13, 0x81, 0x8f, 0x2f,
+		0x6e, 0x5f, 0x47, 0xbe, 0x77, 0x4d, 0x1a, 0x83, 0x38, 0x71, 0x9a, 0x8a,
+		0xfc, 0xd7, 0x0f, 0xbf, 0x9d, 0x8d, 0x3d, 0x68, 0xe1, 0xb0, 0xf5, 0x09,
+		0x9e, 0xd0, 0x98, 0x86, 0x07, 0xeb, 0xcf, 0x2f, 0xd5, 0xa1, 0xfd, 0x5e,
+		0x75, 0xf2, 0xbb, 0x86, 0xb9, 0x59, 0xa0, 0xdd, 0x32, 0xa6, 0xbc, 0xd4,
+		0xcf, 0xb8, 0xde, 0xf4, 0x97, 0x69, 0x98, 0x8f, 0x21, 0x97, 0x64, 0x52,
+		0x22, 0x8f, 0x46, 0x3a, 0xbf, 0xb3, 0xcb, 0xbd, 0x0a, 0x93, 0xe4, 0x17,
+		0x69, 0x28, 0xb1, 0x86, 0x35, 0x90, 0xff, 0x44, 0xc8, 0xa9, 0x81, 0x58,
+		0x7c, 0x5e, 0x46, 0xf1, 0x6a, 0xfc, 0x63, 0x84, 0xac, 0xc6, 0x9f, 0xc6,
+		0x2a, 0x77, 0x6a, 0x4e, 0x8d, 0xfe, 0x42, 0x03, 0x2c, 0x36, 0x40, 0x45,
+		0x5a, 0xb4, 0xc5, 0x3e, 0xad, 0x56, 0x2d, 0x5e, 0x11, 0xff, 0xb7, 0xc9,
+		0xed, 0x3d, 0x2c, 0xf4, 0xdc, 0x09, 0xb5, 0x11, 0x07, 0xd6, 0x42, 0x60,
+		0xcb, 0xea, 0x50, 0x15, 0x17, 0xda, 0xab, 0x34, 0xbf, 0x69, 0x04, 0x53,
+		0xeb, 0x37, 0x23, 0x51, 0x28, 0x73, 0xbe, 0xd3, 0x38, 0x5d, 0x53, 0x21,
+		0x5b, 0xa3, 0x9a, 0x6f, 0x79, 0x40, 0x21, 0xfb, 0x4b, 0x14, 0xde, 0xd0,
+		0x7b, 0x23, 0xbd, 0xde, 0x72, 0x36, 0x38, 0x8d, 0x1f, 0x79, 0x2f, 0xd5,
+		0x0e, 0x4c, 0x58, 0x46, 0xd3, 0x95, 0x0d, 0xaf, 0x7f, 0x11, 0x5f, 0xf0,
+		0xa6, 0xc5, 0x82, 0x85, 0x47, 0x95, 0x88, 0x18, 0x60, 0x2b, 0xa3, 0xee,
+		0xeb, 0x61, 0xd7, 0x8c, 0x3b, 0x5a, 0x59, 0xaa, 0x1a, 0x64, 0x75, 0x3d,
+		0x0e, 0xb5, 0x6d, 0xdf, 0xcc, 0xdc, 0x59, 0x7d, 0x7e, 0xce, 0x8c, 0x7d,
+		0xcd, 0x00, 0x6f, 0x2c, 0x4f, 0x79, 0x2f, 0x17, 0x00, 0xf3, 0x37, 0xb3,
+		0x60, 0x46, 0x57, 0x35, 0x78, 0xce, 0x4f, 0x44, 0x7a, 0x8e, 0x63, 0xf8,
+		0x98, 0xed, 0xf6, 0xac, 0x93, 0x1e, 0x78, 0x8a, 0x75, 0x7c, 0x90, 0xd1,
+		0xfb, 0x2a, 0x1f, 0xa0, 0x00, 0x75, 0x79, 0x31, 0xb3, 0x15, 0xb6, 0x72,
+		0x23, 0x19, 0xcc, 0xd4, 0x91, 0x21, 0x6c, 0x16, 0x5f, 0x7d, 0x5b, 0x64,
+		0x3a, 0x5f, 0x7d, 0xd2, 0x32, 0x17, 0xd6, 0x78, 0x3d, 0x40, 0xb3, 0x59,
+		0xb5, 0x19, 0x9c, 0x30, 0x74, 0x91, 0xde, 0x58, 0xaa, 0x6d, 0x4b, 0x45,
+		0xad, 0xdd, 0x38, 0xb4, 0x8c, 0x89, 0xb5, 0x65, 0x0a, 0x0a, 0xeb, 0x32,
+		0x9f, 0xac, 0x68, 0x18, 0x95, 0x65, 0xf5, 0x31, 0x66, 0x05, 0x12, 0x1e,
+		0x70, 0x0c, 0x12, 0xc4, 0x10, 0xe0, 0xe8, 0x29, 0x0b, 0xa1, 0xe6, 0xb8,
+		0xd6, 0x87, 0x10, 0xeb, 0xa7, 0x9a, 0x97, 0xf5, 0x19, 0xdd, 0xbf, 0xb8,
+		0x15, 0xbd, 0x6b, 0x2e, 0xae, 0x4d, 0xae, 0xe0, 0x3e, 0x36, 0x0d, 0x6a,
+		0x58, 0xeb, 0x36, 0x4e, 0x0f, 0xca, 0x45, 0x1e, 0x82, 0x97, 0x60, 0xf7,
+		0x65, 0x45, 0x75, 0x17, 0x00, 0x55, 0xa9, 0x11, 0xdc, 0x4b, 0x5d, 0x83,
+		0xf7, 0x60, 0x8d, 0x31, 0x4f, 0x28, 0x5d, 0xfb, 0xde, 0x37, 0xb2, 0xcc,
+		0x36, 0x44, 0x48, 0x36, 0xbd, 0xe1, 0x3d, 0xcd, 0x42, 0x98, 0x3f, 0xc0,
+		0x32, 0x78, 0x01, 0xe9, 0xd7, 0xc9, 0x94, 0xea, 0x49, 0xd2, 0x11, 0x22,
+		0xb0, 0x6c, 0x03, 0x8d, 0x6b, 0xc2, 0x98, 0x50, 0x4d, 0x3a, 0xf5, 0x74,
+		0x24, 0x6d, 0x9a, 0xdb, 0x4c, 0x77, 0x0c, 0x77, 0x6f, 0x2f, 0xbf, 0x75,
+		0x71, 0xe7, 0xa0, 0x0e, 0x1e, 0x60, 0xb7, 0xef, 0x6a, 0x82, 0xcd, 0x3b,
+		0x94, 0x95, 0x17, 0x12, 0xf6, 0x4a, 0xd5, 0xbf, 0xd7, 0x65, 0x5d, 0x9b,
+		0x92, 0xec, 0xf7, 0x60, 0x52, 0x7b, 0x49, 0xeb, 0x50, 0x8a, 0x36, 0xe5,
+		0xc8, 0x19, 0xc9, 0x44, 0x6b, 0x12, 0xab, 0x63, 0xb1, 0x32, 0xac, 0x65,
+		0x39, 0x78, 0x58, 0x30, 0xb6, 0x1d, 0x05, 0x7f, 0x1e, 0x6c, 0xea, 0x65,
+		0xab, 0x3a, 0x17, 0xcc, 0x3f, 0x4e, 0x45, 0x87, 0xe9, 0xff, 0x53, 0x49,
+		0xf3, 0x65, 0x83, 0xa6, 0x6a, 0xef, 0xe2, 0x33, 0x9e, 0x09, 0xf0, 0x9e,
+		0x62, 0x0f, 0x0f, 0x38, 0x06, 0x06, 0x2d, 0xef, 0xf7, 0xf4, 0x0c, 0x10,
+		0x4e, 0x78, 0x92, 0xe0, 0x07, 0x14, 0x1f, 0x3f, 0x35, 0x00, 0xdc, 0xf9,
+		0xd8, 0x31, 0x30, 0x1f, 0xe3, 0xf1, 0x2b, 0x3c, 0xfc, 0x90, 0x8a, 0x2c,
+		0x86, 0x1e, 0xac, 0x53, 0xda, 0x63, 0xf5, 0x77, 0x9f, 0x52, 0xc9, 0x69,
+		0x39, 0x9b, 0x89, 0x1c, 0xf0, 0xb2, 0xc9, 0xc7, 0x39, 0x57, 0x47, 0xec,
+		0x2f, 0x59, 0xe6, 0xac, 0xea, 0x1a, 0x91, 0x14, 0x45, 0xda, 0x55, 0xac,
+		0x28, 0xb3, 0x4c, 0xe6, 0x8a, 0x6d, 0x25, 0x6a, 0x44, 0x0c, 0xb7, 0xce,
+		0xd5, 0xe8, 0xe8, 0x11, 0x3b, 0xc3, 0x1b, 0xce, 0xc3, 0x41, 0x86, 0xdb,
+		0xd2, 0x3a, 0x3c, 0xe0, 0xa7, 0x62, 0xdc, 0xce, 0x7c, 0xc6, 0xf7, 0x26,
+		0x9d, 0x0d, 0xbb, 0x56, 0x7f, 0x65, 0xc2, 0x6b, 0x1b, 0x80, 0x6c, 0xc0,
+		0xa1, 0x06, 0x01, 0x8d, 0xf4, 0xf5, 0x26, 0xfa, 0xa2, 0xc2, 0x7e, 0x5f,
+		0xc1, 0xc6, 0x5a, 0xe3, 0xda, 0xcc, 0xf2, 0x26, 0x7a, 0x2a, 0xc6, 0x4c,
+		0xd4, 0x28, 0x87, 0x03, 0xd8, 0xc9, 0xdd, 0xd0, 0x28, 0x82, 0x57, 0x49,
+		0x2d, 0x48, 0xe3, 0x83, 0x97, 0x2b, 0x8a, 0x0d, 0x78, 0x6f, 0xf2, 0x3b,
+		0x3c, 0x32, 0x1a, 0x3f, 0x9b, 0x12, 0x38, 0x01, 0xe3, 0x4d, 0x4e, 0xe1,
+		0x85, 0xfd, 0xa1, 0xdf, 0xd6, 0x48, 0x51, 0x4d, 0x4d, 0x7a, 0x1b, 0xba,
+		0xd3, 0xd2, 0xc3, 0x8c, 0xb9, 0xe0, 0xb2, 0x88, 0x66, 0xfe, 0xb7, 0xd2,
+		0x1a, 0xe4, 0x7a, 0x5c, 0x6e, 0x13, 0xd4, 0xf3, 0xf5, 0x12, 0x51, 0x63,
+		0x6c, 0x5e, 0xde, 0xc8, 0x99, 0xb6, 0x97, 0x48, 0x1b, 0x13, 0x74, 0x9b,
+		0xb4, 0x31, 0x78, 0xaf, 0x26, 0x35, 0xd3, 0xf4, 0x4a, 0x4a, 0x33, 0x82,
+		0x2f, 0x11, 0xda, 0xd9, 0xba, 0x4d, 0x65, 0x47, 0xf1, 0x25, 0x12, 0x9c,
+		0xb5, 0xdb, 0xd8, 0x38, 0x90, 0x3b, 0xde, 0xd6, 0xbf, 0xd5, 0x4f, 0x85,
+		0xd4, 0xf8, 0x7e, 0x4b, 0x01, 0xaa, 0xfd, 0xaa, 0xef, 0x00, 0x26, 0x74,
+		0x79, 0x60, 0x5c, 0x49, 0x97, 0x92, 0xc4, 0x58, 0x19, 0xa9, 0x69, 0xbf,
+		0xd5, 0x2c, 0xed, 0xc6, 0x87, 0x8f, 0x76, 0x76, 0x6a, 0x98, 0xb3, 0x93,
+		0xd9, 0x48, 0x7f, 0xe2, 0xf3, 0x26, 0xc7, 0x51, 0xc4, 0xe0, 0x81, 0xf6,
+		0x32, 0x20, 0xec, 0x82, 0xde, 0xe4, 0x0d, 0xfc, 0xd5, 0xab, 0x9a, 0xe1,
+		0xce, 0x8e, 0xd9, 0xcb, 0xdc, 0x00, 0x54, 0xd1, 0x74, 0x16, 0xa7, 0x97,
+		0x4c, 0x49, 0x46, 0x5f, 0xa2, 0xe0, 0xf7, 0x16, 0xb3, 0xb7, 0x98, 0x43,
+		0xae, 0x86, 0xa5, 0x2a, 0xd8, 0x94, 0xe7, 0x43, 0xe6, 0x04, 0x62, 0xf5,
+		0xd1, 0x0a, 0x04, 0xe5, 0x3a, 0x40, 0x90, 0x08, 0x8e, 0xa3, 0x97, 0xb8,
+		0x59, 0xcb, 0x5a, 0x03, 0x53, 0x41, 0x06, 0xfa, 0x7f, 0xf2, 0xf9, 0x1f,
+		0x1f, 0xd0, 0x12, 0x46, 0xfc, 0x23, 0x00, 0x00,
 	}))
 
 	if err != nil {
@@ -6,10 +6,12 @@ import (
 	"github.com/sqp/godock/libs/ternary"
 	"github.com/sqp/godock/libs/text/strhelp"
 
-	"os/exec"
+	"errors"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Controler defines actions needed from a cdtype.AppBase.
@@ -34,14 +36,16 @@ type Manager struct {
 
 	firstID int // Position of first action, when used with other applets services.
 
-	active     bool // true when downloading.
+	jobs       map[*Video]*job // Downloads in progress.
 	actionPre  func() error
 	actionPost func() error
-	cmd        *exec.Cmd
 
 	history *HistoryVideo
 
-	progress *Progress
+	limiter  *RateLimiter // Global rate limit, shared by all downloads.
+	schedule Schedule
+	timer    *time.Timer // Next schedule state change.
+	mu       sync.Mutex
 
 	category string // currently selected category (group / dir).
 
@@ -59,6 +63,8 @@ func NewManager(control Controler, log cdtype.Logger, hist *HistoryVideo) *Manag
 		log:     log,
 		backend: YTDL{},
 		history: hist,
+		jobs:    make(map[*Video]*job),
+		limiter: NewRateLimiter(0),
 		Config:  &Config{},
 		Commands: &Commands{
 			CmdOpenDir:   cdglobal.CmdOpen,
@@ -91,6 +97,16 @@ func (m *Manager) SetConfig(conf *Config) {
 	m.CmdOpenDir = strhelp.First(m.CmdOpenDir, cdglobal.CmdOpen)
 	m.CmdOpenVideo = strhelp.First(m.CmdOpenVideo, cdglobal.CmdOpen)
 	m.CmdOpenWeb = strhelp.First(m.CmdOpenWeb, cdglobal.CmdOpen)
+
+	m.limiter.SetRate(int64(conf.RateLimit) << 10)
+	m.webAccess()
+
+	sched, e := ParseSchedule(conf.ScheduleStart, conf.ScheduleEnd)
+	m.log.Err(e, "videodl: schedule")
+	m.mu.Lock()
+	m.schedule = sched
+	m.mu.Unlock()
+	m.scheduleTimer()
 }
 
 // SetEnabledWeb sets the web service state.
@@ -190,13 +206,11 @@ func (m *Manager) OpenFolder() {
 	m.log.Err(e, "open folder")
 }
 
-// CancelDownload stops the current download.
+// CancelDownload stops the downloads in progress. They are moved to the done
+// list as failed.
 //
 func (m *Manager) CancelDownload() {
-	if m.cmd != nil {
-		m.cmd.Process.Kill()
-		m.cmd = nil
-	}
+	m.stopJobs(false)
 }
 
 // ToggleEnableWeb toggles the status of the web service.
@@ -208,12 +222,70 @@ func (m *Manager) ToggleEnableWeb() {
 // ToggleEnableDownload toggles the status of the download activity (dl/pause).
 //
 func (m *Manager) ToggleEnableDownload() {
-	m.EnabledDL = !m.EnabledDL
 	if m.EnabledDL {
-		m.Start()
+		m.Pause()
+	} else {
+		m.Resume()
 	}
 }
 
+// Pause stops the downloads in progress and keeps them in the queue.
+//
+func (m *Manager) Pause() {
+	m.mu.Lock()
+	m.EnabledDL = false
+	m.mu.Unlock()
+	m.stopJobs(true)
+}
+
+// Resume restarts downloading queued items.
+//
+func (m *Manager) Resume() {
+	m.mu.Lock()
+	m.EnabledDL = true
+	m.mu.Unlock()
+	m.Start()
+}
+
+// MoveQueued moves a video in the download queue.
+//
+func (m *Manager) MoveQueued(vid *Video, move QueueMove) error {
+	return m.history.Move(vid, move)
+}
+
+// RemoveQueued removes a video from the download queue, and stops its download
+// if in progress. A download in progress is removed by its worker, or moved to
+// the done list if it finished before being cancelled.
+//
+func (m *Manager) RemoveQueued(vid *Video) error {
+	m.mu.Lock()
+	j, ok := m.jobs[vid]
+	if ok {
+		j.remove = true
+		if vid.filer != nil {
+			vid.filer.Cancel()
+		}
+	}
+	m.mu.Unlock()
+	if ok {
+		return nil
+	}
+	return m.dropQueued(vid)
+}
+
+// dropQueued removes a video from the download queue and saves the history.
+//
+func (m *Manager) dropQueued(vid *Video) error {
+	m.history.mu.Lock()
+	vid.active = false
+	e := m.history.Remove(vid, &m.history.Queue)
+	m.history.mu.Unlock()
+	if e != nil {
+		return e
+	}
+	return m.history.Save()
+}
+
 //
 //--------------------------------------------------------------------[ MENU ]--
 
@@ -222,17 +294,24 @@ func (m *Manager) ToggleEnableDownload() {
 func (m *Manager) Menu(menu cdtype.Menuer) {
 	m.control.Action().BuildMenu(menu, []int{m.firstID + ActionOpenFolder, m.firstID + ActionEnableDownload})
 
-	if m.active {
+	if m.IsActive() {
 		m.control.Action().BuildMenu(menu, []int{m.firstID + ActionCancelDownload})
 	}
 
 	subTitle := "Video Download"
-	if !m.EnabledDL {
+	m.mu.Lock()
+	switch {
+	case !m.EnabledDL:
 		subTitle += " (paused)"
+
+	case !m.schedule.Active(time.Now()):
+		subTitle += " (waiting " + m.schedule.String() + ")"
 	}
+	m.mu.Unlock()
+
 	sub := menu.AddSubMenu(subTitle, iconMenuMain)
-	if m.history.Queued() > 0 {
-		sub.AddEntry("Queued: "+strconv.Itoa(m.history.Queued()), "emblem-downloads", nil)
+	if queue := m.history.QueueList(); len(queue) > 0 {
+		m.menuQueue(sub, queue)
 		sub.AddSeparator()
 	}
 
@@ -244,6 +323,39 @@ func (m *Manager) Menu(menu cdtype.Menuer) {
 	m.MenuQuality(sub, &m.Quality, m.backend.MenuQuality())
 }
 
+// menuQueue fills the menu with the download queue, and the progress of active
+// downloads.
+//
+func (m *Manager) menuQueue(menu cdtype.Menuer, queue []*Video) {
+	m.mu.Lock()
+	var progress []string
+	for _, vid := range queue {
+		if j, ok := m.jobs[vid]; ok {
+			progress = append(progress, vid.Name+"  "+strconv.Itoa(int(j.progress.Fraction()*100))+"%")
+		}
+	}
+	m.mu.Unlock()
+
+	for _, str := range progress {
+		menu.AddEntry(str, "emblem-downloads", nil)
+	}
+
+	sub := menu.AddSubMenu("Queued: "+strconv.Itoa(len(queue)), "emblem-downloads")
+	for _, vid := range queue {
+		vidsub := sub.AddSubMenu(vid.Name, "")
+		stv := vid // force static for the callback.
+		for _, move := range []QueueMove{QueueMoveTop, QueueMoveUp, QueueMoveDown, QueueMoveBottom} {
+			stm := move
+			vidsub.AddEntry(move.String(), "", func() {
+				m.log.Err(m.MoveQueued(stv, stm), "videodl: move queued")
+			})
+		}
+		vidsub.AddEntry("Remove", "edit-delete", func() {
+			m.log.Err(m.RemoveQueued(stv), "videodl: remove queued")
+		})
+	}
+}
+
 // MenuQuality returns the list of available streams and formats for the video.
 //
 func (m *Manager) MenuQuality(menu cdtype.Menuer, qual *Quality, list []Quality) {
@@ -397,7 +509,11 @@ func (m *Manager) DialogCategory(filterFormats FuncFilterFormats, callDialog Fun
 
 // IsActive returns whether a download is in progress or not.
 //
-func (m *Manager) IsActive() bool { return m.active }
+func (m *Manager) IsActive() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.jobs) > 0
+}
 
 // Download downloads a video file from the server at configured quality (can be ask).
 //
@@ -447,12 +563,29 @@ func (m *Manager) getQuality(vid *Video) {
 			m.DialogQuality(m.FilterBlacklist(), m.control.PopupDialog, download, vid)
 		})
 
-		// case QualityBestFound:
-		// 	download("best")
+	case QualityBestFound, QualityBestPossible:
+		m.log.GoTry(func() {
+			format, e := m.bestFormat(vid, m.TypeDL)
+			if !m.log.Err(e, "videodl: select format") {
+				download(vid, format)
+			}
+		})
+	}
+}
 
-		// case QualityBestPossible:
-		// 	download("")
+// bestFormat returns the first format of the video (best quality) matching the
+// media type, and not blacklisted.
+//
+func (m *Manager) bestFormat(vid *Video, typ TypeDL) (*Format, error) {
+	formats, e := vid.filer.Formats()
+	if e != nil {
+		return nil, e
+	}
+	formats = m.filterTypeDL(typ)(formats)
+	if len(formats) == 0 {
+		return nil, errors.New("no format found")
 	}
+	return formats[0], nil
 }
 
 // Enqueue enqueues an item to the download list.
@@ -469,56 +602,202 @@ func (m *Manager) EnqueueAndStart(vid *Video) {
 	m.Start()
 }
 
-// Start starts downloading queued items.
+// Start starts downloading queued items, with up to Workers downloads at once.
 //
 func (m *Manager) Start() {
-	if m.IsActive() || m.history.Queued() == 0 { // Only one worker.
-		return
-	}
-	m.log.GoTry(func() {
-		m.active = true
-		if m.actionPre != nil {
+	for {
+		m.mu.Lock()
+		first := len(m.jobs) == 0
+		vid := m.nextJob()
+		m.mu.Unlock()
+		if vid == nil {
+			return
+		}
+
+		if first && m.actionPre != nil {
 			m.log.Err(m.actionPre(), "actionPre")
 		}
-		defer func() {
-			if m.actionPost != nil {
-				m.log.Err(m.actionPost(), "actionPost")
-			}
-			m.active = false
-		}()
+		m.log.GoTry(func() { m.worker(vid) })
+	}
+}
 
-		var e error
-		for {
-			vid, ok := m.history.Next()
-			if !ok || !m.EnabledDL {
-				return
-			}
+// job defines a download in progress.
+//
+type job struct {
+	progress *Progress
+	release  bool // Stopped to be downloaded later (paused).
+	remove   bool // Stopped to be removed from the queue.
+}
 
-			if !m.testFiler(vid) {
-				return
-			}
+// worker downloads the video, and the next queued items while allowed.
+//
+func (m *Manager) worker(vid *Video) {
+	for vid != nil {
+		m.downloadOne(vid)
 
-			if vid.filer == nil {
-				vid.filer, e = m.backend.New(m.log, vid.URL)
-				if m.log.Err(e, "videodl: get data") {
-					return
-				}
-			}
+		m.mu.Lock()
+		delete(m.jobs, vid)
+		vid = m.nextJob()
+		last := vid == nil && len(m.jobs) == 0
+		m.mu.Unlock()
 
-			m.progress = NewProgress()
-			e = vid.filer.DownloadCmd(m.Path, vid.Format, m.progress)()
-			if m.log.Err(e, "Download") {
-				vid.Fail = true
-			} else if vid.Format.needDeleteFile != "" {
-				m.log.Info("to delete", vid.Category, vid.Name, vid.needDeleteFile)
-			}
+		if last && m.actionPost != nil {
+			m.log.Err(m.actionPost(), "actionPost")
+		}
+	}
+}
+
+// downloadOne downloads a video and moves it to the done list, or keeps it in
+// the queue if the download was paused, or drops it if it was removed.
+//
+func (m *Manager) downloadOne(vid *Video) {
+	m.mu.Lock()
+	j := m.jobs[vid]
+	limit := Limit{
+		Shared:  m.limiter,
+		Rate:    int64(m.RateLimitOne) << 10,
+		Workers: m.workers(),
+	}
+	m.mu.Unlock()
+
+	if !m.testFiler(vid) {
+		vid.Fail = true
+	} else {
+		m.mu.Lock()
+		release, remove := j.release, j.remove
+		m.mu.Unlock()
+		switch {
+		case remove: // Removed before the download started.
+			m.log.Err(m.dropQueued(vid), "videodl: remove queued")
+			return
 
-			// Remove from queue.
-			// Whether it was a success or not, we cannot chain loop over the same item.
-			e = m.history.Done()
-			m.log.Err(e, "videodl: save data")
+		case release: // Paused before the download started.
+			m.history.Release(vid)
+			return
 		}
-	})
+
+		e := vid.filer.DownloadCmd(m.Path, vid.Format, j.progress, limit)()
+
+		// Only a cancelled download is kept in the queue, or removed. The cancel
+		// does nothing if the command wasn't started yet, and the download may
+		// have finished: it's then done.
+		m.mu.Lock()
+		release, remove = j.release, j.remove
+		m.mu.Unlock()
+		switch {
+		case remove && e != nil:
+			m.log.Err(m.dropQueued(vid), "videodl: remove queued")
+			return
+
+		case release && e != nil:
+			m.history.Release(vid)
+			return
+		}
+
+		if m.log.Err(e, "Download") {
+			vid.Fail = true
+		} else if vid.Format.needDeleteFile != "" {
+			m.log.Info("to delete", vid.Category, vid.Name, vid.needDeleteFile)
+		}
+	}
+
+	// Remove from queue.
+	// Whether it was a success or not, we cannot chain loop over the same item.
+	e := m.history.Done(vid)
+	m.log.Err(e, "videodl: save data")
+}
+
+// nextJob returns the next video to download, registered as a job, or nil if
+// none or not allowed. Must be called with the lock.
+//
+func (m *Manager) nextJob() *Video {
+	if len(m.jobs) >= m.workers() || !m.EnabledDL || !m.schedule.Active(time.Now()) {
+		return nil
+	}
+	vid, ok := m.history.Next()
+	if !ok {
+		return nil
+	}
+	m.jobs[vid] = &job{progress: NewProgress()}
+	return vid
+}
+
+// stopJobs stops the downloads in progress. With release, they are kept in the
+// queue to be downloaded later, unless they finish before being cancelled.
+//
+func (m *Manager) stopJobs(release bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for vid, j := range m.jobs {
+		j.release = release
+		if vid.filer != nil {
+			vid.filer.Cancel()
+		}
+	}
+}
+
+// State returns the download manager state.
+//
+func (m *Manager) State() State {
+	m.mu.Lock()
+	state := State{
+		Enabled: m.EnabledDL,
+		Waiting: !m.schedule.Active(time.Now()),
+		Workers: m.workers(),
+		Active:  len(m.jobs),
+	}
+	if m.schedule.Set {
+		state.Schedule = m.schedule.String()
+	}
+	m.mu.Unlock()
+	state.Queued = m.history.Queued()
+	return state
+}
+
+// workers returns the number of parallel downloads allowed.
+//
+func (m *Manager) workers() int {
+	if m.Workers < 1 {
+		return 1
+	}
+	return m.Workers
+}
+
+//
+//----------------------------------------------------------------[ SCHEDULE ]--
+
+// scheduleTimer sets the timer for the next schedule state change.
+//
+func (m *Manager) scheduleTimer() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	now := time.Now()
+	next := m.schedule.Next(now)
+	if next.IsZero() {
+		return
+	}
+	m.timer = time.AfterFunc(next.Sub(now)+time.Second, m.scheduleChange)
+}
+
+// scheduleChange starts or pauses the downloads when entering or leaving the
+// schedule time window.
+//
+func (m *Manager) scheduleChange() {
+	m.scheduleTimer()
+
+	m.mu.Lock()
+	active := m.schedule.Active(time.Now())
+	m.mu.Unlock()
+
+	if active {
+		m.Start()
+	} else {
+		m.stopJobs(true)
+	}
 }
 
 //
@@ -527,6 +806,13 @@ func (m *Manager) Start() {
 // FilterBlacklist provides a filter formats call to remove blacklisted file types.
 //
 func (m *Manager) FilterBlacklist() FuncFilterFormats {
+	return m.filterTypeDL(m.TypeDL)
+}
+
+// filterTypeDL provides a filter formats call to remove blacklisted file types
+// and formats not matching the media type.
+//
+func (m *Manager) filterTypeDL(typ TypeDL) FuncFilterFormats {
 	testBlacklist := func(form *Format) bool {
 		for _, ext := range m.Blacklist {
 			if form.Extension == ext {
@@ -537,7 +823,7 @@ func (m *Manager) FilterBlacklist() FuncFilterFormats {
 	}
 
 	testTypeDL := func(form *Format) bool {
-		switch m.TypeDL {
+		switch typ {
 		case TypeDLAudio:
 			return form.AudioEncoding != "" && form.VideoEncoding == ""
 
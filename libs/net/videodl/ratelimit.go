@@ -0,0 +1,136 @@
+package videodl
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// rateChunk is the maximum size read at once by a limited reader, to keep a
+// smooth rate.
+//
+const rateChunk = 32 << 10
+
+//
+//------------------------------------------------------------[ RATE LIMITER ]--
+
+// RateLimiter limits the transfer rate of readers sharing it.
+//
+type RateLimiter struct {
+	rate int64     // Bytes per second, 0 = unlimited.
+	next time.Time // Time the next transfer is allowed.
+	mu   sync.Mutex
+}
+
+// NewRateLimiter creates a rate limiter for the rate in bytes per second.
+// A rate of 0 means unlimited.
+//
+func NewRateLimiter(rate int64) *RateLimiter {
+	return &RateLimiter{rate: rate}
+}
+
+// Rate returns the rate limit in bytes per second, or 0 if unlimited.
+//
+func (rl *RateLimiter) Rate() int64 {
+	if rl == nil {
+		return 0
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.rate
+}
+
+// SetRate sets the rate limit in bytes per second. 0 means unlimited.
+//
+func (rl *RateLimiter) SetRate(rate int64) {
+	rl.mu.Lock()
+	rl.rate = rate
+	rl.mu.Unlock()
+}
+
+// Wait blocks until the transfer of n bytes is allowed.
+//
+func (rl *RateLimiter) Wait(n int) {
+	time.Sleep(rl.reserve(n))
+}
+
+// reserve books the transfer of n bytes and returns the delay to wait before.
+//
+func (rl *RateLimiter) reserve(n int) time.Duration {
+	if rl == nil {
+		return 0
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.rate <= 0 {
+		return 0
+	}
+	now := time.Now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	wait := rl.next.Sub(now)
+	rl.next = rl.next.Add(time.Duration(n) * time.Second / time.Duration(rl.rate))
+	return wait
+}
+
+//
+//-------------------------------------------------------------------[ LIMIT ]--
+
+// Limit defines the transfer rate limits of a download.
+//
+type Limit struct {
+	Shared  *RateLimiter // Global limit, shared by all downloads. Can be nil.
+	Rate    int64        // Limit of the download, in bytes per second. 0 = unlimited.
+	Workers int          // Number of parallel downloads.
+}
+
+// Reader returns the reader with its transfer rate limited.
+//
+func (l Limit) Reader(r io.Reader) io.Reader {
+	if l.Shared.Rate() <= 0 && l.Rate <= 0 {
+		return r
+	}
+	return &limitReader{Reader: r, limits: []*RateLimiter{l.Shared, NewRateLimiter(l.Rate)}}
+}
+
+// CommandRate returns the rate limit to give to an external command, which
+// can't use the shared limiter: the lowest of the download limit and the
+// global limit split between workers. 0 means unlimited.
+//
+func (l Limit) CommandRate() int64 {
+	rate := l.Rate
+	if global := l.Shared.Rate(); global > 0 {
+		if l.Workers > 1 {
+			global /= int64(l.Workers)
+		}
+		if rate <= 0 || global < rate {
+			rate = global
+		}
+	}
+	return rate
+}
+
+// limitReader limits the transfer rate of a reader.
+//
+type limitReader struct {
+	io.Reader
+	limits []*RateLimiter
+}
+
+// Read implements io.Reader.
+//
+func (lr *limitReader) Read(p []byte) (int, error) {
+	if len(p) > rateChunk {
+		p = p[:rateChunk]
+	}
+	n, e := lr.Reader.Read(p)
+	var wait time.Duration
+	for _, limit := range lr.limits {
+		if delay := limit.reserve(n); delay > wait {
+			wait = delay
+		}
+	}
+	time.Sleep(wait)
+	return n, e
+}
@@ -0,0 +1,68 @@
+package videodl
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var unset *RateLimiter
+	assert.Equal(t, int64(0), unset.Rate(), "nil rate")
+	assert.Equal(t, time.Duration(0), unset.reserve(1000), "nil limiter")
+
+	for _, test := range []struct {
+		name  string
+		rate  int64
+		sizes []int
+		waits []time.Duration // Expected delays, for each reserved size.
+	}{
+		{"unlimited", 0, []int{1000, 1000}, []time.Duration{0, 0}},
+		{"limited", 1000, []int{500, 500, 1000}, []time.Duration{0, 500 * time.Millisecond, time.Second}},
+		{"large first", 2000, []int{4000, 10}, []time.Duration{0, 2 * time.Second}},
+	} {
+		rl := NewRateLimiter(test.rate)
+		for i, size := range test.sizes {
+			assert.InDelta(t, test.waits[i], rl.reserve(size), float64(50*time.Millisecond), test.name, i)
+		}
+	}
+
+	rl := NewRateLimiter(1000)
+	rl.SetRate(0)
+	assert.Equal(t, int64(0), rl.Rate(), "SetRate")
+	assert.Equal(t, time.Duration(0), rl.reserve(1000), "SetRate unlimited")
+}
+
+func TestLimit(t *testing.T) {
+	shared := NewRateLimiter(1000)
+	for _, test := range []struct {
+		name  string
+		limit Limit
+		want  int64
+	}{
+		{"unlimited", Limit{}, 0},
+		{"download only", Limit{Rate: 100}, 100},
+		{"shared only", Limit{Shared: shared, Workers: 1}, 1000},
+		{"shared no workers", Limit{Shared: shared}, 1000},
+		{"shared split", Limit{Shared: shared, Workers: 4}, 250},
+		{"download lower", Limit{Shared: shared, Rate: 100, Workers: 2}, 100},
+		{"shared lower", Limit{Shared: shared, Rate: 800, Workers: 2}, 500},
+	} {
+		assert.Equal(t, test.want, test.limit.CommandRate(), "CommandRate", test.name)
+	}
+
+	src := bytes.NewReader(make([]byte, 3*rateChunk))
+	assert.Equal(t, src, Limit{}.Reader(src), "unlimited reader unchanged")
+
+	r := Limit{Rate: 1 << 30}.Reader(src)
+	buf := make([]byte, 2*rateChunk)
+	n, e := r.Read(buf)
+	assert.NoError(t, e, "Read")
+	assert.Equal(t, rateChunk, n, "Read by chunks")
+	data, e := ioutil.ReadAll(r)
+	assert.NoError(t, e, "ReadAll")
+	assert.Len(t, data, 2*rateChunk, "ReadAll")
+}
@@ -0,0 +1,84 @@
+package videodl
+
+import "time"
+
+// Schedule defines a daily time window when downloads are allowed.
+//
+type Schedule struct {
+	Start time.Duration // Time of day when downloads start.
+	End   time.Duration // Time of day when downloads stop. Can be before Start (next day).
+	Set   bool          // Downloads are always allowed when not set.
+}
+
+// ParseSchedule parses a time window as "HH:MM" start and end times.
+// Empty strings means no schedule.
+//
+func ParseSchedule(start, end string) (Schedule, error) {
+	if start == "" && end == "" {
+		return Schedule{}, nil
+	}
+	begin, e := parseTimeOfDay(start)
+	if e != nil {
+		return Schedule{}, e
+	}
+	stop, e := parseTimeOfDay(end)
+	if e != nil {
+		return Schedule{}, e
+	}
+	return Schedule{Start: begin, End: stop, Set: begin != stop}, nil
+}
+
+// Active returns whether downloads are allowed at the given time.
+//
+func (s Schedule) Active(now time.Time) bool {
+	if !s.Set {
+		return true
+	}
+	clock := timeOfDay(now)
+	if s.Start < s.End {
+		return clock >= s.Start && clock < s.End
+	}
+	return clock >= s.Start || clock < s.End // Over midnight.
+}
+
+// Next returns the time of the next state change after the given time, or a
+// zero time if not set.
+//
+func (s Schedule) Next(now time.Time) (next time.Time) {
+	if !s.Set {
+		return next
+	}
+	day := now.Add(-timeOfDay(now))
+	current := s.Active(now)
+	for _, base := range []time.Time{day, day.AddDate(0, 0, 1)} {
+		for _, clock := range []time.Duration{s.Start, s.End} {
+			t := base.Add(clock)
+			if t.After(now) && s.Active(t) != current && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next
+}
+
+// String returns the time window as "HH:MM-HH:MM".
+//
+func (s Schedule) String() string {
+	format := func(d time.Duration) string {
+		return time.Time{}.Add(d).Format("15:04")
+	}
+	return format(s.Start) + "-" + format(s.End)
+}
+
+func parseTimeOfDay(str string) (time.Duration, error) {
+	t, e := time.Parse("15:04", str)
+	if e != nil {
+		return 0, e
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
@@ -0,0 +1,71 @@
+package videodl
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"testing"
+	"time"
+)
+
+// at returns the time of day on a fixed date, or on the next day with day 1.
+func at(day, hour, min int) time.Time {
+	return time.Date(2020, 3, 10+day, hour, min, 0, 0, time.UTC)
+}
+
+func TestParseSchedule(t *testing.T) {
+	for _, test := range []struct {
+		start, end string
+		want       Schedule
+		fail       bool
+	}{
+		{"", "", Schedule{}, false},
+		{"08:00", "18:30", Schedule{Start: 8 * time.Hour, End: 18*time.Hour + 30*time.Minute, Set: true}, false},
+		{"22:00", "06:00", Schedule{Start: 22 * time.Hour, End: 6 * time.Hour, Set: true}, false},
+		{"10:00", "10:00", Schedule{Start: 10 * time.Hour, End: 10 * time.Hour}, false},
+		{"25:00", "06:00", Schedule{}, true},
+		{"08:00", "", Schedule{}, true},
+	} {
+		got, e := ParseSchedule(test.start, test.end)
+		if test.fail {
+			assert.Error(t, e, "ParseSchedule", test.start, test.end)
+			continue
+		}
+		assert.NoError(t, e, "ParseSchedule", test.start, test.end)
+		assert.Equal(t, test.want, got, "ParseSchedule", test.start, test.end)
+	}
+
+	sched, _ := ParseSchedule("22:00", "06:05")
+	assert.Equal(t, "22:00-06:05", sched.String(), "String")
+}
+
+func TestSchedule(t *testing.T) {
+	day, _ := ParseSchedule("08:00", "18:00")
+	night, _ := ParseSchedule("22:00", "06:00") // Over midnight.
+
+	for _, test := range []struct {
+		name   string
+		sched  Schedule
+		now    time.Time
+		active bool
+		next   time.Time
+	}{
+		{"unset", Schedule{}, at(0, 12, 0), true, time.Time{}},
+
+		{"day before", day, at(0, 7, 59), false, at(0, 8, 0)},
+		{"day start", day, at(0, 8, 0), true, at(0, 18, 0)},
+		{"day inside", day, at(0, 12, 0), true, at(0, 18, 0)},
+		{"day end", day, at(0, 18, 0), false, at(1, 8, 0)},
+		{"day after", day, at(0, 23, 0), false, at(1, 8, 0)},
+
+		{"night before", night, at(0, 21, 59), false, at(0, 22, 0)},
+		{"night start", night, at(0, 22, 0), true, at(1, 6, 0)},
+		{"night evening", night, at(0, 23, 30), true, at(1, 6, 0)},
+		{"night midnight", night, at(0, 0, 0), true, at(0, 6, 0)},
+		{"night morning", night, at(0, 5, 59), true, at(0, 6, 0)},
+		{"night end", night, at(0, 6, 0), false, at(0, 22, 0)},
+		{"night day", night, at(0, 12, 0), false, at(0, 22, 0)},
+	} {
+		assert.Equal(t, test.active, test.sched.Active(test.now), "Active", test.name)
+		assert.Equal(t, test.next, test.sched.Next(test.now), "Next", test.name)
+	}
+}
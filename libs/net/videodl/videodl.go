@@ -4,6 +4,7 @@ package videodl
 import (
 	"github.com/sqp/godock/libs/cdtype"
 
+	"sync/atomic"
 	"time"
 )
 
@@ -50,6 +51,17 @@ type Config struct {
 	JSWindowOption string
 
 	Blacklist []string
+
+	Workers       int    // Number of parallel downloads.
+	RateLimit     int    // Global download rate limit, in KiB/s. 0 = unlimited.
+	RateLimitOne  int    // Rate limit of each download, in KiB/s. 0 = unlimited.
+	ScheduleStart string // Start of the daily download window, as HH:MM. Empty = always.
+	ScheduleEnd   string // End of the daily download window, as HH:MM.
+
+	WebToken    string // Token needed by web clients. Empty = none.
+	WebUser     string // Basic authentication for web clients. Empty = none.
+	WebPassword string
+	WebLAN      bool // Allow web clients from other hosts.
 }
 
 // Commands defines custom user commands to open programs.
@@ -97,8 +109,9 @@ type VideoDler interface {
 //
 type Filer interface {
 	Title() (string, error)
-	DownloadCmd(path string, format *Format, progress *Progress) func() error
+	DownloadCmd(path string, format *Format, progress *Progress, limit Limit) func() error
 	Formats() ([]*Format, error)
+	Cancel() // Stops the download in progress.
 }
 
 //
@@ -189,6 +202,34 @@ func (t TypeDL) String() string {
 	return ""
 }
 
+// ParseTypeDL returns the media type matching the name (all, audio, video or
+// videoaudio).
+//
+func ParseTypeDL(name string) (TypeDL, bool) {
+	for _, t := range []TypeDL{TypeDLAll, TypeDLAudio, TypeDLVideo, TypeDLVideoWithAudio} {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// Name returns the media type name, as used by the web API.
+//
+func (t TypeDL) Name() string {
+	switch t {
+	case TypeDLAll:
+		return "all"
+	case TypeDLAudio:
+		return "audio"
+	case TypeDLVideo:
+		return "video"
+	case TypeDLVideoWithAudio:
+		return "videoaudio"
+	}
+	return ""
+}
+
 // Tooltip returns the tooltip text displayed for the media type filter.
 //
 func (t TypeDL) Tooltip() string {
@@ -221,15 +262,29 @@ func NewProgress() *Progress { return &Progress{} }
 
 // SetMax sets the expected size of the download.
 //
-func (p *Progress) SetMax(m int64) { p.max = m }
+func (p *Progress) SetMax(m int64) { atomic.StoreInt64(&p.max, m) }
 
 // Write implements io.Writer to count download progress.
 //
 func (p *Progress) Write(data []byte) (n int, e error) {
-	p.cur += int64(len(data))
-	return 0, nil
+	atomic.AddInt64(&p.cur, int64(len(data)))
+	return len(data), nil
+}
+
+// Fraction returns the downloaded fraction, from 0 to 1 (0 if unknown).
+//
+func (p *Progress) Fraction() float64 {
+	max := atomic.LoadInt64(&p.max)
+	if max <= 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&p.cur)) / float64(max)
 }
 
+// set sets the downloaded size.
+//
+func (p *Progress) set(cur int64) { atomic.StoreInt64(&p.cur, cur) }
+
 //
 //------------------------------------------------------------------[ FORMAT ]--
 
@@ -246,7 +301,8 @@ type Video struct {
 	Viewed    bool
 	Category  string
 
-	filer Filer
+	filer  Filer
+	active bool // Downloading.
 }
 
 // NewVideo creates a video for the remote url.
@@ -280,6 +336,63 @@ type Format struct {
 	needDeleteFile string // Set to extension to delete if active.
 }
 
+//
+//---------------------------------------------------------------[ QUEUEMOVE ]--
+
+// QueueMove defines a move of a video in the download queue.
+//
+type QueueMove int
+
+// Queue moves.
+const (
+	QueueMoveTop QueueMove = iota
+	QueueMoveUp
+	QueueMoveDown
+	QueueMoveBottom
+)
+
+// ParseQueueMove returns the queue move matching the name (top, up, down or
+// bottom).
+//
+func ParseQueueMove(name string) (QueueMove, bool) {
+	for _, move := range []QueueMove{QueueMoveTop, QueueMoveUp, QueueMoveDown, QueueMoveBottom} {
+		if move.Name() == name {
+			return move, true
+		}
+	}
+	return 0, false
+}
+
+// Name returns the queue move name, as used by the web service.
+//
+func (q QueueMove) Name() string {
+	switch q {
+	case QueueMoveTop:
+		return "top"
+	case QueueMoveUp:
+		return "up"
+	case QueueMoveDown:
+		return "down"
+	case QueueMoveBottom:
+		return "bottom"
+	}
+	return ""
+}
+
+func (q QueueMove) String() string {
+	switch q {
+	case QueueMoveTop:
+		return "Move to top"
+	case QueueMoveUp:
+		return "Move up"
+	case QueueMoveDown:
+		return "Move down"
+	case QueueMoveBottom:
+		return "Move to bottom"
+	}
+	return ""
+}
+
 //
 //-----------------------------------------------------------------[ BACKEND ]--
 
@@ -350,6 +463,22 @@ type Downloader interface {
 	EnqueueAndStart(*Video)
 	Start()
 
+	// Pause stops the downloads in progress and keeps them in the queue.
+	//
+	Pause()
+
+	// Resume restarts downloading queued items.
+	//
+	Resume()
+
+	// MoveQueued moves a video in the download queue.
+	//
+	MoveQueued(vid *Video, move QueueMove) error
+
+	// RemoveQueued removes a video from the download queue.
+	//
+	RemoveQueued(vid *Video) error
+
 	//-------------------------------------------------------------[ ACTIONS ]--
 
 	OpenFolder()
@@ -6,8 +6,8 @@ import (
 	"encoding/json"
 	"html"
 	"net/http"
+	"net/url"
 	"os"
-	"strconv"
 	"strings"
 )
 
@@ -20,6 +20,20 @@ type Entry struct {
 	Viewed bool
 	URL    string
 	Fail   bool
+
+	Active   bool // Downloading.
+	Progress int  // Download progress in percent.
+}
+
+// State defines the download manager state to provide as json.
+//
+type State struct {
+	Enabled  bool   // Downloads enabled (not paused).
+	Waiting  bool   // Outside of the schedule time window.
+	Schedule string // Schedule time window, empty if not set.
+	Workers  int    // Number of parallel downloads.
+	Active   int    // Number of downloads in progress.
+	Queued   int    // Number of queued items.
 }
 
 // WebRegister registers the web service.
@@ -27,6 +41,22 @@ type Entry struct {
 func (m *Manager) WebRegister() {
 	e := websrv.Service.Register(WebPath, m.ServeHTTP, m.log)
 	m.log.Err(e, "WebRegister")
+	m.webAccess()
+}
+
+// webAccess sets the web service access rules from the config.
+//
+func (m *Manager) webAccess() {
+	if websrv.Service == nil { // Not registered, the web server is not used.
+		return
+	}
+	e := websrv.Service.SetAccess(WebPath, websrv.Access{
+		Token:    m.WebToken,
+		User:     m.WebUser,
+		Password: m.WebPassword,
+		AllowLAN: m.WebLAN,
+	})
+	m.log.Err(e, "web service access")
 }
 
 // WebUnregister unregister the web service.
@@ -90,11 +120,7 @@ func (m *Manager) SetStartedWeb(b bool) {
 // WebURL formats the web service base url.
 //
 func (m *Manager) WebURL() string {
-	url := "http://" // TODO: need https
-	if websrv.Service.Host == "" {
-		url += "localhost"
-	}
-	return url + websrv.Service.URL() + "/" + WebPath
+	return websrv.Service.Scheme() + "://" + websrv.Service.URL() + "/" + WebPath
 }
 
 // type HandlerFunc func( http.ResponseWriter, *http.Request)
@@ -106,6 +132,9 @@ func (m *Manager) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	case url == "" || url == "/":
 		m.webVideoIndex(rw, req)
 
+	case strings.HasPrefix(url, APIPath+"/"): // JSON API.
+		m.serveAPI(rw, req)
+
 	case strings.HasPrefix(url, "/add"): // remote add video from js link.
 		m.webVideoAdd(rw, req)
 
@@ -133,8 +162,25 @@ func (m *Manager) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	case strings.HasPrefix(url, "/list"):
 		m.webVideoList(rw, req, m.history.List)
 
+	case strings.HasPrefix(url, "/queuemove"):
+		m.webVideoQueueMove(rw, req)
+
+	case strings.HasPrefix(url, "/queueremove"):
+		m.webVideoQueueRemove(rw, req)
+
 	case strings.HasPrefix(url, "/queue"):
-		m.webVideoList(rw, req, m.history.Queue)
+		m.webVideoQueue(rw, req)
+
+	case strings.HasPrefix(url, "/pause"):
+		m.Pause()
+		m.webVideoState(rw, req)
+
+	case strings.HasPrefix(url, "/resume"):
+		m.Resume()
+		m.webVideoState(rw, req)
+
+	case strings.HasPrefix(url, "/state"):
+		m.webVideoState(rw, req)
 
 	default:
 		m.log.NewErr(req.URL.String(), "webservice: bad address call=")
@@ -156,8 +202,12 @@ func (m *Manager) webVideoAdd(w http.ResponseWriter, r *http.Request) {
 }
 
 func (m *Manager) webLinkAdd(w http.ResponseWriter, r *http.Request) {
+	token := ""
+	if m.WebToken != "" {
+		token = `+'&token=` + url.QueryEscape(m.WebToken) + `'`
+	}
 	w.Write([]byte(`javascript:u=document.location.href;t=document.title;` +
-		`dock="http://localhost:` + strconv.Itoa(websrv.Service.Port) + `/video/add/?title="+escape(t)+'&url='+escape(u);` +
+		`dock="` + m.WebURL() + `/add/?title="+escape(t)+'&url='+escape(u)` + token + `;` +
 		`void(window.open(dock,'_blank','` + m.JSWindowOption + "'));"))
 }
 
@@ -191,6 +241,53 @@ func (m *Manager) webVideoList(w http.ResponseWriter, r *http.Request, list []*V
 	m.log.Err(e, "webservice: json.Encode videodl list")
 }
 
+func (m *Manager) webVideoQueue(w http.ResponseWriter, r *http.Request) {
+	queue := m.history.QueueList()
+	entries := make([]Entry, len(queue))
+	m.mu.Lock()
+	for k, v := range queue {
+		entries[k].Name = v.Name
+		entries[k].Mode = 0644
+		entries[k].URL = html.EscapeString(v.URL)
+		entries[k].Fail = v.Fail
+		if j, ok := m.jobs[v]; ok {
+			entries[k].Active = true
+			entries[k].Progress = int(j.progress.Fraction() * 100)
+		}
+	}
+	m.mu.Unlock()
+	e := json.NewEncoder(w).Encode(&entries)
+	m.log.Err(e, "webservice: json.Encode videodl queue")
+}
+
+func (m *Manager) webVideoQueueMove(w http.ResponseWriter, r *http.Request) {
+	vid := m.findVideo(r)
+	if vid == nil {
+		return
+	}
+	move, ok := ParseQueueMove(r.URL.Query().Get("move"))
+	if !ok {
+		m.log.NewErr("bad move: "+r.URL.Query().Get("move"), "webservice: queuemove")
+		return
+	}
+	e := m.MoveQueued(vid, move)
+	m.log.Err(e, "queuemove", "webservice")
+}
+
+func (m *Manager) webVideoQueueRemove(w http.ResponseWriter, r *http.Request) {
+	vid := m.findVideo(r)
+	if vid == nil {
+		return
+	}
+	e := m.RemoveQueued(vid)
+	m.log.Err(e, "queueremove", "webservice")
+}
+
+func (m *Manager) webVideoState(w http.ResponseWriter, r *http.Request) {
+	e := json.NewEncoder(w).Encode(m.State())
+	m.log.Err(e, "webservice: json.Encode videodl state")
+}
+
 func (m *Manager) webVideoRemoveOne(w http.ResponseWriter, r *http.Request, list *[]*Video) {
 	vid := m.findVideo(r)
 	if vid == nil {
@@ -227,7 +324,7 @@ func (m *Manager) webVideoFileInfo(w http.ResponseWriter, r *http.Request) {
 		if strviewed == "true" {
 			view = true
 		}
-		vid.Viewed = view
+		m.log.Err(m.history.SetViewed(vid, view), "webservice: set viewed")
 	}
 
 	e := json.NewEncoder(w).Encode(vid)
@@ -261,9 +358,9 @@ func (m *Manager) webVideoViewed(w http.ResponseWriter, r *http.Request) {
 		view = true
 	}
 
-	vid.Viewed = view
+	m.log.Err(m.history.SetViewed(vid, view), "webVideoViewed")
 
-	if vid.Viewed {
+	if view {
 		w.Write([]byte("true"))
 	} else {
 		w.Write([]byte("false"))
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 const (
@@ -40,6 +41,7 @@ type YoutubeDLFile struct {
 
 	log cdtype.Logger
 	cmd *exec.Cmd
+	mu  sync.Mutex
 }
 
 // NewYoutubeDLFile creates a video file downloader.
@@ -59,14 +61,27 @@ func (f *YoutubeDLFile) Title() (string, error) {
 
 // DownloadCmd downloads the video file from server.
 //
-func (f *YoutubeDLFile) DownloadCmd(path string, format *Format, progress *Progress) func() error {
-	args := []string{"--all-subs", "-i", f.url}
+func (f *YoutubeDLFile) DownloadCmd(path string, format *Format, progress *Progress, limit Limit) func() error {
+	args := []string{"--all-subs", "--newline", "-i", f.url}
 	// if quality < len(f.formats) {
 	q := strconv.Itoa(format.Itag)
 	args = append([]string{"-f", q}, args...)
 	// }
+	if rate := limit.CommandRate(); rate > 0 {
+		args = append([]string{"--limit-rate", strconv.FormatInt(rate, 10)}, args...)
+	}
 	cmd := f.log.ExecCmd(cmdName, args...)
 	cmd.Dir = path
+	cmd.Stdout = linesplit.NewWriter(func(s string) { // [download]  42.3% of 12.34MiB at ...
+		fields := strings.Fields(s)
+		if len(fields) > 1 && fields[0] == "[download]" && strings.HasSuffix(fields[1], "%") {
+			percent, e := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+			if e == nil {
+				progress.SetMax(1000)
+				progress.set(int64(percent * 10))
+			}
+		}
+	})
 
 	return f.runCmd(cmd)
 }
@@ -134,11 +149,28 @@ func (f *YoutubeDLFile) Formats() ([]*Format, error) {
 	return f.formats, e
 }
 
+// Cancel stops the download in progress.
+//
+func (f *YoutubeDLFile) Cancel() {
+	f.mu.Lock()
+	if f.cmd != nil && f.cmd.Process != nil {
+		f.cmd.Process.Kill()
+	}
+	f.mu.Unlock()
+}
+
 func (f *YoutubeDLFile) runCmd(cmd *exec.Cmd) func() error {
 	return func() error {
+		f.mu.Lock()
 		f.cmd = cmd
-		e := f.cmd.Run()
+		e := f.cmd.Start()
+		f.mu.Unlock()
+		if e == nil {
+			e = cmd.Wait()
+		}
+		f.mu.Lock()
 		f.cmd = nil
+		f.mu.Unlock()
 		return e
 	}
 }
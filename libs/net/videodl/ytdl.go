@@ -6,6 +6,7 @@ import (
 	"github.com/sqp/godock/libs/cdtype"
 
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 )
@@ -49,6 +51,8 @@ type YTDLFile struct {
 
 	formats []*Format
 	log     cdtype.Logger
+	cancel  func()
+	mu      sync.Mutex
 }
 
 // NewYTDLFile creates a video file downloader.
@@ -116,7 +120,7 @@ func (f *YTDLFile) Title() (string, error) {
 
 // DownloadCmd downloads the video file from server.
 //
-func (f *YTDLFile) DownloadCmd(path string, locformat *Format, progress *Progress) func() error {
+func (f *YTDLFile) DownloadCmd(path string, locformat *Format, progress *Progress, limit Limit) func() error {
 	var format ytdl.Format
 	var found bool
 	for _, q := range f.VideoInfo.Formats {
@@ -191,6 +195,15 @@ func (f *YTDLFile) DownloadCmd(path string, locformat *Format, progress *Progres
 		f.log.Info("Downloading to", of.Name()) // out.(*os.File).Name())
 
 		req, e := http.NewRequest("GET", downloadURL.String(), nil)
+		if e != nil {
+			return e
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		f.mu.Lock()
+		f.cancel = cancel
+		f.mu.Unlock()
+		req = req.WithContext(ctx)
 
 		// if byte range flag is set, use http range header option
 		// if options.byteRange != "" || options.append {
@@ -223,10 +236,8 @@ func (f *YTDLFile) DownloadCmd(path string, locformat *Format, progress *Progres
 		// progressBar.Start()
 		// defer progressBar.Finish()
 
-		io.MultiWriter(of, progress)
-
 		progress.SetMax(resp.ContentLength)
-		_, e = io.Copy(of, resp.Body)
+		_, e = io.Copy(io.MultiWriter(of, progress), limit.Reader(resp.Body))
 
 		f.log.Info("Finished")
 		return e
@@ -239,6 +250,16 @@ func (f *YTDLFile) Formats() ([]*Format, error) {
 	return f.formats, nil
 }
 
+// Cancel stops the download in progress.
+//
+func (f *YTDLFile) Cancel() {
+	f.mu.Lock()
+	if f.cancel != nil {
+		f.cancel()
+	}
+	f.mu.Unlock()
+}
+
 //
 //
 //
@@ -0,0 +1,88 @@
+package websrv
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Access defines the access rules of a registered service.
+//
+// The zero value only allows clients on the same host, without
+// authentication, which was the default behavior.
+//
+type Access struct {
+	Token    string // Token needed, as "Authorization: Bearer" header or token query param.
+	User     string // Basic authentication user.
+	Password string // Basic authentication password.
+	AllowLAN bool   // Allow clients from other hosts.
+}
+
+// HasAuth returns whether an authentication is required.
+//
+func (a Access) HasAuth() bool {
+	return a.Token != "" || a.User != ""
+}
+
+// Check returns the HTTP status code for the request: StatusOK if allowed,
+// StatusForbidden for a client from another host when not allowed, or
+// StatusUnauthorized when authentication failed.
+//
+func (a Access) Check(req *http.Request) int {
+	if !a.AllowLAN && !IsLoopback(req) {
+		return http.StatusForbidden
+	}
+	if !a.HasAuth() {
+		return http.StatusOK
+	}
+
+	if a.Token != "" {
+		token := req.URL.Query().Get("token")
+		if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if token != "" && equal(token, a.Token) {
+			return http.StatusOK
+		}
+	}
+
+	if a.User != "" {
+		user, password, ok := req.BasicAuth()
+		if ok && equal(user, a.User) && equal(password, a.Password) {
+			return http.StatusOK
+		}
+	}
+	return http.StatusUnauthorized
+}
+
+// redactURL returns the request url for logs, with the token param hidden.
+//
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if _, ok := query["token"]; !ok {
+		return u.String()
+	}
+	query.Set("token", "xxx")
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// IsLoopback returns whether the request comes from the same host.
+//
+func IsLoopback(req *http.Request) bool {
+	host, _, e := net.SplitHostPort(req.RemoteAddr)
+	if e != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// equal compares strings in constant time.
+//
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,111 @@
+package websrv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Certificate files, in the certificate dir.
+//
+const (
+	CertFile = "websrv.crt"
+	KeyFile  = "websrv.key"
+)
+
+// CertValidity is the validity of generated certificates.
+//
+var CertValidity = 10 * 365 * 24 * time.Hour
+
+// LoadCert returns the location of the certificate and key files in the dir.
+// A self-signed certificate is generated if missing, for localhost and the
+// given hosts (names or IP addresses).
+//
+func LoadCert(dir string, hosts ...string) (certFile, keyFile string, e error) {
+	certFile = filepath.Join(dir, CertFile)
+	keyFile = filepath.Join(dir, KeyFile)
+	_, ec := os.Stat(certFile)
+	_, ek := os.Stat(keyFile)
+	if ec == nil && ek == nil {
+		return certFile, keyFile, nil
+	}
+	return certFile, keyFile, GenerateCert(certFile, keyFile, hosts...)
+}
+
+// GenerateCert creates a self-signed certificate for localhost and the given
+// hosts (names or IP addresses), and saves it with its key.
+//
+func GenerateCert(certFile, keyFile string, hosts ...string) error {
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if e != nil {
+		return e
+	}
+	serial, e := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if e != nil {
+		return e
+	}
+
+	now := time.Now()
+	tmpl := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"godock"}, CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(CertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if name, e := os.Hostname(); e == nil {
+		tmpl.DNSNames = append(tmpl.DNSNames, name)
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else if host != "" && host != "localhost" {
+			tmpl.DNSNames = append(tmpl.DNSNames, host)
+		}
+	}
+
+	der, e := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if e != nil {
+		return e
+	}
+	keyDer, e := x509.MarshalECPrivateKey(key)
+	if e != nil {
+		return e
+	}
+
+	e = os.MkdirAll(filepath.Dir(certFile), os.ModePerm)
+	if e != nil {
+		return e
+	}
+	e = writePEM(keyFile, "EC PRIVATE KEY", keyDer, 0600)
+	if e != nil {
+		return e
+	}
+	return writePEM(certFile, "CERTIFICATE", der, 0644)
+}
+
+func writePEM(file, typ string, data []byte, mode os.FileMode) error {
+	f, e := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if e != nil {
+		return e
+	}
+	e = pem.Encode(f, &pem.Block{Type: typ, Bytes: data})
+	if e != nil {
+		f.Close()
+		return e
+	}
+	return f.Close()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/sqp/godock/libs/cdtype" // Logger type.
 	// Secure handling crashs.
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof" // Web service for pprof.
 	"strconv"
@@ -44,6 +45,7 @@ type service struct {
 	started bool
 	call    http.HandlerFunc
 	log     cdtype.Logger
+	access  Access
 }
 
 // Srv defines a web service handling subservices.
@@ -52,6 +54,10 @@ type Srv struct {
 	Host string // Where to
 	Port int    // Listen.
 
+	Interface string // Network interface to listen on (overrides Host).
+	TLS       bool   // Use HTTPS, with a self-signed certificate if none found.
+	CertDir   string // Location of the certificate files.
+
 	list map[string]*service // Registered services.
 	log  cdtype.Logger       // Logger.
 }
@@ -72,10 +78,102 @@ func NewSrv(host string, port int, log cdtype.Logger) *Srv {
 	return srv
 }
 
-// URL returns the service location: host:port
+// URL returns the service location for clients: host:port
 //
 func (s *Srv) URL() string {
-	return s.Host + ":" + strconv.Itoa(s.Port)
+	host := s.Host
+	if s.Interface != "" {
+		ip, e := InterfaceIP(s.Interface)
+		if e == nil {
+			host = ip.String()
+		}
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(s.Port))
+}
+
+// Scheme returns the URL scheme of the service: http or https.
+//
+func (s *Srv) Scheme() string {
+	if s.TLS {
+		return "https"
+	}
+	return "http"
+}
+
+// SetAccess sets the access rules of the service matching the given prefix key.
+//
+func (s *Srv) SetAccess(key string, access Access) error {
+	svc, ok := s.list[key]
+	if !ok {
+		return fmt.Errorf("set web service access: key not found: %s", key)
+	}
+	svc.access = access
+	return nil
+}
+
+// listenAddr returns the address to listen on: host:port
+//
+func (s *Srv) listenAddr() (string, error) {
+	host := s.Host
+	if s.Interface != "" {
+		ip, e := InterfaceIP(s.Interface)
+		if e != nil {
+			return "", e
+		}
+		host = ip.String()
+	}
+	return net.JoinHostPort(host, strconv.Itoa(s.Port)), nil
+}
+
+// listen starts the web server. It blocks until the server is closed.
+//
+func (s *Srv) listen() error {
+	addr, e := s.listenAddr()
+	if e != nil {
+		return e
+	}
+	if !s.TLS {
+		return manners.ListenAndServe(addr, s)
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	certFile, keyFile, e := LoadCert(s.CertDir, host)
+	if e != nil {
+		return e
+	}
+	return manners.ListenAndServeTLS(addr, certFile, keyFile, s)
+}
+
+// InterfaceIP returns the first IP address of the network interface, IPv4 if
+// available.
+//
+func InterfaceIP(name string) (net.IP, error) {
+	iface, e := net.InterfaceByName(name)
+	if e != nil {
+		return nil, e
+	}
+	addrs, e := iface.Addrs()
+	if e != nil {
+		return nil, e
+	}
+	var found net.IP
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		switch {
+		case !ok:
+		case ipnet.IP.To4() != nil:
+			return ipnet.IP, nil
+		case found == nil:
+			found = ipnet.IP
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no address for interface %s", name)
+	}
+	return found, nil
 }
 
 // Register registers the service matching the given prefix key.
@@ -127,7 +225,7 @@ func (s *Srv) Start(key string) error {
 		return nil
 	}
 	s.log.GoTry(func() {
-		e := manners.ListenAndServe(s.URL(), s)
+		e := s.listen()
 		svc.log.Err(e, "start web server")
 	})
 
@@ -157,18 +255,34 @@ func (s *Srv) Stop(key string) error {
 //
 func (s *Srv) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	url := req.URL.String()
+	logURL := redactURL(req.URL) // Never log the access token.
 	for prefix, svc := range s.list {
 		if strings.HasPrefix(url, "/"+prefix) {
-			if svc.started {
-				svc.log.Debug("served", req.RemoteAddr+" asked "+url+"  UA="+req.UserAgent())
+			if !svc.started {
+				// Should we log refused because inactive?
+				return
+			}
+
+			switch code := svc.access.Check(req); code {
+			case http.StatusOK:
+				svc.log.Debug("served", req.RemoteAddr+" asked "+logURL+"  UA="+req.UserAgent())
 				defer s.log.Recover()
 				svc.call(rw, req)
+
+			case http.StatusUnauthorized:
+				if svc.access.User != "" {
+					rw.Header().Set("WWW-Authenticate", `Basic realm="`+prefix+`"`)
+				}
+				fallthrough
+
+			default:
+				svc.log.Debug("refused", req.RemoteAddr+" asked "+logURL+"  code="+strconv.Itoa(code))
+				http.Error(rw, http.StatusText(code), code)
 			}
-			// Should we log refused because inactive?
 			return
 		}
 	}
-	s.log.Debug("refused", req.RemoteAddr+" asked "+url+"  UA="+req.UserAgent())
+	s.log.Debug("refused", req.RemoteAddr+" asked "+logURL+"  UA="+req.UserAgent())
 }
 
 // IsMonitored returns whether monitoring pages are active or not.
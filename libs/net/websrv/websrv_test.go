@@ -0,0 +1,97 @@
+package websrv
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sqp/godock/libs/log"
+
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestAccess(t *testing.T) {
+	srv := NewSrv("", 0, log.NewLog(log.Logs).SetName("websrv_test"))
+	ok := func(rw http.ResponseWriter, req *http.Request) { rw.Write([]byte("ok")) }
+	assert.NoError(t, srv.Register("video", ok, srv.log), "Register")
+	for _, svc := range srv.list {
+		svc.started = true // Without listening.
+	}
+
+	call := func(url, remote string, edit func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		if remote != "" {
+			req.RemoteAddr = remote
+		}
+		if edit != nil {
+			edit(req)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+	local, lan := "127.0.0.1:41000", "192.168.1.20:41000"
+
+	// Default: local only.
+	assert.Equal(t, http.StatusOK, call("/video/list", local, nil).Code, "local")
+	assert.Equal(t, http.StatusOK, call("/video/list", "[::1]:41000", nil).Code, "local ipv6")
+	assert.Equal(t, http.StatusForbidden, call("/video/list", lan, nil).Code, "lan")
+
+	// Token.
+	assert.NoError(t, srv.SetAccess("video", Access{Token: "secret", AllowLAN: true}), "SetAccess")
+	assert.Error(t, srv.SetAccess("audio", Access{}), "SetAccess unknown")
+	assert.Equal(t, http.StatusUnauthorized, call("/video/list", lan, nil).Code, "token missing")
+	assert.Equal(t, http.StatusUnauthorized, call("/video/list?token=bad", lan, nil).Code, "token bad")
+	assert.Equal(t, http.StatusOK, call("/video/list?token=secret", lan, nil).Code, "token query")
+	assert.Equal(t, http.StatusOK, call("/video/list", lan, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer secret")
+	}).Code, "token header")
+	assert.Equal(t, http.StatusForbidden, call("/"+PathPprof+"/", lan, nil).Code, "pprof lan")
+
+	// Basic auth.
+	srv.SetAccess("video", Access{User: "me", Password: "pass", AllowLAN: true})
+	rec := call("/video/list", lan, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "basic missing")
+	assert.Equal(t, `Basic realm="video"`, rec.Header().Get("WWW-Authenticate"), "basic realm")
+	assert.Equal(t, http.StatusUnauthorized, call("/video/list", lan, func(req *http.Request) {
+		req.SetBasicAuth("me", "bad")
+	}).Code, "basic bad")
+	rec = call("/video/list", lan, func(req *http.Request) { req.SetBasicAuth("me", "pass") })
+	assert.Equal(t, http.StatusOK, rec.Code, "basic")
+	assert.Equal(t, "ok", rec.Body.String(), "basic served")
+
+	// Logs.
+	req := httptest.NewRequest("GET", "/video/list?id=2&token=secret", nil)
+	assert.Equal(t, "/video/list?id=2&token=xxx", redactURL(req.URL), "token redacted")
+	req = httptest.NewRequest("GET", "/video/list?id=2", nil)
+	assert.Equal(t, "/video/list?id=2", redactURL(req.URL), "no token")
+}
+
+func TestCert(t *testing.T) {
+	dir, e := ioutil.TempDir("", "websrv")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile, e := LoadCert(dir, "192.168.1.2", "box.lan")
+	if !assert.NoError(t, e, "LoadCert") {
+		return
+	}
+	pair, e := tls.LoadX509KeyPair(certFile, keyFile)
+	assert.NoError(t, e, "LoadX509KeyPair")
+	assert.NotEmpty(t, pair.Certificate, "certificate")
+
+	info, e := os.Stat(keyFile)
+	if assert.NoError(t, e, "stat key") {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "key mode")
+	}
+
+	before, _ := ioutil.ReadFile(certFile)
+	LoadCert(dir)
+	after, _ := ioutil.ReadFile(certFile)
+	assert.Equal(t, before, after, "certificate reused")
+}
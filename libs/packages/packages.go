@@ -5,14 +5,15 @@ import (
 	"github.com/sqp/godock/libs/cdglobal"      // Dock types.
 	"github.com/sqp/godock/libs/cdtype"        // Logger type.
 	"github.com/sqp/godock/libs/config"        // Config parser.
+	"github.com/sqp/godock/libs/net/download"  // Web client.
 	"github.com/sqp/godock/libs/text/bytesize" // Human readable bytes.
 	"github.com/sqp/godock/libs/text/tran"     // Translate.
 
+	"bytes"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
@@ -246,15 +247,13 @@ func ListDistant(log cdtype.Logger, version string) (AppletPackages, error) {
 	url := cdglobal.DownloadServerURL + "/" + version
 
 	// Download list from packages server.
-	resp, e := http.Get(url + "/" + cdglobal.DownloadServerListFile)
+	body, e := download.Get(url + "/" + cdglobal.DownloadServerListFile)
 	if e != nil {
 		return nil, e
 	}
 
-	defer resp.Body.Close()
-
 	// Parse distant list.
-	cfg, e := config.NewFromReader(resp.Body) // Special conf reflector around the config file parser.
+	cfg, e := config.NewFromReader(bytes.NewReader(body)) // Special conf reflector around the config file parser.
 	if e != nil {
 		return nil, e
 	}
@@ -470,13 +469,7 @@ func (pack *AppletPackage) GetPreview(tmp string) (string, bool) {
 	switch pack.Type {
 	case cdtype.PackTypeDistant, cdtype.PackTypeNew: // Applets not on disk.
 
-		resp, e := http.Get(pack.Path + "/preview")
-		if pack.log.Err(e, "Get applet image") {
-			return "", false
-		}
-		defer resp.Body.Close()
-
-		result, e := ioutil.ReadAll(resp.Body)
+		result, e := download.Get(pack.Path + "/preview")
 		if pack.log.Err(e, "Download applet image") {
 			return "", false
 		}
@@ -518,13 +511,7 @@ func (pack *AppletPackage) GetDescription() string {
 
 		case cdtype.PackTypeDistant, cdtype.PackTypeNew: // Applets not on disk.
 
-			resp, e := http.Get(pack.Path + "/readme")
-			if pack.log.Err(e, "Get applet readme") {
-				return ""
-			}
-			defer resp.Body.Close()
-
-			result, e := ioutil.ReadAll(resp.Body)
+			result, e := download.Get(pack.Path + "/readme")
 			if pack.log.Err(e, "Download applet readme") {
 				return ""
 			}
@@ -283,16 +283,19 @@ func SessionBus() (*dbus.Conn, chan *dbus.Signal, error) {
 	return conn, c, nil
 }
 
-// EavesDrop registers to receive Dbus events for custom parsing.
+// EavesDrop registers to receive Dbus events for custom parsing, with one or
+// more match rules.
 //
-func EavesDrop(match string) (chan *dbus.Message, error) {
+func EavesDrop(match ...string) (chan *dbus.Message, error) {
 	conn, e := dbus.SessionBus()
 	if e != nil {
 		return nil, e
 	}
-	e = conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, match).Err
-	if e != nil {
-		return nil, e
+	for _, rule := range match {
+		e = conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, rule).Err
+		if e != nil {
+			return nil, e
+		}
 	}
 	c := make(chan *dbus.Message, 10)
 	conn.Eavesdrop(c)
@@ -140,11 +140,27 @@ func (load *Loader) UpToShareLastLink() (string, *dbus.Error) {
 	return link, e
 }
 
-// UpToShareLinks gets all links of items sent to one-click hosting services.
+// UpToShareLinks gets links of items sent to one-click hosting services,
+// matching the query (see uptoshare.ParseQuery for fields). Newest first.
 //
-func (load *Loader) UpToShareLinks() (out []map[string]string, e *dbus.Error) {
+func (load *Loader) UpToShareLinks(query map[string]string) (out []map[string]string, e *dbus.Error) {
 	e = load.uploaderAction(func(app uploader) {
-		out = app.UpToShareLinks()
+		var err error
+		out, err = app.UpToShareLinks(query)
+		if err != nil {
+			e = dbuscommon.NewError(err.Error())
+		}
+	})
+	return
+}
+
+// UpToShareDelete deletes an uploaded file from its hosting service.
+//
+func (load *Loader) UpToShareDelete(link string) (e *dbus.Error) {
+	e = load.uploaderAction(func(app uploader) {
+		if err := app.UpToShareDelete(link); err != nil {
+			e = dbuscommon.NewError(err.Error())
+		}
 	})
 	return
 }
@@ -321,17 +337,30 @@ func UpToShareLastLink() (string, error) {
 	return link, e
 }
 
-// UpToShareLinks get all uploaded links from the dock.
+// UpToShareLinks get uploaded links matching the query from the dock.
 //
-func UpToShareLinks() (string, error) {
+func UpToShareLinks(query map[string]string) ([]map[string]string, error) {
 	client, e := dbuscommon.GetClient(SrvObj, SrvPath)
 	if e != nil {
-		return "", e
+		return nil, e
 	}
 
-	var link string
-	e = client.Get("UpToShareLinks", []interface{}{&link})
-	return link, e
+	var links []map[string]string
+	if query == nil {
+		query = make(map[string]string) // Dbus needs a value.
+	}
+	e = client.Get("UpToShareLinks", []interface{}{&links}, query)
+	return links, e
+}
+
+// UpToShareDelete deletes an uploaded file from its hosting service.
+//
+func UpToShareDelete(link string) error {
+	client, e := dbuscommon.GetClient(SrvObj, SrvPath)
+	if e != nil {
+		return e
+	}
+	return client.Call("UpToShareDelete", link)
 }
 
 //
@@ -362,7 +391,8 @@ type uploader interface {
 	UploadFiles(...string)
 	UpToShareUploadString(string)
 	UpToShareLastLink() string
-	UpToShareLinks() []map[string]string
+	UpToShareLinks(query map[string]string) ([]map[string]string, error)
+	UpToShareDelete(link string) error
 }
 
 func (load *Loader) uploaderAction(call func(sc uploader)) *dbus.Error {
@@ -0,0 +1,229 @@
+package sysinfo
+
+import (
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
+
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AlertAction defines the action triggered when an alert is raised.
+//
+type AlertAction int
+
+// Alert actions.
+const (
+	AlertEmblem    AlertAction = iota // Set an emblem on the icon until the alert is cleared.
+	AlertAnimation                    // Animate the icon until the alert is cleared.
+	AlertDialog                       // Show a dialog with the alert details.
+	AlertSound                        // Play a sound file.
+	AlertCommand                      // Launch a command with the value name and value as arguments.
+)
+
+// alertDialogDuration is the display duration of an alert dialog, in seconds.
+const alertDialogDuration = 10
+
+// EmblemAlert is the position of the alert emblem.
+const EmblemAlert = cdtype.EmblemBottomRight
+
+// AlertConfig defines user settings for a threshold alert.
+// It can be embedded in an applet config, with its own group.
+//
+// The threshold and hysteresis are in the unit displayed by the applet:
+// percent for RenderPercent values, or KiB/s for IOActivity rates.
+//
+type AlertConfig struct {
+	AlertEnabled    bool
+	AlertValues     []string        // Names of values to watch. Empty to watch all values.
+	AlertThreshold  float64         // Alert is raised when the value goes above.
+	AlertSustain    cdtype.Duration // Time the value must stay above the threshold.
+	AlertHysteresis float64         // Alert is cleared when the value goes below threshold - hysteresis.
+	AlertAction     AlertAction
+	AlertEmblem     string `default:"dialog-warning"`
+	AlertAnimation  string `default:"default"`
+	AlertSound      string
+	AlertCommand    string
+}
+
+// alertState tracks the alert status of one value.
+//
+type alertState struct {
+	since  time.Time // Time the value first went above the threshold.
+	active bool      // True when the alert has been raised.
+}
+
+// Alert monitors values against a threshold, with a sustain duration and an
+// hysteresis to prevent flapping.
+//
+// A nil Alert can be used safely and will do nothing.
+//
+type Alert struct {
+	Scale float64 // Multiplier applied to checked values before comparison (0 for none).
+
+	app        cdtype.AppBase
+	conf       AlertConfig
+	states     map[string]*alertState
+	thresholds map[string]float64 // Thresholds overriding the config one, by value name.
+	ignored    map[string]bool    // Values never checked, by value name.
+	active     int                // Number of raised alerts.
+}
+
+// NewAlert creates a threshold alert for the applet.
+//
+func NewAlert(app cdtype.AppBase) *Alert {
+	return &Alert{
+		app:    app,
+		states: make(map[string]*alertState),
+	}
+}
+
+// SetConfig sets the alert user settings and resets the alert state.
+//
+func (al *Alert) SetConfig(conf AlertConfig) {
+	al.Clear()
+	al.conf = conf
+}
+
+// SetThresholds sets thresholds for some values, overriding the config one.
+// The hysteresis still applies.
+//
+func (al *Alert) SetThresholds(thresholds map[string]float64) {
+	al.thresholds = thresholds
+}
+
+// SetIgnored sets values that are never checked, even when watched by the
+// config. Used for values in a unit the threshold doesn't apply to.
+//
+func (al *Alert) SetIgnored(names ...string) {
+	al.ignored = make(map[string]bool)
+	for _, name := range names {
+		al.ignored[name] = true
+	}
+}
+
+// Clear resets all alerts, removing their visible effects.
+//
+func (al *Alert) Clear() {
+	if al.active > 0 {
+		al.stopEffects()
+	}
+	al.states = make(map[string]*alertState)
+	al.active = 0
+}
+
+// Check tests a new value for the given name. The value is in the applet
+// displayed unit, before the Scale multiplier.
+//
+func (al *Alert) Check(name string, value float64) {
+	if al == nil || !al.conf.AlertEnabled || !al.isWatched(name) {
+		return
+	}
+	if al.Scale != 0 {
+		value *= al.Scale
+	}
+
+	threshold, ok := al.thresholds[name]
+	if !ok {
+		threshold = al.conf.AlertThreshold
+	}
+
+	state, ok := al.states[name]
+	if !ok {
+		state = &alertState{}
+		al.states[name] = state
+	}
+
+	switch {
+	case value > threshold:
+		if state.since.IsZero() {
+			state.since = time.Now()
+		}
+		sustain := time.Duration(al.conf.AlertSustain.Value()) * time.Second
+		if !state.active && time.Since(state.since) >= sustain {
+			state.active = true
+			al.active++
+			al.raise(name, value, threshold)
+		}
+
+	case value < threshold-al.conf.AlertHysteresis:
+		state.since = time.Time{}
+		if state.active {
+			state.active = false
+			al.active--
+			if al.active == 0 {
+				al.stopEffects()
+			}
+		}
+
+	default: // Inside the hysteresis band: the alert keeps its state.
+		if !state.active {
+			state.since = time.Time{}
+		}
+	}
+}
+
+// isWatched returns whether the value name must be checked.
+//
+func (al *Alert) isWatched(name string) bool {
+	if al.ignored[name] {
+		return false
+	}
+	if len(al.conf.AlertValues) == 0 {
+		return true
+	}
+	for _, watched := range al.conf.AlertValues {
+		if watched == name {
+			return true
+		}
+	}
+	return false
+}
+
+// raise triggers the configured alert action.
+//
+func (al *Alert) raise(name string, value, threshold float64) {
+	log := al.app.Log()
+	switch al.conf.AlertAction {
+	case AlertEmblem:
+		if al.active == 1 {
+			log.Err(al.app.SetEmblem(al.conf.AlertEmblem, EmblemAlert), "alert emblem")
+		}
+
+	case AlertAnimation:
+		if al.active == 1 {
+			log.Err(al.app.DemandsAttention(true, al.conf.AlertAnimation), "alert animation")
+		}
+
+	case AlertDialog:
+		msg := fmt.Sprintf("%s: %s > %s", name, formatAlert(value), formatAlert(threshold))
+		log.Err(al.app.ShowDialog(msg, alertDialogDuration), "alert dialog")
+
+	case AlertSound:
+		log.Err(log.PlaySound(al.conf.AlertSound), "alert sound")
+
+	case AlertCommand:
+		cmd, e := log.ExecShlex(al.conf.AlertCommand, name, formatAlert(value))
+		if !log.Err(e, "alert command") {
+			log.Err(cmd.Start(), "alert command")
+		}
+	}
+}
+
+// stopEffects removes the persistent effects of raised alerts.
+//
+func (al *Alert) stopEffects() {
+	switch al.conf.AlertAction {
+	case AlertEmblem:
+		al.app.Log().Err(al.app.SetEmblem("none", EmblemAlert), "alert emblem")
+
+	case AlertAnimation:
+		al.app.Log().Err(al.app.DemandsAttention(false, ""), "alert animation")
+	}
+}
+
+// format value for alert messages and commands.
+//
+func formatAlert(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
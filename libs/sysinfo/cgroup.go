@@ -0,0 +1,162 @@
+package sysinfo
+
+import (
+	"github.com/sqp/godock/libs/text/bytesize"
+
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SysCgroupDir is the cgroup filesystem mount point.
+const SysCgroupDir = "/sys/fs/cgroup"
+
+// CgroupIO defines the storage I/O counters of a cgroup v2.
+//
+type CgroupIO struct {
+	Path      string  // Path relative to the cgroup root.
+	Read      uint64  // Bytes read.
+	Write     uint64  // Bytes written.
+	ReadRate  float64 // Read rate during the last measure, in bytes per second.
+	WriteRate float64 // Write rate during the last measure, in bytes per second.
+}
+
+// CgroupRoot returns the cgroup v2 hierarchy location, either mounted alone
+// or in hybrid mode with cgroup v1.
+//
+func CgroupRoot() (string, error) {
+	for _, dir := range []string{SysCgroupDir, filepath.Join(SysCgroupDir, "unified")} {
+		if _, e := os.Stat(filepath.Join(dir, "cgroup.controllers")); e == nil {
+			return dir, nil
+		}
+	}
+	return "", errors.New("cgroup v2 not found")
+}
+
+// CgroupsIO returns the I/O counters of leaf cgroups in the hierarchy.
+// Parent groups are ignored as they include their children counters.
+//
+func CgroupsIO(root string) (list []*CgroupIO, e error) {
+	e = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() || hasSubdir(path) {
+			return nil // Unreadable group or not a leaf, keep walking.
+		}
+		file, err := os.Open(filepath.Join(path, "io.stat"))
+		if err != nil {
+			return nil // io controller not enabled for this group.
+		}
+		defer file.Close()
+
+		cg := &CgroupIO{Path: strings.TrimPrefix(path, root)}
+		cg.Read, cg.Write, err = ParseCgroupIOStat(file)
+		if err == nil {
+			list = append(list, cg)
+		}
+		return nil
+	})
+	return list, e
+}
+
+// TopCgroupIO returns the max cgroups using the most storage I/O (read + write)
+// during the given delay. This call is blocking for the duration of the delay.
+//
+func TopCgroupIO(root string, max int, delay time.Duration) ([]*CgroupIO, error) {
+	before, e := CgroupsIO(root)
+	if e != nil {
+		return nil, e
+	}
+	time.Sleep(delay)
+	after, e := CgroupsIO(root)
+	if e != nil {
+		return nil, e
+	}
+
+	last := make(map[string]*CgroupIO, len(before))
+	for _, cg := range before {
+		last[cg.Path] = cg
+	}
+
+	var list []*CgroupIO
+	for _, cg := range after {
+		prev, ok := last[cg.Path]
+		if !ok || cg.Read < prev.Read || cg.Write < prev.Write {
+			continue
+		}
+		cg.ReadRate = float64(cg.Read-prev.Read) / delay.Seconds()
+		cg.WriteRate = float64(cg.Write-prev.Write) / delay.Seconds()
+		if cg.ReadRate+cg.WriteRate > 0 {
+			list = append(list, cg)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ReadRate+list[i].WriteRate > list[j].ReadRate+list[j].WriteRate
+	})
+	if max > 0 && len(list) > max {
+		list = list[:max]
+	}
+	return list, nil
+}
+
+// ParseCgroupIOStat parses a cgroup v2 io.stat file, and returns the bytes
+// read and written on all devices.
+//
+// Format: "8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353 dbytes=0 dios=0"
+//
+func ParseCgroupIOStat(r io.Reader) (read, write uint64, e error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields[1:] { // Drop the device number.
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return 0, 0, errors.New("cgroup io.stat: failed parsing")
+			}
+			value, _ := strconv.ParseUint(kv[1], 10, 64)
+			switch kv[0] {
+			case "rbytes":
+				read += value
+			case "wbytes":
+				write += value
+			}
+		}
+	}
+	return read, write, scanner.Err()
+}
+
+// Format returns the cgroup path with its storage read and write rates.
+//
+func (cg *CgroupIO) Format() string {
+	return fmt.Sprintf("%s: r %s/s / w %s/s", cg.Path, bytesize.ByteSize(cg.ReadRate), bytesize.ByteSize(cg.WriteRate))
+}
+
+// hasSubdir returns whether the directory contains other directories.
+//
+func hasSubdir(dir string) bool {
+	file, e := os.Open(dir)
+	if e != nil {
+		return false
+	}
+	defer file.Close()
+
+	for {
+		list, e := file.Readdir(64)
+		for _, fi := range list {
+			if fi.IsDir() {
+				return true
+			}
+		}
+		if e != nil {
+			return false
+		}
+	}
+}
@@ -6,7 +6,10 @@ package sysinfo
 import (
 	"bufio"
 	"errors"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -110,6 +113,137 @@ func GetNetActivity() ([]Value, error) {
 	return values, nil
 }
 
+//
+//-----------------------------------------------------------[ PRESSURE DATA ]--
+
+// KernelPressureDir is the pressure stall information source directory.
+// Requires a kernel 4.20 or above, built with CONFIG_PSI.
+const KernelPressureDir = "/proc/pressure"
+
+// KernelLoadAvg is the load average information source.
+const KernelLoadAvg = "/proc/loadavg"
+
+// Pressure resources, as named in the kernel source directory.
+const (
+	PressureCPU    = "cpu"
+	PressureMemory = "memory"
+	PressureIO     = "io"
+)
+
+// PressureStall defines stall time averages for a pressure line, in percent.
+//
+type PressureStall struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64 // Total stall time, in microseconds.
+}
+
+// Pressure defines the pressure stall information of a resource.
+//
+// Some is the share of time at least one task was stalled on the resource.
+// Full is the share of time all non-idle tasks were stalled simultaneously.
+// The cpu full line is only provided by kernels 5.13 or above.
+//
+type Pressure struct {
+	Some    PressureStall
+	Full    PressureStall
+	HasFull bool
+}
+
+// ErrNoPressure is returned when the kernel doesn't provide pressure stall
+// information.
+var ErrNoPressure = errors.New("your kernel doesn't support pressure stall information. (4.20 or above required)")
+
+// HasPressure returns whether the kernel provides pressure stall information.
+//
+func HasPressure() bool {
+	_, e := os.Stat(KernelPressureDir)
+	return e == nil
+}
+
+// GetPressure returns pressure stall information for the given resource.
+//
+// Using Linux PSI : https://www.kernel.org/doc/Documentation/accounting/psi.txt
+//
+func GetPressure(resource string) (*Pressure, error) {
+	file, e := os.Open(filepath.Join(KernelPressureDir, resource))
+	if e != nil {
+		return nil, ErrNoPressure
+	}
+	defer file.Close()
+	return ParsePressure(file)
+}
+
+// ParsePressure parses pressure stall information in the kernel format.
+//
+func ParsePressure(r io.Reader) (*Pressure, error) {
+	psi := &Pressure{}
+	found := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		data := strings.Fields(scanner.Text())
+		if len(data) == 0 {
+			continue
+		}
+
+		var stall *PressureStall
+		switch data[0] {
+		case "some":
+			stall = &psi.Some
+			found = true
+
+		case "full":
+			stall = &psi.Full
+			psi.HasFull = true
+
+		default:
+			return nil, errors.New("pressure: failed parsing")
+		}
+
+		for _, field := range data[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.New("pressure: failed parsing")
+			}
+			switch kv[0] {
+			case "avg10":
+				stall.Avg10, _ = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				stall.Avg60, _ = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				stall.Avg300, _ = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				stall.Total, _ = strconv.ParseUint(kv[1], 10, 64)
+			}
+		}
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, e
+	}
+	if !found {
+		return nil, errors.New("pressure: no data")
+	}
+	return psi, nil
+}
+
+// GetLoadAverage returns the system load averages over 1, 5 and 15 minutes.
+//
+func GetLoadAverage() (load1, load5, load15 float64, e error) {
+	data, e := ioutil.ReadFile(KernelLoadAvg)
+	if e != nil {
+		return 0, 0, 0, e
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return 0, 0, 0, errors.New("load average: failed parsing")
+	}
+	load1, _ = strconv.ParseFloat(fields[0], 64)
+	load5, _ = strconv.ParseFloat(fields[1], 64)
+	load15, _ = strconv.ParseFloat(fields[2], 64)
+	return load1, load5, load15, nil
+}
+
 //
 //-------------------------------------------------------------[ DELTA STATS ]--
 
@@ -0,0 +1,77 @@
+package sysinfo
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Forecast estimates when a growing value will reach its limit, from an
+// exponential moving average of its growth rate.
+//
+type Forecast struct {
+	Window time.Duration // Averaging window of the growth rate.
+	MinAge time.Duration // Observation time required before giving a forecast.
+
+	first time.Time // Date of the first sample.
+	last  time.Time // Date of the last sample.
+	value float64   // Last value.
+	rate  float64   // Average growth, per second.
+}
+
+// NewForecast creates a forecast with the given averaging window. A forecast
+// will be given after half the window.
+//
+func NewForecast(window time.Duration) *Forecast {
+	return &Forecast{Window: window, MinAge: window / 2}
+}
+
+// Add adds a sample of the value.
+//
+func (fc *Forecast) Add(value float64, now time.Time) {
+	if fc.first.IsZero() {
+		fc.first = now
+		fc.last = now
+		fc.value = value
+		return
+	}
+
+	elapsed := now.Sub(fc.last)
+	if elapsed <= 0 {
+		return
+	}
+	rate := (value - fc.value) / elapsed.Seconds()
+
+	// Weight of the new sample, depending on the time it covers.
+	weight := 1 - math.Exp(-elapsed.Seconds()/fc.Window.Seconds())
+	if fc.last.Equal(fc.first) { // First rate computed.
+		weight = 1
+	}
+	fc.rate += (rate - fc.rate) * weight
+	fc.last = now
+	fc.value = value
+}
+
+// Remaining returns the estimated time before the value grows by the given
+// amount. ok is false when there is not enough data, or the value isn't
+// growing.
+//
+func (fc *Forecast) Remaining(left float64) (dur time.Duration, ok bool) {
+	if fc.rate <= 0 || fc.last.Sub(fc.first) < fc.MinAge {
+		return 0, false
+	}
+	seconds := left / fc.rate
+	if seconds > float64(math.MaxInt64/time.Second) {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// FormatRemaining formats a forecast duration as days, or hours if less than a day.
+//
+func FormatRemaining(dur time.Duration) string {
+	if dur < 24*time.Hour {
+		return fmt.Sprintf("%dh", int(dur.Hours()))
+	}
+	return fmt.Sprintf("%d days", int(dur.Hours()/24))
+}
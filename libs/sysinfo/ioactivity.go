@@ -10,8 +10,11 @@ import (
 // percent provides a couple of text and value to render.
 //
 type percent struct {
-	text  string
-	value float64
+	text    string
+	value   float64
+	raw     float64 // Value in the source unit, checked and recorded when display is set.
+	display string  // Optional value text, replacing the percent.
+	failed  bool    // Value couldn't be read: only the display text is rendered.
 }
 
 // RenderPercent provides a simple icon/label text renderer with values in percent.
@@ -20,8 +23,11 @@ type RenderPercent struct {
 	text   RenderOne
 	values []percent
 	App    cdtype.RenderSimple
+	Log    cdtype.Logger
 	Texts  map[cdtype.InfoPosition]RenderOne
 	cbText func(string) error // Display callback.
+	Alert  *Alert             // Optional threshold alert, checked with values in percent (or raw).
+	Record *Recorder          // Optional metrics recorder, with values in percent (or raw).
 
 	DisplayText   cdtype.InfoPosition
 	DisplayValues int
@@ -71,6 +77,21 @@ func (rp *RenderPercent) Append(str string, value float64) {
 	rp.values = append(rp.values, percent{text: str, value: value})
 }
 
+// AppendRaw adds a value to the renderer, with its source unit value and text.
+// The value must still be in the 0..1 range for the renderer, the raw value is
+// used for the alert and the recorder.
+//
+func (rp *RenderPercent) AppendRaw(str string, value, raw float64, display string) {
+	rp.values = append(rp.values, percent{text: str, value: value, raw: raw, display: display})
+}
+
+// AppendFailed adds a value that couldn't be read. Only its text is displayed,
+// it isn't checked by the alert nor recorded.
+//
+func (rp *RenderPercent) AppendFailed(str, display string) {
+	rp.values = append(rp.values, percent{text: str, display: display, failed: true})
+}
+
 // Display renders and displays the provided values.
 //
 func (rp *RenderPercent) Display() {
@@ -82,12 +103,21 @@ func (rp *RenderPercent) Display() {
 	rp.text.Clear()
 	for _, v := range rp.values {
 		values = append(values, v.value)
-		rp.text.Append(v.text, v.value*100)
-	}
-	e := rp.App.DataRenderer().Render(values...)
-	if e != nil {
-		println("ioactivity render", e)
+		checked := v.value * 100
+		if v.display != "" {
+			rp.text.AppendText(v.text, v.display)
+			checked = v.raw
+		} else {
+			rp.text.Append(v.text, checked)
+		}
+		if v.failed {
+			continue
+		}
+		rp.Alert.Check(v.text, checked)
+		rp.Record.Set(v.text, checked)
 	}
+	rp.Log.Err(rp.App.DataRenderer().Render(values...), "render values")
+	rp.Log.Err(rp.Record.Commit(), "record metrics")
 	rp.cbText(rp.text.Text())
 }
 
@@ -117,6 +147,12 @@ type RenderOne struct {
 // The value must be in the 0..100 range.
 //
 func (ro *RenderOne) Append(str string, value float64) {
+	ro.AppendText(str, FormatPercent(value))
+}
+
+// AppendText adds a new formatted value to the renderer.
+//
+func (ro *RenderOne) AppendText(str, value string) {
 	if ro.info != "" {
 		ro.info += ro.Sep
 	}
@@ -124,7 +160,7 @@ func (ro *RenderOne) Append(str string, value float64) {
 		ro.info += str + " "
 	}
 
-	ro.info += formatPercent(value)
+	ro.info += value
 
 	if ro.ShowPost && str != "" {
 		ro.info += " " + str
@@ -143,9 +179,9 @@ func (ro *RenderOne) Clear() {
 	ro.info = ""
 }
 
-// format value as percent.
+// FormatPercent formats a value as percent.
 //
-func formatPercent(value float64) string {
+func FormatPercent(value float64) string {
 	format := ""
 	switch {
 	case value < 0:
@@ -172,9 +208,15 @@ type IOActivity struct {
 
 	list     []*stat
 	interval uint64
+	render   bool                // True when values are rendered with a gauge or graph.
+	held     bool                // True when the data renderer is used by the applet.
+	renderer func()              // Sets the configured data renderer.
 	info     ITextInfo           // Paired values text renderer.
 	app      cdtype.RenderSimple // Controler to the Cairo-Dock icon.
 
+	Alert  *Alert    // Optional threshold alert, checked with the sum of in and out rates.
+	Record *Recorder // Optional metrics recorder, with in and out rates in the data source unit.
+
 	FormatIcon  FormatIO
 	FormatLabel FormatIO
 	GetData     func() ([]Value, error)
@@ -194,7 +236,10 @@ func (ioa *IOActivity) Settings(interval uint64, textPosition cdtype.InfoPositio
 	ioa.interval = interval
 
 	ioa.list = []*stat{} // Clear list. Nothing must remain.
-	ioa.app.DataRenderer().Remove()
+	ioa.render = false
+	if !ioa.held {
+		ioa.app.DataRenderer().Remove()
+	}
 
 	if len(names) > 0 {
 		for _, name := range names {
@@ -219,11 +264,18 @@ func (ioa *IOActivity) Settings(interval uint64, textPosition cdtype.InfoPositio
 			ioa.info = NewTextNil()
 		}
 
+		size := 2 * len(ioa.list)
+		ioa.render = true
 		switch renderer {
 		case 0:
-			ioa.app.DataRenderer().Gauge(2*len(ioa.list), gaugeTheme)
+			ioa.renderer = func() { ioa.app.DataRenderer().Gauge(size, gaugeTheme) }
 		case 1:
-			ioa.app.DataRenderer().Graph(2*len(ioa.list), graphType)
+			ioa.renderer = func() { ioa.app.DataRenderer().Graph(size, graphType) }
+		default: // No renderer, or managed by the applet.
+			ioa.render = false
+		}
+		if ioa.render && !ioa.held {
+			ioa.renderer()
 		}
 	} else {
 		// log.DEV("no na ffs")
@@ -231,6 +283,20 @@ func (ioa *IOActivity) Settings(interval uint64, textPosition cdtype.InfoPositio
 	}
 }
 
+// HoldRenderer stops rendering values while the applet uses the data renderer
+// for something else, like a progress bar. The configured renderer is set back
+// when released.
+//
+// IOActivity isn't safe for concurrent use: when the hold is set from another
+// goroutine, the applet must serialize it with Check and Settings.
+//
+func (ioa *IOActivity) HoldRenderer(hold bool) {
+	ioa.held = hold
+	if !hold && ioa.render {
+		ioa.renderer()
+	}
+}
+
 //
 //-------------------------------------------------------------[ UPDATE DATA ]--
 
@@ -252,6 +318,9 @@ func (ioa *IOActivity) Check() {
 	for _, stat := range ioa.list {
 		if in, out, ok := stat.Current(); ok {
 			ioa.info.Append(stat.name, stat.rateReadNow, stat.rateWriteNow)
+			ioa.Alert.Check(stat.name, float64(stat.rateReadNow+stat.rateWriteNow))
+			ioa.Record.Set(stat.name+" in", float64(stat.rateReadNow))
+			ioa.Record.Set(stat.name+" out", float64(stat.rateWriteNow))
 			values = append(values, in, out)
 		} else {
 			ioa.info.Fail(stat.name)
@@ -261,9 +330,10 @@ func (ioa *IOActivity) Check() {
 
 	ioa.info.Display()
 
-	if len(values) > 0 {
+	if ioa.render && !ioa.held && len(values) > 0 {
 		ioa.app.DataRenderer().Render(values...)
 	}
+	ioa.Log.Err(ioa.Record.Commit(), "record metrics")
 }
 
 // Get new data from source.
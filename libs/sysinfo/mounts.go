@@ -0,0 +1,129 @@
+package sysinfo
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// KernelMountInfo is the mounted filesystems information source.
+const KernelMountInfo = "/proc/self/mountinfo"
+
+// mountEventDelay is the minimum delay between mount change callbacks.
+const mountEventDelay = time.Second
+
+//
+//-----------------------------------------------------------[ MOUNT WATCHER ]--
+
+// MountWatcher calls a function when filesystems are mounted or unmounted.
+//
+// The kernel flags the mountinfo file as having priority data on changes.
+//
+type MountWatcher struct {
+	file   *os.File
+	epfd   int
+	stop   [2]int        // Pipe used to stop the watch loop.
+	done   chan struct{} // Closed when the watch loop has ended.
+	once   sync.Once     // Close is only done once.
+	change func()
+}
+
+// WatchMounts starts to watch mounts changes. The callback is called in the
+// watch goroutine.
+//
+func WatchMounts(onChange func()) (*MountWatcher, error) {
+	file, e := os.Open(KernelMountInfo)
+	if e != nil {
+		return nil, e
+	}
+	mw := &MountWatcher{file: file, change: onChange, done: make(chan struct{})}
+
+	mw.epfd, e = syscall.EpollCreate1(syscall.EPOLL_CLOEXEC)
+	if e != nil {
+		file.Close()
+		return nil, e
+	}
+	e = syscall.Pipe2(mw.stop[:], syscall.O_CLOEXEC)
+	if e != nil {
+		syscall.Close(mw.epfd)
+		file.Close()
+		return nil, e
+	}
+
+	for fd, events := range map[int]uint32{
+		int(file.Fd()): syscall.EPOLLPRI | syscall.EPOLLERR,
+		mw.stop[0]:     syscall.EPOLLIN,
+	} {
+		e = syscall.EpollCtl(mw.epfd, syscall.EPOLL_CTL_ADD, fd, &syscall.EpollEvent{Events: events, Fd: int32(fd)})
+		if e != nil {
+			mw.closeAll()
+			return nil, e
+		}
+	}
+
+	io.Copy(ioutil.Discard, file) // Read the file once to arm the change event.
+	go mw.loop()
+	return mw, nil
+}
+
+// Close stops watching mounts, and waits for the watch loop to end before
+// releasing the files. Safe to use on a nil watcher, or more than once.
+//
+func (mw *MountWatcher) Close() (e error) {
+	if mw == nil {
+		return nil
+	}
+	mw.once.Do(func() {
+		select {
+		case <-mw.done: // Loop already ended on error.
+		default:
+			_, e = syscall.Write(mw.stop[1], []byte{0})
+			<-mw.done
+		}
+		mw.closeAll()
+	})
+	return e
+}
+
+// loop waits for mount events until stopped.
+//
+func (mw *MountWatcher) loop() {
+	defer close(mw.done)
+
+	events := make([]syscall.EpollEvent, 2)
+	for {
+		n, e := syscall.EpollWait(mw.epfd, events, -1)
+		switch {
+		case e == syscall.EINTR:
+			continue
+
+		case e != nil:
+			return
+		}
+
+		changed := false
+		for _, ev := range events[:n] {
+			if int(ev.Fd) == mw.stop[0] {
+				return
+			}
+			changed = true
+		}
+
+		if changed {
+			mw.file.Seek(0, io.SeekStart)
+			io.Copy(ioutil.Discard, mw.file) // Rearm the change event.
+			mw.change()
+			time.Sleep(mountEventDelay) // Mounts often come in bursts.
+		}
+	}
+}
+
+func (mw *MountWatcher) closeAll() {
+	syscall.Close(mw.epfd)
+	syscall.Close(mw.stop[0])
+	syscall.Close(mw.stop[1])
+	mw.file.Close()
+}
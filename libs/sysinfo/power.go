@@ -0,0 +1,180 @@
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+)
+
+// SysPowerSupplyDir is the power supply information source directory.
+const SysPowerSupplyDir = "/sys/class/power_supply"
+
+// Power supply types.
+const (
+	PowerTypeBattery = "Battery"
+	PowerTypeMains   = "Mains"
+)
+
+// Power supply status.
+const (
+	PowerCharging    = "Charging"
+	PowerDischarging = "Discharging"
+	PowerFull        = "Full"
+	PowerNotCharging = "Not charging"
+	PowerUnknown     = "Unknown"
+)
+
+// PowerSupply defines the state of a battery or an AC adapter.
+//
+// Energy values are in µWh and power in µW. Batteries only providing charge
+// values (µAh and µA) are handled the same way, as only ratios are computed,
+// and a power or current value is converted to match the energy unit.
+//
+type PowerSupply struct {
+	Name     string
+	Type     string // PowerTypeBattery or PowerTypeMains (or other kernel types).
+	Status   string // Charging status for batteries.
+	Online   bool   // Connected, for AC adapters.
+	Capacity float64
+
+	EnergyNow  float64
+	EnergyFull float64
+	PowerNow   float64
+}
+
+// PowerSupplies returns the list of power supplies found in the given sysfs
+// class directory (empty for the default SysPowerSupplyDir).
+//
+func PowerSupplies(root string) ([]*PowerSupply, error) {
+	if root == "" {
+		root = SysPowerSupplyDir
+	}
+	dirs, e := ioutil.ReadDir(root)
+	if e != nil {
+		return nil, e
+	}
+
+	var list []*PowerSupply
+	for _, fi := range dirs {
+		ps, e := ReadPowerSupply(filepath.Join(root, fi.Name()))
+		if e == nil {
+			list = append(list, ps)
+		}
+	}
+	return list, nil
+}
+
+// ReadPowerSupply reads power supply information from its sysfs directory.
+//
+// Using Linux power supply class : https://www.kernel.org/doc/Documentation/power/power_supply_class.txt
+//
+func ReadPowerSupply(dir string) (*PowerSupply, error) {
+	ps := &PowerSupply{
+		Name: filepath.Base(dir),
+		Type: readSysString(filepath.Join(dir, "type")),
+	}
+	if ps.Type == "" {
+		return nil, errors.New("power supply: no type found in " + dir)
+	}
+
+	if online, e := readSysValue(filepath.Join(dir, "online")); e == nil {
+		ps.Online = online > 0
+	}
+	if ps.Type != PowerTypeBattery {
+		return ps, nil
+	}
+
+	ps.Status = readSysString(filepath.Join(dir, "status"))
+	ps.readEnergy(dir)
+	if ps.PowerNow < 0 { // Some drivers report a negative current when discharging.
+		ps.PowerNow = -ps.PowerNow
+	}
+
+	capacity, e := readSysValue(filepath.Join(dir, "capacity"))
+	switch {
+	case e == nil:
+		ps.Capacity = capacity
+
+	case ps.EnergyFull > 0:
+		ps.Capacity = ps.EnergyNow / ps.EnergyFull * 100
+	}
+	return ps, nil
+}
+
+// readEnergy reads the energy and power values, or the charge and current
+// values if the battery has no energy files. Units are never mixed: when the
+// power (or current) matching the energy unit is missing, it's converted from
+// the other one with the voltage.
+//
+func (ps *PowerSupply) readEnergy(dir string) {
+	voltage := readSysFirst(dir, "voltage_now") / 1e6 // µV to V.
+	if _, e := readSysValue(filepath.Join(dir, "energy_now")); e == nil {
+		ps.EnergyNow = readSysFirst(dir, "energy_now")
+		ps.EnergyFull = readSysFirst(dir, "energy_full")
+		ps.PowerNow = readSysFirst(dir, "power_now")
+		if ps.PowerNow == 0 {
+			ps.PowerNow = readSysFirst(dir, "current_now") * voltage
+		}
+		return
+	}
+
+	ps.EnergyNow = readSysFirst(dir, "charge_now")
+	ps.EnergyFull = readSysFirst(dir, "charge_full")
+	ps.PowerNow = readSysFirst(dir, "current_now")
+	if ps.PowerNow == 0 && voltage > 0 {
+		ps.PowerNow = readSysFirst(dir, "power_now") / voltage
+	}
+}
+
+// IsBattery returns whether the power supply is a battery.
+//
+func (ps *PowerSupply) IsBattery() bool {
+	return ps.Type == PowerTypeBattery
+}
+
+// TimeLeft returns the estimated time to empty when discharging, or to full
+// when charging. Returns 0 when it can't be computed.
+//
+func (ps *PowerSupply) TimeLeft() time.Duration {
+	if ps.PowerNow <= 0 {
+		return 0
+	}
+	var hours float64
+	switch ps.Status {
+	case PowerDischarging:
+		hours = ps.EnergyNow / ps.PowerNow
+
+	case PowerCharging:
+		if ps.EnergyFull <= ps.EnergyNow {
+			return 0
+		}
+		hours = (ps.EnergyFull - ps.EnergyNow) / ps.PowerNow
+
+	default:
+		return 0
+	}
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// FormatTimeLeft formats a time left as hours:minutes, or nothing if 0.
+//
+func FormatTimeLeft(dur time.Duration) string {
+	if dur <= 0 {
+		return ""
+	}
+	minutes := int(dur.Minutes())
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// readSysFirst returns the value of the first readable file in dir.
+//
+func readSysFirst(dir string, files ...string) float64 {
+	for _, file := range files {
+		if value, e := readSysValue(filepath.Join(dir, file)); e == nil {
+			return value
+		}
+	}
+	return 0
+}
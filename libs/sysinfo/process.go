@@ -0,0 +1,302 @@
+package sysinfo
+
+import (
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
+	"github.com/sqp/godock/libs/text/bytesize"
+
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// clockTicks is the kernel USER_HZ value used in /proc/<pid>/stat counters.
+// It is fixed to 100 on Linux, whatever the real kernel frequency.
+const clockTicks = 100
+
+const ( // Position of our data in /proc/<pid>/stat, after the command name.
+	statUTime = 11
+	statSTime = 12
+	statNice  = 16
+	statRSS   = 21
+)
+
+//
+//-----------------------------------------------------------------[ PROCESS ]--
+
+// Process defines the resources used by a running process.
+//
+type Process struct {
+	PID   int
+	Name  string
+	Nice  int
+	Ticks uint64  // CPU time used (user+system), in clock ticks.
+	RSS   uint64  // Resident memory, in bytes.
+	CPU   float64 // CPU usage during the last measure, in percent of one core.
+
+	IORead    uint64  // Bytes read from storage, set by ReadIO.
+	IOWrite   uint64  // Bytes written to storage, set by ReadIO.
+	ReadRate  float64 // Storage read rate during the last measure, in bytes per second.
+	WriteRate float64 // Storage write rate during the last measure, in bytes per second.
+}
+
+// ReadProcess gets resources information for a given PID from /proc.
+//
+func ReadProcess(pid int) (*Process, error) {
+	data, e := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if e != nil {
+		return nil, e
+	}
+
+	// The command name is between parenthesis and can contain spaces.
+	line := string(data)
+	start := strings.IndexByte(line, '(')
+	end := strings.LastIndexByte(line, ')')
+	if start < 0 || end < start {
+		return nil, errors.New("process stat: failed parsing")
+	}
+
+	fields := strings.Fields(line[end+1:])
+	if len(fields) <= statRSS {
+		return nil, errors.New("process stat: failed parsing")
+	}
+
+	utime, _ := strconv.ParseUint(fields[statUTime], 10, 64)
+	stime, _ := strconv.ParseUint(fields[statSTime], 10, 64)
+	nice, _ := strconv.Atoi(fields[statNice])
+	rss, _ := strconv.ParseUint(fields[statRSS], 10, 64)
+
+	return &Process{
+		PID:   pid,
+		Name:  line[start+1 : end],
+		Nice:  nice,
+		Ticks: utime + stime,
+		RSS:   rss * uint64(os.Getpagesize()),
+	}, nil
+}
+
+// ReadIO reads the process storage I/O counters from /proc.
+// Only processes of the current user can be read, unless running as root.
+//
+func (proc *Process) ReadIO() error {
+	data, e := ioutil.ReadFile(fmt.Sprintf("/proc/%d/io", proc.PID))
+	if e != nil {
+		return e
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "read_bytes:":
+			proc.IORead, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "write_bytes:":
+			proc.IOWrite, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return nil
+}
+
+// Processes returns the list of running processes.
+// Processes that disappeared while reading are ignored.
+//
+func Processes() ([]*Process, error) {
+	dir, e := ioutil.ReadDir("/proc")
+	if e != nil {
+		return nil, e
+	}
+
+	var list []*Process
+	for _, fi := range dir {
+		pid, e := strconv.Atoi(fi.Name())
+		if e != nil || !fi.IsDir() {
+			continue
+		}
+		if proc, e := ReadProcess(pid); e == nil {
+			list = append(list, proc)
+		}
+	}
+	return list, nil
+}
+
+// TopMemory returns the max processes using the most resident memory.
+//
+func TopMemory(max int) ([]*Process, error) {
+	list, e := Processes()
+	if e != nil {
+		return nil, e
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].RSS > list[j].RSS })
+	return trimProcesses(list, max), nil
+}
+
+// TopCPU returns the max processes using the most CPU during the given delay.
+// This call is blocking for the duration of the delay.
+//
+func TopCPU(max int, delay time.Duration) ([]*Process, error) {
+	before, e := Processes()
+	if e != nil {
+		return nil, e
+	}
+	time.Sleep(delay)
+	after, e := Processes()
+	if e != nil {
+		return nil, e
+	}
+
+	ticks := make(map[int]uint64, len(before))
+	for _, proc := range before {
+		ticks[proc.PID] = proc.Ticks
+	}
+
+	for _, proc := range after {
+		if last, ok := ticks[proc.PID]; ok && proc.Ticks >= last {
+			proc.CPU = float64(proc.Ticks-last) / clockTicks / delay.Seconds() * 100
+		}
+	}
+	sort.Slice(after, func(i, j int) bool { return after[i].CPU > after[j].CPU })
+	return trimProcesses(after, max), nil
+}
+
+// TopIO returns the max processes using the most storage I/O (read + write)
+// during the given delay. Processes with unreadable counters are ignored.
+// This call is blocking for the duration of the delay.
+//
+func TopIO(max int, delay time.Duration) ([]*Process, error) {
+	before, e := processesIO()
+	if e != nil {
+		return nil, e
+	}
+	time.Sleep(delay)
+	after, e := processesIO()
+	if e != nil {
+		return nil, e
+	}
+
+	last := make(map[int]*Process, len(before))
+	for _, proc := range before {
+		last[proc.PID] = proc
+	}
+
+	var list []*Process
+	for _, proc := range after {
+		prev, ok := last[proc.PID]
+		if !ok || proc.IORead < prev.IORead || proc.IOWrite < prev.IOWrite {
+			continue
+		}
+		proc.ReadRate = float64(proc.IORead-prev.IORead) / delay.Seconds()
+		proc.WriteRate = float64(proc.IOWrite-prev.IOWrite) / delay.Seconds()
+		if proc.ReadRate+proc.WriteRate > 0 {
+			list = append(list, proc)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ReadRate+list[i].WriteRate > list[j].ReadRate+list[j].WriteRate
+	})
+	return trimProcesses(list, max), nil
+}
+
+// processesIO returns the list of processes with readable I/O counters.
+//
+func processesIO() ([]*Process, error) {
+	all, e := Processes()
+	if e != nil {
+		return nil, e
+	}
+	var list []*Process
+	for _, proc := range all {
+		if proc.ReadIO() == nil {
+			list = append(list, proc)
+		}
+	}
+	return list, nil
+}
+
+// Kill sends a termination signal to the process.
+//
+func (proc *Process) Kill() error {
+	return syscall.Kill(proc.PID, syscall.SIGTERM)
+}
+
+// Renice changes the process priority by the given delta.
+//
+func (proc *Process) Renice(delta int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, proc.PID, proc.Nice+delta)
+}
+
+// FormatCPU is a process text formatter showing the CPU usage.
+//
+func FormatCPU(proc *Process) string {
+	return fmt.Sprintf("%s (%d): %s", proc.Name, proc.PID, FormatPercent(proc.CPU))
+}
+
+// FormatMemory is a process text formatter showing the resident memory.
+//
+func FormatMemory(proc *Process) string {
+	return fmt.Sprintf("%s (%d): %s", proc.Name, proc.PID, bytesize.ByteSize(proc.RSS))
+}
+
+// FormatDiskIO is a process text formatter showing the storage read and write rates.
+//
+func FormatDiskIO(proc *Process) string {
+	return fmt.Sprintf("%s (%d): r %s/s / w %s/s", proc.Name, proc.PID,
+		bytesize.ByteSize(proc.ReadRate), bytesize.ByteSize(proc.WriteRate))
+}
+
+func trimProcesses(list []*Process, max int) []*Process {
+	if max > 0 && len(list) > max {
+		return list[:max]
+	}
+	return list
+}
+
+//
+//--------------------------------------------------------------[ TOP DIALOG ]--
+
+// Process dialog buttons.
+const (
+	processButtonKill = iota
+	processButtonRenice
+)
+
+// reniceDelta is the priority change applied by the renice button.
+const reniceDelta = 5
+
+// PopupProcesses shows a dialog with the list of processes.
+// The user can kill or lower the priority of the selected process.
+//
+func PopupProcesses(app cdtype.AppBase, title string, list []*Process, format func(*Process) string) error {
+	if len(list) == 0 {
+		return errors.New("no process found")
+	}
+
+	var values []string
+	for _, proc := range list {
+		values = append(values, format(proc))
+	}
+
+	return app.PopupDialog(cdtype.DialogData{
+		Message: title,
+		Widget:  cdtype.DialogWidgetList{Values: values},
+		Buttons: "process-stop;go-down;cancel",
+		Callback: func(button int, data interface{}) {
+			id, ok := data.(int)
+			if !ok || id < 0 || id >= len(list) {
+				return
+			}
+			switch button {
+			case processButtonKill:
+				app.Log().Err(list[id].Kill(), "kill process", list[id].Name)
+
+			case processButtonRenice:
+				app.Log().Err(list[id].Renice(reniceDelta), "renice process", list[id].Name)
+			}
+		},
+	})
+}
@@ -0,0 +1,230 @@
+package sysinfo
+
+import (
+	"github.com/sqp/godock/libs/cdglobal"      // Global consts.
+	"github.com/sqp/godock/libs/files/history" // History file management.
+
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// RecordFilePrefix is the name prefix of metrics history files, in the user appdata dir.
+const RecordFilePrefix = "metrics-"
+
+// RecordFileName returns the metrics history file name for an applet.
+//
+func RecordFileName(appletName string) string {
+	return RecordFilePrefix + appletName + ".json"
+}
+
+// RecordFilePath returns the metrics history file location for an applet, in
+// the given dock config dir (empty for the default one).
+//
+func RecordFilePath(configDir, appletName string) string {
+	return filepath.Join(cdglobal.ConfigDirDock(configDir), cdglobal.DirUserAppData, RecordFileName(appletName))
+}
+
+//
+//------------------------------------------------------------------[ SAMPLE ]--
+
+// Sample defines a set of named values recorded at the same time.
+//
+type Sample struct {
+	Date   time.Time          `json:"date"`
+	Values map[string]float64 `json:"values"`
+}
+
+//
+//----------------------------------------------------------------[ RECORDER ]--
+
+// Recorder saves values samples in a ring buffer file.
+//
+// Samples are appended to the file, one JSON object per line. The file is
+// rewritten when it grows over the history size, to drop older samples.
+//
+// A nil Recorder can be used safely and will do nothing.
+//
+type Recorder struct {
+	history.History
+	List []Sample
+
+	current map[string]float64 // Sample being built.
+}
+
+// NewRecorder creates a metrics recorder for the applet.
+//
+func NewRecorder(app history.AppletLike, appletName string) *Recorder {
+	rec := &Recorder{History: *history.New(app, RecordFileName(appletName))}
+	rec.SetFuncs(rec.load, rec.save, rec.trim)
+	rec.Load()
+	return rec
+}
+
+// UpdateRecorder returns a recorder matching the history size setting.
+// The recorder is created if needed, or nil is returned when size is 0.
+//
+func UpdateRecorder(rec *Recorder, app history.AppletLike, appletName string, size int) *Recorder {
+	if size == 0 {
+		return nil
+	}
+	if rec == nil {
+		rec = NewRecorder(app, appletName)
+	}
+	rec.SetHistorySize(size)
+	return rec
+}
+
+// Set sets a value in the current sample.
+//
+func (rec *Recorder) Set(name string, value float64) {
+	if rec == nil || rec.Max == 0 {
+		return
+	}
+	if rec.current == nil {
+		rec.current = make(map[string]float64)
+	}
+	rec.current[name] = value
+}
+
+// Commit records the current sample, if any.
+//
+func (rec *Recorder) Commit() error {
+	if rec == nil || len(rec.current) == 0 {
+		return nil
+	}
+	sample := Sample{Date: time.Now(), Values: rec.current}
+	rec.current = nil
+	rec.List = append(rec.List, sample)
+
+	// Rewrite the file only when it's a quarter over the limit.
+	if rec.Max > 0 && len(rec.List) > rec.Max+rec.Max/4 {
+		rec.trim()
+		return rec.Save()
+	}
+	return rec.appendFile(sample)
+}
+
+// appendFile appends a single sample to the history file.
+//
+func (rec *Recorder) appendFile(sample Sample) error {
+	if _, e := os.Stat(rec.File); e != nil { // Create the file and its dir.
+		return rec.Save()
+	}
+	f, e := os.OpenFile(rec.File, os.O_WRONLY|os.O_APPEND, cdglobal.FileMode)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+	return json.NewEncoder(f).Encode(sample)
+}
+
+func (rec *Recorder) load() error {
+	f, e := os.Open(rec.File)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+
+	rec.List, e = ReadSamples(f)
+	return e
+}
+
+func (rec *Recorder) save() error {
+	f, e := os.OpenFile(rec.File, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cdglobal.FileMode)
+	if e != nil {
+		return e
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, sample := range rec.List {
+		if e = enc.Encode(sample); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func (rec *Recorder) trim() {
+	switch rec.Max {
+	case -1:
+		return
+	case 0:
+		rec.List = nil
+	default:
+		if len(rec.List) > rec.Max {
+			rec.List = rec.List[len(rec.List)-rec.Max:]
+		}
+	}
+}
+
+//
+//------------------------------------------------------------------[ EXPORT ]--
+
+// ReadSamples reads samples saved by a Recorder.
+// Invalid lines are dropped, only the last parsing error is returned.
+//
+func ReadSamples(r io.Reader) (list []Sample, e error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			e = err
+			continue
+		}
+		list = append(list, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return list, err
+	}
+	return list, e
+}
+
+// WriteJSON writes the samples as a JSON array.
+//
+func WriteJSON(w io.Writer, list []Sample) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(list)
+}
+
+// WriteCSV writes the samples as CSV, with one column for each value name.
+// Missing values are left empty.
+//
+func WriteCSV(w io.Writer, list []Sample) error {
+	names := make(map[string]bool)
+	for _, sample := range list {
+		for name := range sample.Values {
+			names[name] = true
+		}
+	}
+	var cols []string
+	for name := range names {
+		cols = append(cols, name)
+	}
+	sort.Strings(cols)
+
+	out := csv.NewWriter(w)
+	out.Write(append([]string{"date"}, cols...))
+	for _, sample := range list {
+		line := []string{sample.Date.Format(time.RFC3339)}
+		for _, name := range cols {
+			value, ok := sample.Values[name]
+			if ok {
+				line = append(line, strconv.FormatFloat(value, 'f', -1, 64))
+			} else {
+				line = append(line, "")
+			}
+		}
+		out.Write(line)
+	}
+	out.Flush()
+	return out.Error()
+}
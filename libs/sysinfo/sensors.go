@@ -0,0 +1,171 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sensors information sources.
+const (
+	SysHwmonDir   = "/sys/class/hwmon"
+	SysThermalDir = "/sys/class/thermal"
+)
+
+// SensorType defines the kind of value provided by a sensor.
+//
+type SensorType int
+
+// Sensor types.
+const (
+	SensorTemp SensorType = iota // Temperature, in °C.
+	SensorFan                    // Fan speed, in RPM.
+)
+
+// Sensor defines a temperature or fan sensor found in sysfs.
+//
+type Sensor struct {
+	Name  string     // Unique name: chip/label for hwmon, zone/type for thermal zones.
+	Type  SensorType //
+	Input string     // Path of the value file.
+	Crit  float64    // Critical trip value, in the sensor unit. 0 if not provided.
+}
+
+// Read returns the current sensor value, in the sensor unit.
+//
+func (sensor *Sensor) Read() (float64, error) {
+	value, e := readSysValue(sensor.Input)
+	if e != nil {
+		return 0, e
+	}
+	if sensor.Type == SensorTemp {
+		return value / 1000, nil // Millidegree to degree.
+	}
+	return value, nil
+}
+
+// Format returns the value as text, with the sensor unit.
+//
+func (sensor *Sensor) Format(value float64) string {
+	if sensor.Type == SensorTemp {
+		return fmt.Sprintf("%.f°C", value)
+	}
+	return fmt.Sprintf("%.f rpm", value)
+}
+
+// Sensors returns the list of temperature and fan sensors found on the system.
+//
+func Sensors() []*Sensor {
+	return FindSensors(SysHwmonDir, SysThermalDir)
+}
+
+// FindSensors returns the list of sensors found in the given hwmon and thermal
+// class directories.
+//
+// Duplicate names (like two identical chips) get a numbered suffix.
+//
+func FindSensors(hwmonDir, thermalDir string) (list []*Sensor) {
+	list = append(list, findHwmon(hwmonDir)...)
+	list = append(list, findThermal(thermalDir)...)
+
+	names := make(map[string]int)
+	for _, sensor := range list {
+		names[sensor.Name]++
+		if count := names[sensor.Name]; count > 1 {
+			sensor.Name += " #" + strconv.Itoa(count)
+		}
+	}
+	return list
+}
+
+// findHwmon finds hwmon temperature and fan inputs.
+//
+// Using Linux hwmon : https://www.kernel.org/doc/Documentation/hwmon/sysfs-interface
+//
+func findHwmon(dir string) (list []*Sensor) {
+	chips, _ := filepath.Glob(filepath.Join(dir, "hwmon*"))
+	sort.Strings(chips)
+	for _, chip := range chips {
+		chipName := readSysString(filepath.Join(chip, "name"))
+		if chipName == "" {
+			chipName = filepath.Base(chip)
+		}
+
+		for _, kind := range []struct {
+			prefix string
+			typ    SensorType
+		}{
+			{"temp", SensorTemp},
+			{"fan", SensorFan},
+		} {
+			inputs, _ := filepath.Glob(filepath.Join(chip, kind.prefix+"*_input"))
+			sort.Strings(inputs)
+			for _, input := range inputs {
+				base := strings.TrimSuffix(input, "_input")
+				label := readSysString(base + "_label")
+				if label == "" {
+					label = filepath.Base(base)
+				}
+
+				sensor := &Sensor{
+					Name:  chipName + "/" + label,
+					Type:  kind.typ,
+					Input: input,
+				}
+				if kind.typ == SensorTemp {
+					if crit, e := readSysValue(base + "_crit"); e == nil {
+						sensor.Crit = crit / 1000
+					}
+				}
+				list = append(list, sensor)
+			}
+		}
+	}
+	return list
+}
+
+// findThermal finds thermal zones temperatures, with their critical trip point.
+//
+func findThermal(dir string) (list []*Sensor) {
+	zones, _ := filepath.Glob(filepath.Join(dir, "thermal_zone*"))
+	sort.Strings(zones)
+	for _, zone := range zones {
+		sensor := &Sensor{
+			Name:  filepath.Base(zone) + "/" + readSysString(filepath.Join(zone, "type")),
+			Type:  SensorTemp,
+			Input: filepath.Join(zone, "temp"),
+		}
+
+		trips, _ := filepath.Glob(filepath.Join(zone, "trip_point_*_type"))
+		for _, trip := range trips {
+			if readSysString(trip) != "critical" {
+				continue
+			}
+			if crit, e := readSysValue(strings.TrimSuffix(trip, "_type") + "_temp"); e == nil {
+				sensor.Crit = crit / 1000
+			}
+		}
+		list = append(list, sensor)
+	}
+	return list
+}
+
+// readSysString returns the trimmed content of a sysfs file, or an empty string.
+//
+func readSysString(file string) string {
+	data, _ := ioutil.ReadFile(file)
+	return strings.TrimSpace(string(data))
+}
+
+// readSysValue returns the number in a sysfs file.
+//
+func readSysValue(file string) (float64, error) {
+	data, e := ioutil.ReadFile(file)
+	if e != nil {
+		return 0, e
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+}
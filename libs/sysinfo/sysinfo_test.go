@@ -6,8 +6,12 @@ import (
 	"github.com/sqp/godock/libs/log"
 	"github.com/sqp/godock/libs/sysinfo"
 
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 var logger = log.NewLog(log.Logs)
@@ -25,3 +29,216 @@ func TestSystem(t *testing.T) {
 	assert.NoError(t, e, "sysinfo.ProcessMemory")
 	assert.True(t, mem > 1000, "sysinfo.ProcessMemory")
 }
+
+func TestProcesses(t *testing.T) {
+	proc, e := sysinfo.ReadProcess(os.Getpid())
+	assert.NoError(t, e, "sysinfo.ReadProcess")
+	assert.NotEmpty(t, proc.Name, "Name")
+	assert.True(t, proc.RSS > 0, "RSS")
+
+	list, e := sysinfo.TopMemory(5)
+	assert.NoError(t, e, "sysinfo.TopMemory")
+	assert.Len(t, list, 5, "sysinfo.TopMemory")
+	assert.True(t, list[0].RSS >= list[4].RSS, "sorted")
+}
+
+type testApplet string
+
+func (dir testApplet) FileDataDir(path ...string) string {
+	return filepath.Join(append([]string{string(dir)}, path...)...)
+}
+
+func TestRecorder(t *testing.T) {
+	dir, e := ioutil.TempDir("", "sysinfo")
+	assert.NoError(t, e, "TempDir")
+	defer os.RemoveAll(dir)
+
+	rec := sysinfo.UpdateRecorder(nil, testApplet(dir), "test", 4)
+	for i := 0; i < 6; i++ {
+		rec.Set("value", float64(i))
+		assert.NoError(t, rec.Commit(), "Commit")
+	}
+	assert.Len(t, rec.List, 4, "trimmed")
+
+	reload := sysinfo.NewRecorder(testApplet(dir), "test")
+	assert.Len(t, reload.List, 4, "reloaded")
+	assert.Equal(t, 5., reload.List[3].Values["value"], "last value")
+
+	assert.Nil(t, sysinfo.UpdateRecorder(rec, testApplet(dir), "test", 0), "disabled")
+}
+
+func TestPressure(t *testing.T) {
+	psi, e := sysinfo.ParsePressure(strings.NewReader(
+		"some avg10=3.47 avg60=2.52 avg300=2.25 total=43551021\n" +
+			"full avg10=1.20 avg60=0.50 avg300=0.10 total=1200\n"))
+	assert.NoError(t, e, "ParsePressure")
+	assert.Equal(t, 3.47, psi.Some.Avg10, "some avg10")
+	assert.Equal(t, 0.5, psi.Full.Avg60, "full avg60")
+	assert.Equal(t, uint64(43551021), psi.Some.Total, "some total")
+	assert.True(t, psi.HasFull, "HasFull")
+
+	psi, e = sysinfo.ParsePressure(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"))
+	assert.NoError(t, e, "ParsePressure some only")
+	assert.False(t, psi.HasFull, "HasFull")
+
+	_, e = sysinfo.ParsePressure(strings.NewReader(""))
+	assert.Error(t, e, "ParsePressure empty")
+}
+
+func TestSensors(t *testing.T) {
+	dir, e := ioutil.TempDir("", "sysinfo")
+	assert.NoError(t, e, "TempDir")
+	defer os.RemoveAll(dir)
+
+	for file, data := range map[string]string{
+		"hwmon/hwmon0/name":                       "coretemp",
+		"hwmon/hwmon0/temp1_input":                "45000",
+		"hwmon/hwmon0/temp1_label":                "Core 0",
+		"hwmon/hwmon0/temp1_crit":                 "100000",
+		"hwmon/hwmon1/name":                       "coretemp",
+		"hwmon/hwmon1/temp1_input":                "47000",
+		"hwmon/hwmon1/fan1_input":                 "1200",
+		"thermal/thermal_zone0/type":              "acpitz",
+		"thermal/thermal_zone0/temp":              "52000",
+		"thermal/thermal_zone0/trip_point_0_type": "critical",
+		"thermal/thermal_zone0/trip_point_0_temp": "105000",
+	} {
+		path := filepath.Join(dir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "MkdirAll")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(data+"\n"), 0644), "WriteFile")
+	}
+
+	list := sysinfo.FindSensors(filepath.Join(dir, "hwmon"), filepath.Join(dir, "thermal"))
+	if !assert.Len(t, list, 4, "FindSensors") {
+		return
+	}
+	assert.Equal(t, "coretemp/Core 0", list[0].Name, "label")
+	assert.Equal(t, 100., list[0].Crit, "hwmon crit")
+	assert.Equal(t, "coretemp/temp1", list[1].Name, "no label")
+	assert.Equal(t, "coretemp/fan1", list[2].Name, "fan")
+	assert.Equal(t, sysinfo.SensorFan, list[2].Type, "fan type")
+	assert.Equal(t, "thermal_zone0/acpitz", list[3].Name, "thermal")
+	assert.Equal(t, 105., list[3].Crit, "thermal crit")
+
+	value, e := list[0].Read()
+	assert.NoError(t, e, "Read")
+	assert.Equal(t, 45., value, "Read")
+}
+
+func TestPowerSupplies(t *testing.T) {
+	dir, e := ioutil.TempDir("", "sysinfo")
+	assert.NoError(t, e, "TempDir")
+	defer os.RemoveAll(dir)
+
+	for file, data := range map[string]string{
+		"AC/type":          "Mains",
+		"AC/online":        "0",
+		"BAT0/type":        "Battery",
+		"BAT0/status":      "Discharging",
+		"BAT0/capacity":    "40",
+		"BAT0/energy_now":  "20000000",
+		"BAT0/energy_full": "50000000",
+		"BAT0/power_now":   "10000000",
+		"BAT1/type":        "Battery",
+		"BAT1/status":      "Charging",
+		"BAT1/charge_now":  "1000000",
+		"BAT1/charge_full": "4000000",
+		"BAT1/current_now": "1500000",
+		"BAT2/type":        "Battery",
+		"BAT2/status":      "Discharging",
+		"BAT2/energy_now":  "24000000",
+		"BAT2/energy_full": "48000000",
+		"BAT2/current_now": "1000000",
+		"BAT2/voltage_now": "12000000",
+	} {
+		path := filepath.Join(dir, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "MkdirAll")
+		assert.NoError(t, ioutil.WriteFile(path, []byte(data+"\n"), 0644), "WriteFile")
+	}
+
+	list, e := sysinfo.PowerSupplies(dir)
+	assert.NoError(t, e, "PowerSupplies")
+	if !assert.Len(t, list, 4, "PowerSupplies") {
+		return
+	}
+	assert.False(t, list[0].IsBattery(), "AC")
+
+	assert.Equal(t, 40., list[1].Capacity, "capacity")
+	assert.Equal(t, 2*time.Hour, list[1].TimeLeft(), "time to empty")
+	assert.Equal(t, "2:00", sysinfo.FormatTimeLeft(list[1].TimeLeft()), "FormatTimeLeft")
+
+	assert.Equal(t, 25., list[2].Capacity, "capacity from charge")
+	assert.Equal(t, 2*time.Hour, list[2].TimeLeft(), "time to full")
+
+	assert.Equal(t, 12000000., list[3].PowerNow, "power from current and voltage")
+	assert.Equal(t, 2*time.Hour, list[3].TimeLeft(), "time to empty from current")
+}
+
+func TestWatchMounts(t *testing.T) {
+	mw, e := sysinfo.WatchMounts(func() {})
+	if !assert.NoError(t, e, "WatchMounts") {
+		return
+	}
+	assert.NoError(t, mw.Close(), "Close")
+	assert.NoError(t, mw.Close(), "Close twice")
+
+	var nilWatcher *sysinfo.MountWatcher
+	assert.NoError(t, nilWatcher.Close(), "Close nil")
+}
+
+func TestTraffic(t *testing.T) {
+	dir, e := ioutil.TempDir("", "sysinfo")
+	assert.NoError(t, e, "TempDir")
+	defer os.RemoveAll(dir)
+
+	day := time.Date(2020, 3, 31, 12, 0, 0, 0, time.Local)
+	tr := sysinfo.NewTraffic(testApplet(dir))
+	tr.Update([]sysinfo.Value{{Field: "eth0", In: 1000, Out: 100}}, day)
+	tr.Update([]sysinfo.Value{{Field: "eth0", In: 1500, Out: 200}}, day.Add(time.Hour))
+	assert.Equal(t, uint64(600), tr.Used(sysinfo.TrafficDay), "day")
+
+	tr.Update([]sysinfo.Value{{Field: "eth0", In: 1600, Out: 200}}, day.Add(24*time.Hour))
+	assert.Equal(t, uint64(100), tr.Used(sysinfo.TrafficDay, "eth0"), "day rollover")
+	assert.Equal(t, uint64(100), tr.Used(sysinfo.TrafficMonth, "eth0"), "month rollover")
+	assert.Equal(t, uint64(700), tr.Used(sysinfo.TrafficTotal, "eth0"), "total")
+
+	tr.Update([]sysinfo.Value{{Field: "eth0", In: 50, Out: 0}}, day.Add(25*time.Hour))
+	assert.Equal(t, uint64(750), tr.Used(sysinfo.TrafficTotal), "counters reset")
+
+	assert.NoError(t, tr.Save(), "Save")
+	reload := sysinfo.NewTraffic(testApplet(dir))
+	assert.Equal(t, uint64(750), reload.Used(sysinfo.TrafficTotal), "reloaded")
+}
+
+func TestForecast(t *testing.T) {
+	fc := sysinfo.NewForecast(4 * time.Hour)
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	add := func(hour, value int) { fc.Add(float64(value), start.Add(time.Duration(hour)*time.Hour)) }
+
+	add(0, 0)
+	add(1, 100)
+	_, ok := fc.Remaining(1000)
+	assert.False(t, ok, "not enough data")
+
+	add(2, 200) // 100 per hour.
+	dur, ok := fc.Remaining(1000)
+	assert.True(t, ok, "Remaining")
+	assert.Equal(t, 10*time.Hour, dur, "Remaining")
+	assert.Equal(t, "10h", sysinfo.FormatRemaining(dur), "FormatRemaining")
+	assert.Equal(t, "4 days", sysinfo.FormatRemaining(100*time.Hour), "FormatRemaining")
+
+	for hour := 3; hour < 10; hour++ {
+		add(hour, 200-(hour-2)*100) // Shrinking.
+	}
+	_, ok = fc.Remaining(1000)
+	assert.False(t, ok, "shrinking")
+}
+
+func TestCgroupIOStat(t *testing.T) {
+	read, write, e := sysinfo.ParseCgroupIOStat(strings.NewReader(
+		"8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353 dbytes=0 dios=0\n" +
+			"8:16 rbytes=100 wbytes=200 rios=1 wios=2 dbytes=0 dios=0\n"))
+	assert.NoError(t, e, "ParseCgroupIOStat")
+	assert.Equal(t, uint64(1459300), read, "read")
+	assert.Equal(t, uint64(314773704), write, "write")
+}
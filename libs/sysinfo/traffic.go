@@ -0,0 +1,239 @@
+package sysinfo
+
+import (
+	"github.com/sqp/godock/libs/cdglobal"      // Global consts.
+	"github.com/sqp/godock/libs/files/history" // History file management.
+
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrafficFileName is the name of the network traffic counters file, in the user appdata dir.
+const TrafficFileName = "net-traffic.json"
+
+// TrafficSaveDelay is the minimum delay between two saves of the traffic counters.
+const TrafficSaveDelay = time.Minute
+
+// bootTimeJitter is the boot time variation allowed, as it's computed by the
+// kernel and can change by a second between reads.
+const bootTimeJitter = 2
+
+// TrafficPeriod defines a traffic counter period.
+//
+type TrafficPeriod int
+
+// Traffic counter periods.
+const (
+	TrafficDay TrafficPeriod = iota
+	TrafficMonth
+	TrafficTotal
+)
+
+// TrafficCount defines the received and sent bytes count.
+//
+type TrafficCount struct {
+	In  uint64 `json:"in"`
+	Out uint64 `json:"out"`
+}
+
+// Sum returns the total bytes count, received and sent.
+//
+func (tc TrafficCount) Sum() uint64 {
+	return tc.In + tc.Out
+}
+
+// add adds bytes to the counter.
+//
+func (tc *TrafficCount) add(in, out uint64) {
+	tc.In += in
+	tc.Out += out
+}
+
+// TrafficDevice defines the cumulative traffic counters of a network interface.
+//
+type TrafficDevice struct {
+	Day   TrafficCount `json:"day"`
+	Month TrafficCount `json:"month"`
+	Total TrafficCount `json:"total"`
+
+	Date time.Time    `json:"date"` // Date of the last update, for periods rollover.
+	Last TrafficCount `json:"last"` // Last kernel counters read.
+}
+
+// Counter returns the counter for the given period.
+//
+func (dev *TrafficDevice) Counter(period TrafficPeriod) TrafficCount {
+	switch period {
+	case TrafficDay:
+		return dev.Day
+	case TrafficMonth:
+		return dev.Month
+	}
+	return dev.Total
+}
+
+// Traffic keeps cumulative network traffic counters for each interface, with
+// daily and monthly rollover. Counters are saved in a file to be kept across
+// reboots.
+//
+type Traffic struct {
+	history.History
+	trafficData
+	ResetDay int // Day of the month the monthly counters are reset (1 to 28).
+
+	lastSave time.Time
+}
+
+// trafficData defines the traffic counters saved to file.
+//
+type trafficData struct {
+	Devices map[string]*TrafficDevice `json:"devices"`
+	Boot    int64                     `json:"boot"` // Boot time of the last counters read, to detect reboots.
+}
+
+// NewTraffic creates a network traffic counter for the applet.
+//
+func NewTraffic(app history.AppletLike) *Traffic {
+	tr := &Traffic{
+		History:     *history.New(app, TrafficFileName),
+		trafficData: trafficData{Devices: make(map[string]*TrafficDevice)},
+		ResetDay:    1,
+	}
+	tr.SetFuncs(tr.load, tr.save, func() {})
+	tr.Load()
+	return tr
+}
+
+// Update adds the traffic since last update, from network activity values
+// (see GetNetActivity). Counters are saved at most every TrafficSaveDelay.
+//
+func (tr *Traffic) Update(values []Value, now time.Time) error {
+	boot := bootTime()
+	rebooted := boot-tr.Boot > bootTimeJitter || tr.Boot-boot > bootTimeJitter
+	tr.Boot = boot
+
+	for _, value := range values {
+		dev, ok := tr.Devices[value.Field]
+		if !ok {
+			dev = &TrafficDevice{Date: now, Last: TrafficCount{In: value.In, Out: value.Out}}
+			tr.Devices[value.Field] = dev
+			continue // Nothing to count on first read.
+		}
+
+		tr.rollover(dev, now)
+
+		in, out := value.In, value.Out
+		if !rebooted && in >= dev.Last.In && out >= dev.Last.Out {
+			in -= dev.Last.In
+			out -= dev.Last.Out
+		} // else kernel counters were reset: all the traffic is new.
+
+		dev.Day.add(in, out)
+		dev.Month.add(in, out)
+		dev.Total.add(in, out)
+		dev.Last = TrafficCount{In: value.In, Out: value.Out}
+		dev.Date = now
+	}
+
+	if now.Sub(tr.lastSave) < TrafficSaveDelay {
+		return nil
+	}
+	tr.lastSave = now
+	return tr.Save()
+}
+
+// Used returns the sum of traffic for the period on the given devices, or on
+// all devices if none is provided.
+//
+func (tr *Traffic) Used(period TrafficPeriod, devices ...string) (used uint64) {
+	if len(devices) == 0 {
+		for _, dev := range tr.Devices {
+			used += dev.Counter(period).Sum()
+		}
+		return used
+	}
+	for _, name := range devices {
+		if dev, ok := tr.Devices[name]; ok {
+			used += dev.Counter(period).Sum()
+		}
+	}
+	return used
+}
+
+// rollover resets the device day and month counters when a period has ended.
+//
+func (tr *Traffic) rollover(dev *TrafficDevice, now time.Time) {
+	if !sameDay(dev.Date, now) {
+		dev.Day = TrafficCount{}
+	}
+	if !monthStart(dev.Date, tr.ResetDay).Equal(monthStart(now, tr.ResetDay)) {
+		dev.Month = TrafficCount{}
+	}
+}
+
+func (tr *Traffic) load() error {
+	data, e := ioutil.ReadFile(tr.File)
+	if e != nil {
+		return e
+	}
+	e = json.Unmarshal(data, &tr.trafficData)
+	if tr.Devices == nil {
+		tr.Devices = make(map[string]*TrafficDevice)
+	}
+	return e
+}
+
+func (tr *Traffic) save() error {
+	data, e := json.Marshal(tr.trafficData)
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(tr.File, data, cdglobal.FileMode)
+}
+
+// sameDay returns whether both dates are the same local day.
+//
+func sameDay(a, b time.Time) bool {
+	ya, ma, da := a.Local().Date()
+	yb, mb, db := b.Local().Date()
+	return ya == yb && ma == mb && da == db
+}
+
+// monthStart returns the start of the monthly period containing the date,
+// with periods starting on the given day of the month.
+//
+func monthStart(date time.Time, resetDay int) time.Time {
+	if resetDay < 1 || resetDay > 28 {
+		resetDay = 1
+	}
+	date = date.Local()
+	year, month, day := date.Date()
+	if day < resetDay {
+		month--
+	}
+	return time.Date(year, month, resetDay, 0, 0, 0, 0, time.Local)
+}
+
+// bootTime returns the system boot time, in seconds since epoch, from /proc/stat.
+//
+func bootTime() int64 {
+	file, e := os.Open("/proc/stat")
+	if e != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "btime ") {
+			boot, _ := strconv.ParseInt(strings.TrimSpace(line[len("btime "):]), 10, 64)
+			return boot
+		}
+	}
+	return 0
+}
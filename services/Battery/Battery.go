@@ -0,0 +1,189 @@
+// Package Battery is a battery monitoring applet for Cairo-Dock.
+//
+// Batteries are found in the power supply sysfs class.
+package Battery
+
+import (
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"fmt"
+	"path/filepath"
+)
+
+//
+//------------------------------------------------------------------[ APPLET ]--
+
+func init() { cdtype.Applets.Register("Battery", NewApplet) }
+
+// Applet defines a dock applet.
+//
+type Applet struct {
+	cdtype.AppBase // Applet base and dock connection.
+
+	conf    *appletConf
+	service *Monitor
+}
+
+// NewApplet creates a new applet instance.
+//
+func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
+	app := &Applet{AppBase: base, service: &Monitor{}}
+	app.SetConfig(&app.conf)
+
+	// Events.
+	events.OnClick = app.Command().Callback(cmdLeft)
+	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
+			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
+		}
+		if app.conf.MiddleAction > 0 && app.conf.MiddleCommand != "" {
+			menu.AddEntry("Action middle click", "system-run", app.Command().Callback(cmdMiddle))
+		}
+	}
+
+	app.Poller().Add(app.service.Check)
+
+	app.service.App = app
+	app.service.Log = app.Log()
+	app.service.OnWarning = app.warn
+	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
+		cdtype.InfoNone:    {},
+		cdtype.InfoOnIcon:  {Sep: "\n", ShowPre: false},
+		cdtype.InfoOnLabel: {Sep: "\n", ShowPre: true},
+	}
+
+	return app
+}
+
+// Init load user configuration if needed and initialise applet.
+//
+func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
+	// Settings for poller and renderer.
+	app.service.Settings(app.conf.DisplayText, app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName)
+	app.service.ShowTime = app.conf.ShowTime
+	app.service.Levels = [levelCount]float64{
+		levelLow:      float64(app.conf.LowLevel),
+		levelCritical: float64(app.conf.CriticalLevel),
+	}
+	app.service.SetBatteries(app.conf.SysRoot, app.conf.Batteries)
+	app.service.SetSize(len(app.service.dirs))
+
+	// Defaults.
+	def.PollerInterval = app.conf.UpdateDelay.Value()
+	def.Commands[cmdLeft] = cdtype.NewCommandStd(app.conf.LeftAction, app.conf.LeftCommand, app.conf.LeftClass)
+	def.Commands[cmdMiddle] = cdtype.NewCommandStd(app.conf.MiddleAction, app.conf.MiddleCommand)
+}
+
+// warn shows the low charge dialog.
+//
+func (app *Applet) warn(ps *sysinfo.PowerSupply, level int) {
+	msg := app.Translate("Battery low")
+	if level == levelCritical {
+		msg = app.Translate("Battery critically low")
+	}
+	msg = fmt.Sprintf("%s\n%s: %.f%%", msg, ps.Name, ps.Capacity)
+	if left := sysinfo.FormatTimeLeft(ps.TimeLeft()); left != "" {
+		msg += " (" + left + ")"
+	}
+	app.Log().Err(app.ShowDialog(msg, dialogDuration), "battery warning")
+}
+
+//
+//-----------------------------------------------------------------[ MONITOR ]--
+
+// Charge warning levels.
+const (
+	levelNone = iota
+	levelLow
+	levelCritical
+	levelCount
+)
+
+// Monitor monitors batteries charge and renders it on the icon.
+//
+// A warning is sent once for each level reached while discharging, and reset
+// when the battery is plugged.
+//
+type Monitor struct {
+	sysinfo.RenderPercent
+
+	ShowTime  bool                                     // Display the time to empty or full.
+	Levels    [levelCount]float64                      // Charge warning levels, in percent. 0 to disable.
+	OnWarning func(ps *sysinfo.PowerSupply, level int) // Charge warning callback.
+
+	dirs   []string       // Batteries sysfs directories.
+	warned map[string]int // Last warning level sent, by battery.
+}
+
+// SetBatteries sets the batteries to monitor, found by name in the sysfs root
+// directory. If no name is provided, all batteries found are used.
+//
+func (mon *Monitor) SetBatteries(root string, names []string) {
+	mon.dirs = nil
+	mon.warned = make(map[string]int)
+
+	if len(names) > 0 {
+		for _, name := range names {
+			mon.dirs = append(mon.dirs, filepath.Join(root, name))
+		}
+		return
+	}
+
+	list, e := sysinfo.PowerSupplies(root)
+	if mon.Log.Err(e, "find batteries", root) {
+		return
+	}
+	for _, ps := range list {
+		if ps.IsBattery() {
+			mon.dirs = append(mon.dirs, filepath.Join(root, ps.Name))
+		}
+	}
+}
+
+// Check displays current batteries charge.
+//
+func (mon *Monitor) Check() {
+	mon.Clear()
+	for _, dir := range mon.dirs {
+		ps, e := sysinfo.ReadPowerSupply(dir)
+		if mon.Log.Err(e, "read battery") {
+			mon.AppendRaw(filepath.Base(dir), 0, 0, "N/A")
+			continue
+		}
+
+		text := fmt.Sprintf("%.f%%", ps.Capacity)
+		if left := sysinfo.FormatTimeLeft(ps.TimeLeft()); mon.ShowTime && left != "" {
+			text += " " + left
+		}
+		if ps.Status == sysinfo.PowerCharging {
+			text += " +"
+		}
+		mon.AppendRaw(ps.Name, ps.Capacity/100, ps.Capacity, text)
+		mon.checkLevel(ps)
+	}
+	mon.Display()
+}
+
+// checkLevel sends a warning when the battery reaches a new charge level.
+//
+func (mon *Monitor) checkLevel(ps *sysinfo.PowerSupply) {
+	if ps.Status != sysinfo.PowerDischarging {
+		mon.warned[ps.Name] = levelNone
+		return
+	}
+
+	level := levelNone
+	for lvl := levelLow; lvl < levelCount; lvl++ {
+		if mon.Levels[lvl] > 0 && ps.Capacity <= mon.Levels[lvl] {
+			level = lvl
+		}
+	}
+	if level > mon.warned[ps.Name] {
+		mon.warned[ps.Name] = level
+		if mon.OnWarning != nil {
+			mon.OnWarning(ps, level)
+		}
+	}
+}
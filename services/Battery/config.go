@@ -0,0 +1,46 @@
+package Battery
+
+import (
+	"github.com/sqp/godock/libs/cdtype"
+)
+
+// Commands references.
+const (
+	cmdLeft = iota
+	cmdMiddle
+)
+
+// dialogDuration is the display duration of low charge dialogs, in seconds.
+const dialogDuration = 20
+
+//------------------------------------------------------------------[ CONFIG ]--
+
+type appletConf struct {
+	cdtype.ConfGroupIconBoth `group:"Icon"`
+	groupConfiguration       `group:"Configuration"`
+	groupActions             `group:"Actions"`
+}
+
+type groupConfiguration struct {
+	DisplayText   cdtype.InfoPosition
+	DisplayValues int
+
+	GaugeName string
+	GraphType cdtype.RendererGraphType
+
+	UpdateDelay cdtype.Duration `default:"10"`
+	SysRoot     string          `default:"/sys/class/power_supply"`
+	Batteries   []string
+	ShowTime    bool
+
+	LowLevel      int
+	CriticalLevel int
+}
+
+type groupActions struct {
+	LeftAction    int
+	LeftCommand   string
+	LeftClass     string
+	MiddleAction  int
+	MiddleCommand string
+}
@@ -4,6 +4,7 @@
 //
 // Added:
 //   Autodetect location based on IP.
+//   Weather sources: weather.com, Open-Meteo, METAR/TAF (airports).
 //   Shortcuts: show dialog today, show tomorrow, open Webpage, recheck, set location.
 //   Editable template.
 //
@@ -47,7 +48,6 @@ import (
 // theme location : conf and real
 // reduce timer when fail on first try?
 // reenable show current dialog when show on icon is disabled (need to dl data)
-// maybe other backends (at least one to provide a fallback and comparisons)
 
 //
 //-------------------------------------------------------------------[ CONST ]--
@@ -106,7 +106,7 @@ type Applet struct {
 // NewApplet creates a new applet instance.
 //
 func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
-	app := &Applet{AppBase: base, weather: weather.New()}
+	app := &Applet{AppBase: base}
 	app.SetConfig(&app.conf, app.actions()...)
 
 	// Events.
@@ -144,12 +144,17 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Defaults.
 	def.PollerInterval = app.conf.UpdateDelay.Value()
 
-	// Share the conf with the weather service.
+	// Share the conf with the weather service of the selected backend.
+	app.weather = weather.New(app.conf.Backend)
 	app.weather.SetConfig(&app.conf.Config)
-	app.weather.Clear()
 
-	if app.conf.LocationCode == "" {
+	// Location codes are specific to a backend.
+	switch {
+	case app.conf.LocationCode == "":
 		app.DetectLocation()
+
+	case !app.weather.ValidLocation(app.conf.LocationCode):
+		app.FindBackendLocation()
 	}
 }
 
@@ -267,6 +272,7 @@ func (app *Applet) SetLocationCode(locationCode, locationName string) {
 	defer app.Poller().Restart()
 
 	app.conf.LocationCode = locationCode
+	app.conf.LocationName = locationName
 
 	//  Autodetect location if missing.
 	if locationCode == "" {
@@ -293,14 +299,32 @@ func (app *Applet) DetectLocation() {
 	if app.Log().Err(e, "autodetect location") {
 		return
 	}
-	locations, e := weather.FindLocation(loc.City + ", " + loc.Country)
+	locations, e := app.weather.FindLocation(loc.City + ", " + loc.Country)
 	if app.Log().Err(e, "FindLocation") || len(locations) == 0 {
 		return
 	}
-	app.conf.LocationCode = locations[0].ID // do not save, just set live value.
+	app.conf.LocationCode = locations[0].ID // do not save, just set live values.
+	app.conf.LocationName = locations[0].Name
 	app.Log().Debug("autodetect location", locations[0].Name)
 }
 
+// FindBackendLocation searches the location set by the user with the current
+// backend, when its code comes from another backend. The user setting is kept.
+//
+func (app *Applet) FindBackendLocation() {
+	name := app.conf.LocationName
+	if name != "" {
+		locations, e := app.weather.FindLocation(name)
+		if !app.Log().Err(e, "FindLocation") && len(locations) > 0 {
+			app.conf.LocationCode = locations[0].ID // do not save, just set live values.
+			app.Log().Debug("location for backend", locations[0].Name)
+			return
+		}
+	}
+	msg := app.Translate("Location not available with this weather source, please set it again.")
+	app.ShowDialog(msg+"\n"+name, app.conf.DialogDuration)
+}
+
 //
 //-----------------------------------------------------------------[ DISPLAY ]--
 
@@ -449,7 +473,7 @@ func (app *Applet) AskLocationConfirm(locstr string) {
 		app.SetLocationCode("", "*AUTODETECT*")
 		return
 	}
-	locations, e := weather.FindLocation(locstr)
+	locations, e := app.weather.FindLocation(locstr)
 	if app.Log().Err(e, "FindLocation") {
 		app.ShowDialog("Find location: "+e.Error(), 10)
 		return
@@ -6,6 +6,8 @@ import (
 
 	"github.com/sqp/godock/libs/cdtype" // Applet types.
 	"github.com/sqp/godock/libs/sysinfo"
+
+	"strconv"
 )
 
 //
@@ -29,9 +31,10 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.SetConfig(&app.conf)
 
 	// Events.
-	events.OnClick = app.Command().Callback(cmdLeft) // Left and middle click: launch the configured action.
-	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnClick = func() { app.clickAction(topClickLeft, cmdLeft) } // Left and middle click: launch the configured action.
+	events.OnMiddleClick = func() { app.clickAction(topClickMiddle, cmdMiddle) }
 	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		menu.AddEntry("Top processes", "utilities-system-monitor", func() { go app.showTop() })
 		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
 			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
 		}
@@ -43,10 +46,12 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.Poller().Add(app.service.Check)
 
 	app.service.App = app
+	app.service.Log = app.Log()
+	app.service.Alert = sysinfo.NewAlert(app)
 	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
 		cdtype.InfoNone:    {},
-		cdtype.InfoOnIcon:  {ShowPre: false},
-		cdtype.InfoOnLabel: {ShowPre: true},
+		cdtype.InfoOnIcon:  {Sep: "\n", ShowPre: false},
+		cdtype.InfoOnLabel: {Sep: " - ", ShowPre: true},
 	}
 
 	return app
@@ -57,8 +62,11 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Settings for poller and renderer.
 	app.service.Settings(app.conf.DisplayText, app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName)
-	app.service.SetSize(1)
-	app.service.interval = app.conf.UpdateDelay.Value()
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
+	app.service.ShowCores = app.conf.ShowCores
+	app.service.ShowStates = app.conf.ShowStates
+	app.service.SetSize(app.service.Count())
 
 	// Defaults.
 	def.PollerInterval = app.conf.UpdateDelay.Value()
@@ -66,6 +74,28 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	def.Commands[cmdMiddle] = cdtype.NewCommandStd(app.conf.MiddleAction, app.conf.MiddleCommand)
 }
 
+// clickAction shows the top processes dialog if it's set for this click, or
+// launches the configured command.
+//
+func (app *Applet) clickAction(click, cmd int) {
+	if app.conf.TopClick == click {
+		go app.showTop() // Threaded as data collection can take some time.
+		return
+	}
+	app.Command().Launch(cmd)
+}
+
+// showTop shows the dialog with the processes using the most CPU.
+//
+func (app *Applet) showTop() {
+	list, e := sysinfo.TopCPU(app.conf.TopCount, topSampleDelay)
+	if app.Log().Err(e, "top processes") {
+		return
+	}
+	e = sysinfo.PopupProcesses(app, app.Translate("Top processes by CPU usage"), list, sysinfo.FormatCPU)
+	app.Log().Err(e, "popup top processes")
+}
+
 //
 //---------------------------------------------------------------------[ CPU ]--
 
@@ -74,9 +104,12 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 type CPU struct {
 	sysinfo.RenderPercent
 
-	lastIdle uint64
-	nbCPU    float64
-	interval int
+	ShowCores  bool // Display one value for each core instead of the average.
+	ShowStates bool // Display the user, system, iowait and steal split.
+
+	last  sigar.Cpu   // Previous global counters.
+	cores []sigar.Cpu // Previous counters for each core.
+	nbCPU int
 }
 
 // NewCPU creates a new CPU monitoring service.
@@ -84,7 +117,20 @@ type CPU struct {
 func NewCPU() *CPU {
 	list := sigar.CpuList{}
 	list.Get()
-	return &CPU{nbCPU: float64(len(list.List))}
+	return &CPU{nbCPU: len(list.List)}
+}
+
+// Count returns the number of values to render with current settings.
+//
+func (cpu *CPU) Count() int {
+	count := 1
+	if cpu.ShowCores && cpu.nbCPU > 0 {
+		count = cpu.nbCPU
+	}
+	if cpu.ShowStates {
+		count += len(cpuStates)
+	}
+	return count
 }
 
 // Check displays current CPU activity (average since last interval).
@@ -95,12 +141,65 @@ func (cpu *CPU) Check() {
 	procs := sigar.Cpu{}
 	procs.Get()
 
-	if cpu.lastIdle > 0 { // Initialized.
-		delta := procs.Idle - cpu.lastIdle
-		used := float64(delta) / cpu.nbCPU / float64(cpu.interval)
+	var list sigar.CpuList
+	if cpu.ShowCores {
+		list.Get()
+	}
+
+	if cpu.last.Total() > 0 { // Initialized.
+		delta := procs.Delta(cpu.last)
+
+		if cpu.ShowCores && len(list.List) == len(cpu.cores) {
+			for i, core := range list.List {
+				cpu.Append("CPU"+strconv.Itoa(i), cpuUsed(core.Delta(cpu.cores[i])))
+			}
+		} else {
+			cpu.Append("CPU", cpuUsed(delta))
+		}
+
+		if cpu.ShowStates {
+			for _, state := range cpuStates {
+				cpu.Append(state.name, cpuPart(state.get(delta), delta))
+			}
+		}
 
-		cpu.Append("CPU", (100-used)/100)
 		cpu.Display()
 	}
-	cpu.lastIdle = procs.Idle
+	cpu.last = procs
+	cpu.cores = list.List
+}
+
+//
+//--------------------------------------------------------------[ CPU STATES ]--
+
+// cpuStates defines the CPU time split displayed with ShowStates.
+//
+var cpuStates = []struct {
+	name string
+	get  func(sigar.Cpu) uint64
+}{
+	{"User", func(c sigar.Cpu) uint64 { return c.User + c.Nice }},
+	{"System", func(c sigar.Cpu) uint64 { return c.Sys + c.Irq + c.SoftIrq }},
+	{"IOWait", func(c sigar.Cpu) uint64 { return c.Wait }},
+	{"Steal", func(c sigar.Cpu) uint64 { return c.Stolen }},
+}
+
+// cpuUsed returns the busy part of the CPU time delta, in the 0..1 range.
+// An empty delta (offline core, or no time elapsed) is reported as unused.
+//
+func cpuUsed(delta sigar.Cpu) float64 {
+	if delta.Total() == 0 {
+		return 0
+	}
+	return 1 - cpuPart(delta.Idle, delta)
+}
+
+// cpuPart returns the ratio of the given value in the CPU time delta.
+//
+func cpuPart(value uint64, delta sigar.Cpu) float64 {
+	total := delta.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(value) / float64(total)
 }
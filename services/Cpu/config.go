@@ -1,6 +1,11 @@
 package Cpu
 
-import "github.com/sqp/godock/libs/cdtype"
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"time"
+)
 
 const (
 	defaultUpdateDelay = 3
@@ -12,12 +17,23 @@ const (
 	cmdMiddle
 )
 
+// Top processes dialog click options.
+const (
+	topClickNone = iota
+	topClickLeft
+	topClickMiddle
+)
+
+// topSampleDelay is the CPU usage measure duration for the top processes dialog.
+const topSampleDelay = time.Second
+
 //------------------------------------------------------------------[ CONFIG ]--
 
 type appletConf struct {
 	cdtype.ConfGroupIconBoth `group:"Icon"`
 	groupConfiguration       `group:"Configuration"`
 	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
 }
 
 type groupConfiguration struct {
@@ -34,6 +50,9 @@ type groupConfiguration struct {
 	// GraphMix bool
 
 	UpdateDelay cdtype.Duration `default:"3"`
+	RecordSize  int
+	ShowCores   bool
+	ShowStates  bool
 }
 
 type groupActions struct {
@@ -43,6 +62,9 @@ type groupActions struct {
 	MiddleAction  int
 	MiddleCommand string
 
+	TopClick int
+	TopCount int `default:"10"`
+
 	// Still hidden.
 	Debug bool
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/sqp/godock/libs/text/bytesize"
 
 	"fmt"
+	"strings"
 )
 
 //
@@ -30,9 +31,11 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.SetConfig(&app.conf)
 
 	// Events.
-	events.OnClick = app.Command().Callback(cmdLeft)
-	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnClick = func() { app.clickAction(topClickLeft, cmdLeft) } // Left and middle click: launch the configured action.
+	events.OnMiddleClick = func() { app.clickAction(topClickMiddle, cmdMiddle) }
 	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		menu.AddEntry("Top I/O processes", "utilities-system-monitor", func() { go app.showTopProcesses() })
+		menu.AddEntry("Top I/O control groups", "utilities-system-monitor", func() { go app.showTopCgroups() })
 		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
 			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
 		}
@@ -46,6 +49,8 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.service.FormatIcon = formatIcon
 	app.service.FormatLabel = formatLabel
 	app.service.GetData = sysinfo.GetDiskActivity
+	app.service.Alert = sysinfo.NewAlert(app)
+	app.service.Alert.Scale = BlockSize / 1024. // Blocks to KiB.
 
 	app.Poller().Add(app.service.Check)
 
@@ -58,6 +63,9 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Settings for poller and IOActivity (force renderer reset in case of reload).
 	app.service.Settings(uint64(app.conf.UpdateDelay.Value()), cdtype.InfoPosition(app.conf.DisplayText), app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName, app.conf.Disks...)
 
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
+
 	// Defaults.
 	def.PollerInterval = app.conf.UpdateDelay.Value()
 	def.Commands = cdtype.Commands{
@@ -66,6 +74,64 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	}
 }
 
+//
+//-----------------------------------------------------------------[ TOP I/O ]--
+
+// clickAction shows the top I/O dialog if it's set for this click, or launches
+// the configured command.
+//
+func (app *Applet) clickAction(click, cmd int) {
+	if app.conf.TopClick != click {
+		app.Command().Launch(cmd)
+		return
+	}
+	switch app.conf.TopMode { // Threaded as data collection takes some time.
+	case topModeProcesses:
+		go app.showTopProcesses()
+
+	case topModeCgroups:
+		go app.showTopCgroups()
+	}
+}
+
+// showTopProcesses shows the dialog with the processes doing the most I/O.
+//
+func (app *Applet) showTopProcesses() {
+	list, e := sysinfo.TopIO(app.conf.TopCount, topSampleDelay)
+	if app.Log().Err(e, "top I/O processes") {
+		return
+	}
+	if len(list) == 0 {
+		app.Log().Err(app.ShowDialog(app.Translate("No disk activity."), dialogDuration), "top I/O")
+		return
+	}
+	e = sysinfo.PopupProcesses(app, app.Translate("Top processes by disk I/O"), list, sysinfo.FormatDiskIO)
+	app.Log().Err(e, "popup top I/O processes")
+}
+
+// showTopCgroups shows the dialog with the control groups doing the most I/O.
+//
+func (app *Applet) showTopCgroups() {
+	root, e := sysinfo.CgroupRoot()
+	if app.Log().Err(e, "top I/O cgroups") {
+		app.Log().Err(app.ShowDialog(app.Translate("Control groups v2 not available."), dialogDuration), "top I/O")
+		return
+	}
+	list, e := sysinfo.TopCgroupIO(root, app.conf.TopCount, topSampleDelay)
+	if app.Log().Err(e, "top I/O cgroups") {
+		return
+	}
+
+	lines := []string{app.Translate("Top control groups by disk I/O")}
+	for _, cg := range list {
+		lines = append(lines, cg.Format())
+	}
+	if len(list) == 0 {
+		lines = append(lines, app.Translate("No disk activity."))
+	}
+	app.Log().Err(app.ShowDialog(strings.Join(lines, "\n"), dialogDuration), "top I/O cgroups")
+}
+
 //
 //-----------------------------------------------------------------[ DISPLAY ]--
 
@@ -1,6 +1,11 @@
 package DiskActivity
 
-import "github.com/sqp/godock/libs/cdtype"
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"time"
+)
 
 const (
 	// BlockSize is the disk block size.
@@ -13,12 +18,32 @@ const (
 	cmdMiddle
 )
 
+// Top I/O dialog click options.
+const (
+	topClickNone = iota
+	topClickLeft
+	topClickMiddle
+)
+
+// Top I/O dialog sources.
+const (
+	topModeProcesses = iota
+	topModeCgroups
+)
+
+// dialogDuration is the display duration of top I/O dialogs, in seconds.
+const dialogDuration = 15
+
+// topSampleDelay is the I/O rates measure duration for the top I/O dialog.
+const topSampleDelay = 2 * time.Second
+
 //------------------------------------------------------------------[ CONFIG ]--
 
 type appletConf struct {
 	cdtype.ConfGroupIconBoth `group:"Icon"`
 	groupConfiguration       `group:"Configuration"`
 	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
 }
 
 type groupConfiguration struct {
@@ -35,6 +60,7 @@ type groupConfiguration struct {
 	// GraphMix bool
 
 	UpdateDelay cdtype.Duration `default:"3"`
+	RecordSize  int
 	Disks       []string
 }
 
@@ -44,6 +70,10 @@ type groupActions struct {
 	LeftClass     string
 	MiddleAction  int
 	MiddleCommand string
+
+	TopClick int
+	TopMode  int
+	TopCount int
 }
 
 /*
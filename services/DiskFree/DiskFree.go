@@ -14,6 +14,10 @@ import (
 
 	"github.com/sqp/godock/libs/cdtype" // Applet types.
 	"github.com/sqp/godock/libs/sysinfo"
+
+	"strings"
+	"sync"
+	"time"
 )
 
 func init() { cdtype.Applets.Register("DiskFree", NewApplet) }
@@ -25,6 +29,7 @@ type Applet struct {
 
 	conf    *appletConf
 	service DiskFree
+	mounts  *sysinfo.MountWatcher
 }
 
 // NewApplet creates a new applet instance.
@@ -44,12 +49,14 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 			menu.AddEntry("Action middle click", "system-run", app.Command().Callback(cmdMiddle))
 		}
 	}
+	events.End = func() { app.Log().Err(app.mounts.Close(), "stop mounts watcher") }
 
 	// Disk free service.
 	app.Poller().Add(app.service.Check)
 
 	app.service.App = app
-	app.service.log = app.Log()
+	app.service.Log = app.Log()
+	app.service.Alert = sysinfo.NewAlert(app)
 	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
 		cdtype.InfoNone:    {},
 		cdtype.InfoOnIcon:  {Sep: "\n", ShowPost: false},
@@ -64,8 +71,28 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Settings for DiskFree.
 	app.service.Settings(app.conf.DisplayText, 0, 0, app.conf.GaugeName)
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.ShowInodes = app.conf.ShowInodes
+	app.service.ShowForecast = app.conf.ShowForecast
+	app.service.textPosition = app.conf.DisplayText
+	app.service.label = app.conf.Name
+	if app.service.label == "" {
+		app.service.label = app.Name()
+	}
 	app.service.SetParts(app.conf.Partitions, app.conf.AutoDetect)
 
+	// Watch mounts to update autodetected partitions immediately.
+	switch {
+	case app.conf.AutoDetect && app.mounts == nil:
+		var e error
+		app.mounts, e = sysinfo.WatchMounts(app.Poller().Restart)
+		app.Log().Err(e, "watch mounts")
+
+	case !app.conf.AutoDetect && app.mounts != nil:
+		app.Log().Err(app.mounts.Close(), "stop mounts watcher")
+		app.mounts = nil
+	}
+
 	// Defaults.
 	def.PollerInterval = app.conf.UpdateDelay.Value()
 	def.Commands = cdtype.Commands{
@@ -82,24 +109,34 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 type DiskFree struct {
 	sysinfo.RenderPercent
 
+	ShowInodes   bool // Display the inodes usage after each partition.
+	ShowForecast bool // Display the estimated time before partitions are full.
+
 	autoDetect bool     // Will autodetect mounted partitions.
 	names      []string // User provided list of partitions.
 	nbValues   int
 
-	log cdtype.Logger
+	forecasts    map[string]*sysinfo.Forecast // Growth history, by partition.
+	textPosition cdtype.InfoPosition          // Forecasts are added to the text on label, or replace the label.
+	label        string                       // Default icon label.
+
+	mu sync.Mutex // Check is also called by the mounts watcher.
 }
 
 // SetParts sets the user monitored pertitions.
 //
 func (disks *DiskFree) SetParts(parts []string, autoDetect bool) {
+	disks.mu.Lock()
+	defer disks.mu.Unlock()
 	disks.names = parts
 	disks.autoDetect = autoDetect
 
-	disks.nbValues = len(parts) + len(disks.findOthers())
+	disks.forecasts = make(map[string]*sysinfo.Forecast)
+	disks.nbValues = disks.countValues(len(parts) + len(disks.findOthers()))
 	disks.SetSize(disks.nbValues)
 
 	if disks.nbValues == 0 {
-		disks.log.NewErr("none", "disk found")
+		disks.Log.NewErr("none", "disk found")
 		disks.App.SetLabel("No disks found.")
 	}
 }
@@ -107,26 +144,91 @@ func (disks *DiskFree) SetParts(parts []string, autoDetect bool) {
 // Check updates disk usage information from the system.
 //
 func (disks *DiskFree) Check() {
+	disks.mu.Lock()
+	defer disks.mu.Unlock()
 	disks.Clear()
 
 	parts := append(disks.names, disks.findOthers()...)
+	var forecasts []string
 
 	for _, name := range parts {
 		usage := sigar.FileSystemUsage{}
-		value := float64(-1)
-		if usage.Get(name) == nil { // no error
-			value = float64(usage.UsePercent()) / 100
+		if usage.Get(name) != nil {
+			disks.Append(name, -1)
+			if disks.ShowInodes {
+				disks.Append(name+" inodes", -1)
+			}
+			continue
+		}
+
+		value := float64(usage.UsePercent()) / 100
+		forecast := disks.forecast(name, usage)
+		switch {
+		case forecast == "":
+			disks.Append(name, value)
+
+		case disks.textPosition == cdtype.InfoOnLabel:
+			disks.AppendRaw(name, value, value*100, sysinfo.FormatPercent(value*100)+" ("+forecast+")")
+
+		default:
+			disks.Append(name, value)
+			forecasts = append(forecasts, name+": "+forecast)
+		}
+
+		if disks.ShowInodes {
+			inodes := float64(-1)
+			if usage.Files > 0 { // Some filesystems have no inodes limit.
+				inodes = float64(usage.Files-usage.FreeFiles) / float64(usage.Files)
+			}
+			disks.Append(name+" inodes", inodes)
 		}
-		disks.Append(name, value)
 	}
 
-	if newcount := len(parts); newcount != disks.nbValues {
-		disks.log.Debug("Number of partitions changed. Resizing", disks.nbValues, "=>", newcount)
+	if newcount := disks.countValues(len(parts)); newcount != disks.nbValues {
+		disks.Log.Debug("Number of partitions changed. Resizing", disks.nbValues, "=>", newcount)
 		disks.nbValues = newcount
 		disks.SetSize(newcount)
 	}
 
 	disks.Display()
+
+	if disks.ShowForecast && disks.textPosition != cdtype.InfoOnLabel {
+		label := disks.label
+		if len(forecasts) > 0 {
+			label = strings.Join(forecasts, "\n")
+		}
+		disks.App.SetLabel(label)
+	}
+}
+
+// countValues returns the number of values to render for the partitions count.
+//
+func (disks *DiskFree) countValues(nbParts int) int {
+	if disks.ShowInodes {
+		return 2 * nbParts
+	}
+	return nbParts
+}
+
+// forecast updates the partition growth history and returns the estimated
+// time before it's full, or an empty string if unknown.
+//
+func (disks *DiskFree) forecast(name string, usage sigar.FileSystemUsage) string {
+	if !disks.ShowForecast {
+		return ""
+	}
+	fc, ok := disks.forecasts[name]
+	if !ok {
+		fc = sysinfo.NewForecast(forecastWindow)
+		disks.forecasts[name] = fc
+	}
+	fc.Add(float64(usage.Used), time.Now())
+
+	dur, ok := fc.Remaining(float64(usage.Avail))
+	if !ok || dur > forecastMax {
+		return ""
+	}
+	return "full in " + sysinfo.FormatRemaining(dur)
 }
 
 // findOthers returns the list of partitions found and not in user list.
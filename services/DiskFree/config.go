@@ -1,6 +1,17 @@
 package DiskFree
 
-import "github.com/sqp/godock/libs/cdtype"
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"time"
+)
+
+// Free space forecast settings.
+const (
+	forecastWindow = 6 * time.Hour        // Averaging window of the partitions growth.
+	forecastMax    = 365 * 24 * time.Hour // Forecasts over this duration aren't displayed.
+)
 
 // Commands references.
 const (
@@ -14,6 +25,7 @@ type appletConf struct {
 	cdtype.ConfGroupIconBoth `group:"Icon"`
 	groupConfiguration       `group:"Configuration"`
 	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
 }
 
 type groupConfiguration struct {
@@ -24,6 +36,9 @@ type groupConfiguration struct {
 	UpdateDelay cdtype.Duration `default:"60"`
 	AutoDetect  bool
 	Partitions  []string
+
+	ShowInodes   bool
+	ShowForecast bool
 }
 
 type groupActions struct {
@@ -2,9 +2,12 @@
 package GoGmail
 
 import (
-	"github.com/sqp/godock/libs/cdtype" // Applet types.
+	"github.com/sqp/godock/libs/cdglobal" // Global consts.
+	"github.com/sqp/godock/libs/cdtype"   // Applet types.
 	"github.com/sqp/godock/libs/ternary"
+	"github.com/sqp/godock/libs/text/strhelp"
 
+	"errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -21,12 +24,12 @@ type Applet struct {
 	cdtype.AppBase // Applet base and dock connection.
 
 	// Main interfaces.
-	render RendererMail
-	data   Mailbox
-	conf   *mailConf
+	render   RendererMail
+	accounts []*account // A single account without subicon if no accounts file.
+	conf     *mailConf
 
 	// Local variables.
-	err error // Buffer for last error to prevent displaying it twice.
+	interval time.Duration // Poller interval, the smallest accounts delay.
 }
 
 // NewApplet creates a new applet instance.
@@ -39,23 +42,28 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	events.OnClick = func() { app.Action().Launch(app.Action().ID(app.conf.ActionClickLeft)) }
 	events.OnMiddleClick = func() { app.Action().Launch(app.Action().ID(app.conf.ActionClickMiddle)) }
 	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		menuActions := menuFull
+		single := app.single()
 		switch {
-		case !app.data.IsValid(): // No running loop =  no registration. User will do as expected !
+		case single != nil && !single.valid(): // No running loop =  no registration. User will do as expected !
 			app.Action().BuildMenu(menu, menuRegister)
+			return
 
-		case app.Window().IsOpened(): // Monitored application opened.
-			app.Action().BuildMenu(menu, menuFull[1:]) // Drop "Open client" option, already provided as window action by the dock.
+		case single == nil: // Accounts are set in the accounts file.
+			menuActions = menuAccounts
+		}
 
-		default:
-			app.Action().BuildMenu(menu, menuFull)
+		if app.Window().IsOpened() { // Monitored application opened.
+			menuActions = menuActions[1:] // Drop "Open client" option, already provided as window action by the dock.
 		}
+		app.Action().BuildMenu(menu, menuActions)
 	}
+	events.OnSubClick = app.onSubClick
 
-	// Prepare mailbox with the display callback that will receive update info.
-	app.data = NewFeed(app.updateDisplay)
+	events.End = app.stopWatch
 
 	// The poller will check for new mails on a timer, with a small emblem during the polling.
-	poller := app.Poller().Add(app.data.Check)
+	poller := app.Poller().Add(app.check)
 	poller.SetPreCheck(func() {
 		app.SetEmblem(app.FileLocation("img", "go-down.svg"), cdtype.EmblemTopLeft)
 		app.Log().Debug("Check mails")
@@ -68,10 +76,10 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 // Init load user configuration if needed and initialise applet.
 //
 func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
-	// Reset data to be sure our display will be refreshed.
-	app.data.Clear()
-	app.data.LoadLogin(app.FileDataDir(loginLocation))
-	app.err = nil
+	// Prepare new mailboxes to be sure our display will be refreshed.
+	app.stopWatch()
+	app.setAccounts()
+	app.startWatch()
 
 	// Define the mail client action.
 	if app.conf.MailClientName == "" { //  Set default to webpage if not provided.
@@ -81,7 +89,7 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 
 	// Defaults.
 	def.Label = "Mail unchecked"
-	def.PollerInterval = app.conf.UpdateDelay.Value()
+	def.PollerInterval = int(app.interval / time.Second)
 
 	// Add 1 to action as we don't provide the none option.
 	cmd := cdtype.NewCommandStd(app.conf.MailClientAction+1, app.conf.MailClientName, app.conf.MailClientClass)
@@ -100,6 +108,121 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	default: // NoDisplay case, but using default to be sure we have a valid renderer.
 		app.render = NewRenderedNone()
 	}
+
+	// One subicon per account, with its unread mails count.
+	app.RemoveSubIcons()
+	for _, acc := range app.accounts {
+		if acc.sub {
+			icon := strhelp.First(acc.Icon, app.FileLocation("img", "gmail-icon.svg"))
+			app.Log().Err(app.AddSubIcon(acc.Name, icon, acc.Name), "add subicon", acc.Name)
+		}
+	}
+}
+
+// setAccounts creates the mailboxes of the accounts file, or the single
+// account of the applet config if there is none.
+//
+func (app *Applet) setAccounts() {
+	list, e := LoadAccounts(app.Log(), app.accountsFile())
+	app.Log().Err(e, "load accounts")
+
+	appDelay := time.Duration(app.conf.UpdateDelay.Value()) * time.Second
+	app.accounts = nil
+	app.interval = appDelay
+	for i, ac := range list {
+		acc := &account{AccountConfig: ac, sub: true, delay: appDelay}
+		if ac.Delay > 0 {
+			acc.delay = time.Duration(ac.Delay) * time.Minute
+		}
+		acc.box, e = ac.NewMailbox(func(delta int, first bool, e error) { app.updateDisplay(acc, delta, first, e) })
+		if app.Log().Err(e, "account") {
+			continue
+		}
+
+		if ac.User != "" && ac.Password == "" {
+			acc.key = ac.Name
+		}
+
+		if i == 0 || acc.delay < app.interval {
+			app.interval = acc.delay
+		}
+		app.accounts = append(app.accounts, acc)
+	}
+	if len(app.accounts) > 0 {
+		return
+	}
+
+	// Single account from the applet config, with the login in credentials.
+	acc := &account{delay: appDelay}
+	onResult := func(delta int, first bool, e error) { app.updateDisplay(acc, delta, first, e) }
+	switch app.conf.MailboxType {
+	case MailboxIMAP:
+		acc.box = NewIMAP(onResult, app.conf.IMAPServer, app.conf.IMAPSecurity, app.conf.IMAPFolders...)
+		acc.key, acc.legacy = loginKeyIMAP, app.FileDataDir(loginLocationIMAP)
+
+	default:
+		acc.box = NewFeed(onResult)
+		acc.key, acc.legacy = loginKey, app.FileDataDir(loginLocation)
+	}
+	app.accounts = []*account{acc}
+	app.interval = appDelay
+}
+
+// accountsFile returns the location of the accounts file.
+//
+func (app *Applet) accountsFile() string {
+	file := strhelp.First(app.conf.AccountsFile, AccountsFile)
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return app.FileDataDir(cdglobal.DirUserAppData, file)
+}
+
+// single returns the account if the applet uses the applet config account.
+//
+func (app *Applet) single() *account {
+	if len(app.accounts) == 1 && !app.accounts[0].sub {
+		return app.accounts[0]
+	}
+	return nil
+}
+
+// check checks the accounts which delay is over. Polled.
+//
+// Logins are loaded on the first check, as the credentials may have to ask the
+// user, which can't be done from the events loop.
+//
+func (app *Applet) check() {
+	now := time.Now()
+	for _, acc := range app.accounts {
+		if app.loadLogin(acc) {
+			app.watch(acc)
+		}
+		if acc.due(now, app.interval) {
+			acc.box.Check()
+		}
+	}
+}
+
+// count returns the number of unread mails of all accounts.
+//
+func (app *Applet) count() (count int) {
+	for _, acc := range app.accounts {
+		count += acc.box.Count()
+	}
+	return count
+}
+
+// isValid returns true if an account has login informations, or if they are
+// not loaded yet.
+//
+func (app *Applet) isValid() bool {
+	for _, acc := range app.accounts {
+		if acc.valid() {
+			return true
+		}
+	}
+	return false
 }
 
 //
@@ -142,7 +265,7 @@ func (app *Applet) actions() []*cdtype.Action {
 // testNeedRegister return true if registration was called.
 //
 func (app *Applet) testNeedRegister() bool {
-	if app.data.IsValid() {
+	if app.isValid() {
 		return false
 	}
 	app.Action().Launch(ActionRegister) // No registration? User must comply !
@@ -166,7 +289,10 @@ func (app *Applet) actionCheckMail() {
 	if app.testNeedRegister() {
 		return
 	}
-	app.Poller().Restart() // Should trigger a app.data.Check()
+	for _, acc := range app.accounts {
+		acc.force()
+	}
+	app.Poller().Restart() // Should trigger a app.check()
 }
 
 // Show dialog with informations on last mails.
@@ -176,84 +302,252 @@ func (app *Applet) actionShowMails() {
 	if app.testNeedRegister() {
 		return
 	}
-	app.mailPopup(app.conf.DialogNbMail, 0, "ListMailsManual")
+	app.mailPopup(mergeMails(app.accounts), app.conf.DialogNbMail, 0, "ListMailsManual")
 }
 
 // Request login informations from user. Popup an AskText dialog.
-// Save to disk and try to get new data if confirmed.
+// Save to the credentials and try to get new data if confirmed.
 //
 func (app *Applet) actionRegister() {
-	text := ternary.String(app.data.IsValid(), "", "No account configured.\n\n")
+	acc := app.single()
+	text := "Please enter your login in the format username:password"
+	if acc == nil { // Accounts file: ask the first missing password.
+		for _, test := range app.accounts {
+			if test.key != "" && !test.valid() {
+				acc = test
+				break
+			}
+		}
+		if acc == nil {
+			app.ShowDialog("Accounts are set in the file "+app.accountsFile(), app.conf.DialogTimer)
+			return
+		}
+		text = "Please enter the password of the account " + acc.Name + " (" + acc.User + ")"
+	}
+
+	text = ternary.String(acc.box.IsValid(), "", "No account configured.\n\n") + text
 	e := app.PopupDialog(cdtype.DialogData{
-		Message: text + "Please enter your login in the format username:password",
+		Message: text,
 		Buttons: "ok;cancel",
-		Widget:  cdtype.DialogWidgetText{},
+		Widget:  cdtype.DialogWidgetText{Hidden: acc.sub},
 		Callback: cdtype.DialogCallbackValidString(func(str string) {
-			app.data.SaveLogin(str)
-			app.Action().Launch(ActionCheckMail) // CheckMail will launch a check and reset the timer.
+			if str != "" {
+				go app.saveLogin(acc, str) // Credentials may ask the user.
+			}
 		}),
 	})
 
 	app.Log().Err(e, "popup")
 }
 
+// onSubClick opens the mail client of the account.
+//
+func (app *Applet) onSubClick(name string) {
+	for _, acc := range app.accounts {
+		switch {
+		case acc.Name != name:
+
+		case acc.Client == "":
+			app.Command().Launch(cmdMailClient)
+
+		case strings.Contains(acc.Client, "://"):
+			app.Log().Err(app.Log().ExecAsync(cdglobal.CmdOpen, acc.Client), "open mail client", acc.Client)
+
+		default:
+			cmd, e := app.Log().ExecShlex(acc.Client)
+			if e == nil {
+				app.Log().Err(cmd.Start(), "open mail client", acc.Client)
+			}
+		}
+	}
+}
+
 //
 //-----------------------------------------------------------[ MAIL HANDLING ]--
 
-// Update display callback. Receives mail check result with new messages count
-// and polling error status.
+// loadLogin loads the login of the account from the applet credentials, once
+// it succeeds. The old login file is migrated if needed, and removed when the
+// login is read back. Returns true if loaded now.
+//
+func (app *Applet) loadLogin(acc *account) bool {
+	if acc.isLoaded() {
+		return false
+	}
+	acc.loading.Lock()
+	defer acc.loading.Unlock()
+	if acc.isLoaded() { // Saved while waiting.
+		return false
+	}
+
+	secret, e := app.Credentials().Get(acc.key)
+	if app.Log().Err(e, "load login", acc.Name) {
+		return false // Retried on the next check.
+	}
+	switch {
+	case acc.legacy == "":
+
+	case secret == "":
+		secret = app.migrateLogin(acc)
+
+	default: // Migrated login read back, the old file isn't needed anymore.
+		if e := os.Remove(acc.legacy); !os.IsNotExist(e) && !app.Log().Err(e, "remove login file") {
+			app.Log().Info("old login file removed", acc.legacy)
+		}
+	}
+	acc.setSecret(secret)
+	return true
+}
+
+// migrateLogin copies the login of the old login file to the credentials. The
+// file is kept until the login is read back from the credentials.
+//
+func (app *Applet) migrateLogin(acc *account) string {
+	user, password, e := readLoginFile(acc.legacy)
+	if os.IsNotExist(e) || app.Log().Err(e, "read login file") {
+		return ""
+	}
+	secret := user + ":" + password
+	e = app.Credentials().Set(acc.key, secret)
+	if !app.Log().Err(e, "migrate login") {
+		app.Log().Info("login copied to", app.Credentials().Backend())
+	}
+	return secret // Use it anyway.
+}
+
+// saveLogin saves the login to the credentials, and checks the account.
+//
+func (app *Applet) saveLogin(acc *account, secret string) {
+	acc.loading.Lock()
+	e := app.Credentials().Set(acc.key, secret)
+	app.Log().Err(e, "save login", acc.Name)
+	acc.setSecret(secret) // Use it for the session, even if not saved.
+	acc.loading.Unlock()
+
+	app.watch(acc)
+	app.Action().Launch(ActionCheckMail) // CheckMail will launch a check and reset the timer.
+}
+
+// startWatch starts the push notifications of the accounts.
+//
+func (app *Applet) startWatch() {
+	for _, acc := range app.accounts {
+		app.watch(acc)
+	}
+}
+
+// watch starts the push notifications if the mailbox supports them and the
+// user enabled them. Changes trigger a check of the account with the poller.
+//
+func (app *Applet) watch(acc *account) {
+	watcher, ok := acc.box.(MailboxWatcher)
+	idle := acc.Idle || (!acc.sub && app.conf.IMAPIdle)
+	if !ok || !idle || !acc.box.IsValid() {
+		return
+	}
+	watcher.Watch(
+		func() { acc.force(); app.Poller().Restart() },
+		func(e error) { app.Log().Err(e, "watch mails", acc.Name) },
+	)
+}
+
+// stopWatch stops the push notifications of the mailboxes, if any.
+//
+func (app *Applet) stopWatch() {
+	for _, acc := range app.accounts {
+		if watcher, ok := acc.box.(MailboxWatcher); ok {
+			watcher.StopWatch()
+		}
+	}
+}
+
+// Update display callback. Receives mail check result of an account with new
+// messages count and polling error status.
 //
 // Update checked time and, if needed, send info or error to renderer and user
 // alerts.
 //
-func (app *Applet) updateDisplay(delta int, first bool, e error) {
+func (app *Applet) updateDisplay(acc *account, delta int, first bool, e error) {
 	eventTime := time.Now().String()[11:19]
 	label := "Checked: " + eventTime
 	switch {
 	case e != nil:
+		if acc.sub {
+			e = errors.New(acc.Name + ": " + e.Error())
+		}
 		label = "Update Error: " + eventTime + "\n" + e.Error() // Error time is refreshed.
 		app.Log().Err(e, "Check mail")
-		if app.err == nil || e.Error() != app.err.Error() { // Error buffer, dont warn twice the same information.
-			app.render.Error(e)
-			app.ShowDialog("Mail check error"+e.Error(), app.conf.DialogTimer)
+		if acc.err == nil || e.Error() != acc.err.Error() { // Error buffer, dont warn twice the same information.
+			app.renderError(acc, e)
+			app.ShowDialog("Mail check error: "+e.Error(), app.conf.DialogTimer)
 			// app.PopUp("Mail check error", e.Error())
-			app.err = e
+			acc.err = e
 		}
 
 	case first:
-		app.Log().Debug("  * First check", delta)
+		app.Log().Debug("  * First check", acc.Name, delta)
 
 	case delta > 0:
-		app.Log().Debug("  * Count changed", delta)
-		app.sendAlert(delta)
+		app.Log().Debug("  * Count changed", acc.Name, delta)
+		app.sendAlert(acc, delta)
 
 	case delta == 0:
-		app.Log().Debug("  * ", "no change")
+		app.Log().Debug("  * ", acc.Name, "no change")
 	}
 
 	switch {
-	case e == nil && app.err != nil: // Error disappeared. Cleaning buffer and refresh display.
-		app.render.Set(app.data.Count())
-		app.err = nil
+	case e == nil && acc.err != nil: // Error disappeared. Cleaning buffer and refresh display.
+		app.renderCount(acc)
+		acc.err = nil
 
 	case delta != 0: // Refresh display only if changed.
-		app.render.Set(app.data.Count())
+		app.renderCount(acc)
 	}
 	app.SetLabel(label)
 }
 
+// renderCount displays the unread mails count of the account on its subicon,
+// and the total on the main icon.
+//
+func (app *Applet) renderCount(acc *account) {
+	if sub := app.subIcon(acc); sub != nil {
+		count := acc.box.Count()
+		sub.SetQuickInfo(ternary.String(count > 0, strconv.Itoa(count), ""))
+	}
+	app.render.Set(app.count())
+}
+
+// renderError displays the account error on its subicon, or on the main icon
+// for the single account.
+//
+func (app *Applet) renderError(acc *account, e error) {
+	if sub := app.subIcon(acc); sub != nil {
+		sub.SetQuickInfo("N/A")
+		return
+	}
+	app.render.Error(e)
+}
+
+// subIcon returns the subicon of the account, if any.
+//
+func (app *Applet) subIcon(acc *account) cdtype.IconBase {
+	if !acc.sub {
+		return nil
+	}
+	return app.SubIcon(acc.Name)
+}
+
 // Mail count changed. Check if we need to warn the user.
 //
-func (app *Applet) sendAlert(delta int) {
-	if app.conf.AlertDialogEnabled {
+func (app *Applet) sendAlert(acc *account, delta int) {
+	if acc.useAlert(AlertDialog, app.conf.AlertDialogEnabled) {
 		nb := ternary.Min(delta, app.conf.DialogNbMail)
-		app.mailPopup(nb, app.conf.DialogTimer, "ListMailsNew")
+		app.mailPopup(acc.box.Data(), nb, app.conf.DialogTimer, "ListMailsNew")
 	}
-	if app.conf.AlertAnimName != "" {
+	if app.conf.AlertAnimName != "" && acc.useAlert(AlertAnimation, true) {
 		app.Animate(app.conf.AlertAnimName, app.conf.AlertAnimDuration)
 	}
-	if app.conf.AlertSoundEnabled {
-		sound := app.conf.AlertSoundFile
+	if acc.useAlert(AlertSound, app.conf.AlertSoundEnabled) {
+		sound := strhelp.First(acc.Sound, app.conf.AlertSoundFile)
 		if len(sound) == 0 {
 			app.Log().Info("No sound file configured")
 			return
@@ -270,10 +564,7 @@ func (app *Applet) sendAlert(delta int) {
 // Show dialog with information for the given number of mails. Can display an
 // additional comment about mails being new if the second param is set to true.
 //
-func (app *Applet) mailPopup(nb, duration int, templateFunc string) {
-	// feed := app.data.Data().(*Feed)
-	feed := app.data.(*Feed)
-
+func (app *Applet) mailPopup(feed *Feed, nb, duration int, templateFunc string) {
 	// Prepare data for template formater.
 	feed.New = nb
 	feed.Plural = feed.New > 1
@@ -0,0 +1,251 @@
+package GoGmail
+
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/config"
+
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccountsFile defines the name of the default accounts file, in the user
+// applets data dir.
+//
+// Each group of the file declares a mail account, shown in a subicon with its
+// unread mails count. The group name is the account name. Example:
+//
+//   [Personal]
+//   type=gmail
+//   user=me@gmail.com
+//   password=secret
+//
+//   [Work]
+//   type=imap
+//   server=imap.example.com
+//   security=starttls
+//   folders=INBOX;Support
+//   idle=true
+//   user=me
+//   delay=5
+//   alerts=dialog;sound
+//   sound=/usr/share/sounds/freedesktop/stereo/message.oga
+//   client=thunderbird
+//
+// An account without password gets it from the applet credentials, set with
+// the "Set account" action.
+//
+// Without accounts file, the applet checks the single account set with the
+// "Set account" action.
+//
+const AccountsFile = "GoGmail_accounts.conf"
+
+// Account types.
+//
+const (
+	AccountGmail = "gmail" // Gmail Atom feed.
+	AccountIMAP  = "imap"  // IMAP server.
+)
+
+// Account alerts.
+//
+const (
+	AlertDialog    = "dialog"
+	AlertAnimation = "animation"
+	AlertSound     = "sound"
+	AlertNone      = "none"
+)
+
+// AccountConfig declares a user defined mail account.
+//
+type AccountConfig struct {
+	Name     string   `conf:"-"`        // Account name, from the group name.
+	Type     string   `conf:"type"`     // Mailbox type: gmail (default) or imap.
+	User     string   `conf:"user"`     // Login user name.
+	Password string   `conf:"password"` // Login password. Empty to use the applet credentials.
+	Icon     string   `conf:"icon"`     // Subicon image. Default is the applet icon.
+	Client   string   `conf:"client"`   // Command or location opened by a click on the subicon. Default is the applet mail client.
+	Delay    int      `conf:"delay"`    // Delay between refreshes in minutes. Default is the applet delay.
+	Alerts   []string `conf:"alerts"`   // Alerts on new mail: dialog, animation, sound or none. Default is the applet alerts.
+	Sound    string   `conf:"sound"`    // Sound file. Default is the applet sound.
+
+	// IMAP settings.
+	Server   string   `conf:"server"`   // Server address: host or host:port.
+	Security string   `conf:"security"` // Security mode: tls (default), starttls or none.
+	Folders  []string `conf:"folders"`  // Folders to check, separated by ";". Default is INBOX.
+	Idle     bool     `conf:"idle"`     // Push new mails with IDLE.
+}
+
+// LoadAccounts loads user defined accounts from a config file.
+// A missing file isn't an error, it just declares no accounts.
+//
+func LoadAccounts(log cdtype.Logger, file string) (list []AccountConfig, e error) {
+	if _, e := os.Stat(file); os.IsNotExist(e) {
+		return nil, nil
+	}
+	e = config.GetFromFile(log, file, func(cfg cdtype.ConfUpdater) {
+		cfg.ParseGroups(func(group string, _ []cdtype.ConfKeyer) {
+			if group == "DEFAULT" { // Keys before the first group.
+				return
+			}
+			ac := AccountConfig{Name: group}
+			cfg.UnmarshalGroup(&ac, group, config.GetTag)
+			list = append(list, ac)
+		})
+	})
+	return list, e
+}
+
+// security returns the IMAP security mode of the account.
+//
+func (ac AccountConfig) security() (int, error) {
+	switch strings.ToLower(ac.Security) {
+	case "", "tls":
+		return IMAPSecurityTLS, nil
+
+	case "starttls":
+		return IMAPSecuritySTARTTLS, nil
+
+	case "none":
+		return IMAPSecurityNone, nil
+	}
+	return 0, errors.New("account " + ac.Name + ": bad security: " + ac.Security)
+}
+
+// NewMailbox creates the mailbox of the account, with the display callback
+// that will receive update info.
+//
+func (ac AccountConfig) NewMailbox(onResult func(int, bool, error)) (Mailbox, error) {
+	var box Mailbox
+	switch strings.ToLower(ac.Type) {
+	case "", AccountGmail:
+		box = NewFeed(onResult)
+
+	case AccountIMAP:
+		if ac.Server == "" {
+			return nil, errors.New("account " + ac.Name + ": server missing")
+		}
+		security, e := ac.security()
+		if e != nil {
+			return nil, e
+		}
+		box = NewIMAP(onResult, ac.Server, security, ac.Folders...)
+
+	default:
+		return nil, errors.New("account " + ac.Name + ": bad type: " + ac.Type)
+	}
+
+	if ac.User != "" {
+		box.Data().SetLogin(ac.User, ac.Password)
+	}
+	return box, nil
+}
+
+//
+//-----------------------------------------------------------------[ ACCOUNT ]--
+
+// account is a mailbox with its display and polling settings.
+//
+type account struct {
+	AccountConfig
+	box   Mailbox
+	delay time.Duration // Delay between checks.
+	sub   bool          // Displayed in a subicon.
+	err   error         // Buffer for last error to prevent displaying it twice.
+
+	key    string // Credentials key of the login. Empty if set in the accounts file.
+	legacy string // Old login file, migrated to the credentials.
+
+	loading sync.Mutex // Serializes login loading and saving.
+	mu      sync.Mutex
+	checked time.Time // Last check. Zero to check on the next poll.
+	loaded  bool      // Login loaded from the credentials.
+}
+
+// valid returns true if the account has login informations, or if they are
+// not loaded yet.
+//
+func (acc *account) valid() bool {
+	return !acc.isLoaded() || acc.box.IsValid()
+}
+
+// isLoaded returns whether the login was loaded from the credentials.
+//
+func (acc *account) isLoaded() bool {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	return acc.key == "" || acc.loaded
+}
+
+// setSecret sets the mailbox login with the secret from the credentials: the
+// password for accounts of the accounts file, or username:password for the
+// applet account.
+//
+func (acc *account) setSecret(secret string) {
+	acc.mu.Lock()
+	acc.loaded = true
+	acc.mu.Unlock()
+	switch {
+	case secret == "":
+	case acc.sub:
+		acc.box.Data().SetLogin(acc.User, secret)
+	default:
+		acc.box.Data().SetLogin(splitLogin(secret))
+	}
+}
+
+// due returns whether the account must be checked by a poll started now, with
+// the given poller interval. The check time is updated if needed.
+//
+func (acc *account) due(now time.Time, interval time.Duration) bool {
+	acc.mu.Lock()
+	defer acc.mu.Unlock()
+	if !acc.checked.IsZero() && now.Sub(acc.checked) < acc.delay-interval/2 {
+		return false
+	}
+	acc.checked = now
+	return true
+}
+
+// force sets the account to be checked by the next poll.
+//
+func (acc *account) force() {
+	acc.mu.Lock()
+	acc.checked = time.Time{}
+	acc.mu.Unlock()
+}
+
+// useAlert returns whether the alert is enabled for the account, with the
+// applet setting as default.
+//
+func (acc *account) useAlert(alert string, appDefault bool) bool {
+	if len(acc.Alerts) == 0 {
+		return appDefault
+	}
+	for _, name := range acc.Alerts {
+		if strings.EqualFold(strings.TrimSpace(name), alert) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeMails returns the mails data of all accounts, newest first.
+//
+func mergeMails(accounts []*account) *Feed {
+	if len(accounts) == 1 {
+		return accounts[0].box.Data()
+	}
+	feed := &Feed{}
+	for _, acc := range accounts {
+		data := acc.box.Data()
+		feed.Total += data.Total
+		feed.Mail = append(feed.Mail, data.Mail...)
+	}
+	// Dates are UTC RFC3339 strings.
+	sort.SliceStable(feed.Mail, func(i, j int) bool { return feed.Mail[i].Issued > feed.Mail[j].Issued })
+	return feed
+}
@@ -0,0 +1,127 @@
+package GoGmail
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sqp/godock/libs/log"
+
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+const testAccounts = `
+[Personal]
+user=me@gmail.com
+password=secret
+
+[Work]
+type=imap
+server=imap.example.com
+security=starttls
+folders=INBOX;Support
+idle=true
+user=me
+password=pass:word
+delay=5
+alerts=dialog;sound
+
+[Broken]
+type=imap
+security=none
+`
+
+func TestAccounts(t *testing.T) {
+	file, e := ioutil.TempFile("", "gogmail-accounts")
+	if !assert.NoError(t, e, "TempFile") {
+		return
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(testAccounts)
+	file.Close()
+
+	logger := log.NewLog(log.Logs).SetName("GoGmail_test")
+	list, e := LoadAccounts(logger, file.Name())
+	assert.NoError(t, e, "LoadAccounts")
+	if !assert.Len(t, list, 3, "accounts") {
+		return
+	}
+	assert.Equal(t, "Work", list[1].Name, "name")
+	assert.Equal(t, []string{"INBOX", "Support"}, list[1].Folders, "folders")
+	assert.True(t, list[1].Idle, "idle")
+	assert.Equal(t, 5, list[1].Delay, "delay")
+
+	box, e := list[0].NewMailbox(nil)
+	if assert.NoError(t, e, "gmail mailbox") {
+		assert.IsType(t, &Feed{}, box, "gmail mailbox")
+		assert.True(t, box.IsValid(), "gmail login")
+	}
+
+	box, e = list[1].NewMailbox(nil)
+	if assert.NoError(t, e, "imap mailbox") {
+		imapBox := box.(*IMAP)
+		assert.Equal(t, IMAPSecuritySTARTTLS, imapBox.Security, "security")
+		assert.Equal(t, "imap.example.com:143", imapBox.address(), "default port")
+		user, password := imapBox.credentials()
+		assert.Equal(t, "me", user, "user")
+		assert.Equal(t, "pass:word", password, "password")
+	}
+
+	_, e = list[2].NewMailbox(nil)
+	assert.Error(t, e, "server missing")
+
+	list, e = LoadAccounts(logger, file.Name()+".missing")
+	assert.NoError(t, e, "missing file")
+	assert.Empty(t, list, "missing file")
+}
+
+func TestAccountPolling(t *testing.T) {
+	now := time.Now()
+	acc := &account{delay: 15 * time.Minute}
+	assert.True(t, acc.due(now, 5*time.Minute), "first check")
+	assert.False(t, acc.due(now.Add(5*time.Minute), 5*time.Minute), "too soon")
+	assert.True(t, acc.due(now.Add(15*time.Minute), 5*time.Minute), "delay over")
+	acc.force()
+	assert.True(t, acc.due(now.Add(16*time.Minute), 5*time.Minute), "forced")
+
+	assert.True(t, acc.useAlert(AlertDialog, true), "applet default")
+	acc.Alerts = []string{"dialog", " Sound"}
+	assert.True(t, acc.useAlert(AlertSound, false), "account alert")
+	assert.False(t, acc.useAlert(AlertAnimation, true), "account alert disabled")
+
+	one := &account{box: &Feed{Total: 1, Mail: []*Email{{Title: "one", Issued: "2020-05-04T10:00:00Z"}}}}
+	two := &account{box: &Feed{Total: 3, Mail: []*Email{{Title: "two", Issued: "2020-05-04T12:00:00Z"}}}}
+	feed := mergeMails([]*account{one, two})
+	assert.Equal(t, 4, feed.Total, "total")
+	if assert.Len(t, feed.Mail, 2, "mails") {
+		assert.Equal(t, "two", feed.Mail[0].Title, "newest first")
+	}
+	assert.Equal(t, one.box.Data(), mergeMails([]*account{one}), "single account")
+}
+
+func TestLoginFile(t *testing.T) {
+	file, e := ioutil.TempFile("", "gogmail-login")
+	if !assert.NoError(t, e, "TempFile") {
+		return
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(base64.StdEncoding.EncodeToString([]byte("me@gmail.com\npass:word")))
+	file.Close()
+
+	user, password, e := readLoginFile(file.Name())
+	assert.NoError(t, e, "readLoginFile")
+	assert.Equal(t, "me@gmail.com", user, "user")
+	assert.Equal(t, "pass:word", password, "password")
+
+	acc := &account{box: NewFeed(nil), key: loginKey}
+	assert.True(t, acc.valid(), "not loaded yet")
+	acc.setSecret("")
+	assert.False(t, acc.valid(), "no login")
+	acc.setSecret(user + ":" + password)
+	assert.True(t, acc.valid(), "login")
+	user, password = acc.box.(*Feed).credentials()
+	assert.Equal(t, "me@gmail.com", user, "user")
+	assert.Equal(t, "pass:word", password, "password")
+}
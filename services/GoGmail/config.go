@@ -5,8 +5,18 @@ import "github.com/sqp/godock/libs/cdtype"
 // Constants it's better not to have in conf.
 //
 const (
-	loginLocation = ".Gmail_subscription"
-	feedGmail     = "https://mail.google.com/mail/feed/atom/"
+	loginLocation     = ".Gmail_subscription"     // Old login file, migrated to the credentials.
+	loginLocationIMAP = ".Mail_IMAP_subscription" // Old login file, migrated to the credentials.
+	loginKey          = "login"                   // Credentials key of the applet account.
+	loginKeyIMAP      = "login-imap"              // Credentials key of the applet IMAP account.
+	feedGmail         = "https://mail.google.com/mail/feed/atom/"
+)
+
+// Mailbox types.
+//
+const (
+	MailboxFeed = iota // Gmail Atom feed.
+	MailboxIMAP        // IMAP server.
 )
 
 // Renderers.
@@ -42,6 +52,13 @@ type mailConf struct {
 }
 
 type groupConfig struct {
+	AccountsFile string
+	MailboxType  int
+	IMAPServer   string
+	IMAPSecurity int
+	IMAPFolders  []string
+	IMAPIdle     bool
+
 	UpdateDelay  cdtype.Duration `unit:"minute" default:"15"`
 	Renderer     string
 	DialogTimer  int
@@ -104,6 +121,15 @@ var menuFull = []int{
 	ActionRegister,
 }
 
+// Actions available in accounts menu. Displayed when accounts are set in the
+// accounts file.
+//
+var menuAccounts = []int{
+	ActionOpenClient,
+	ActionShowMails,
+	ActionCheckMail,
+}
+
 // Actions available in register menu. Displayed when account isn't set.
 //
 var menuRegister = []int{
@@ -0,0 +1,355 @@
+package GoGmail
+
+import (
+	"github.com/emersion/go-imap"        // IMAP types.
+	"github.com/emersion/go-imap/client" // IMAP client.
+
+	"github.com/sqp/godock/libs/text/gtktext" // Format text GTK.
+
+	"crypto/tls"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IMAP security modes, matching the config list order.
+//
+const (
+	IMAPSecurityTLS      = iota // Implicit TLS (IMAPS), default port 993.
+	IMAPSecuritySTARTTLS        // Plain connection upgraded with STARTTLS, default port 143.
+	IMAPSecurityNone            // Plain connection, only for local servers.
+)
+
+// IMAP settings it's better not to have in conf.
+//
+const (
+	imapTimeout     = 30 * time.Second // Commands timeout, except IDLE.
+	imapIdleRestart = 10 * time.Minute // Restart IDLE before servers drop the connection.
+	imapRetryMin    = 30 * time.Second // First delay before an IDLE reconnection.
+	imapRetryMax    = 15 * time.Minute // Maximum delay before an IDLE reconnection.
+	imapFetchMax    = 50               // Maximum number of mails detailed per folder.
+	imapNotifyDelay = 2 * time.Second  // Groups the notifications of a change.
+)
+
+// MailboxWatcher is a mailbox able to push changes, instead of waiting for the
+// next poll.
+//
+type MailboxWatcher interface {
+	// Watch starts to watch the mailbox. onChange is called when the server
+	// notifies a change, and onError when the connection is lost.
+	Watch(onChange func(), onError func(error))
+
+	// StopWatch stops watching the mailbox.
+	StopWatch()
+}
+
+// IMAP is a mailbox on an IMAP server. Unread mails are searched in the
+// configured folders, and can be pushed by the server with IDLE.
+//
+// It fills the same data as the Gmail feed, so dialogs and renderers work the
+// same way with both.
+//
+type IMAP struct {
+	*Feed // Mails data and login management.
+
+	Server    string      // Server address: host or host:port.
+	Security  int         // Security mode: IMAPSecurityTLS, IMAPSecuritySTARTTLS or IMAPSecurityNone.
+	Folders   []string    // Folders to check. INBOX if empty.
+	TLSConfig *tls.Config // Optional TLS settings. Default is to verify the server name.
+
+	mu    sync.Mutex
+	stop  chan struct{} // Closed to stop the IDLE watchers.
+	check sync.Mutex    // Notified changes can overlap a poll.
+}
+
+// NewIMAP creates a new IMAP mailbox.
+//
+func NewIMAP(onResult func(int, bool, error), server string, security int, folders ...string) *IMAP {
+	return &IMAP{
+		Feed:     NewFeed(onResult),
+		Server:   server,
+		Security: security,
+		Folders:  folders,
+	}
+}
+
+// Check callback for poller mail checking. Search unread mails in all folders
+// and launch the result callback with the mails count delta.
+//
+func (box *IMAP) Check() {
+	if !box.IsValid() {
+		box.callResult(0, false, errors.New("no account informations provided"))
+		return
+	}
+	box.check.Lock()
+	defer box.check.Unlock()
+
+	count := box.Count() // save current count.
+	box.Clear()          // reset list.
+
+	var e error
+	box.Mail, box.Total, e = box.unseen()
+
+	box.callResult(box.Count()-count, count == 0, e)
+}
+
+// unseen returns the newest unread mails of all folders, and the total count
+// of unread mails.
+//
+func (box *IMAP) unseen() (mails []*Email, total int, e error) {
+	c, e := box.dial()
+	if e != nil {
+		return nil, 0, e
+	}
+	defer c.Logout()
+
+	for _, folder := range box.folders() {
+		if _, e = c.Select(folder, true); e != nil {
+			return nil, 0, errors.New(folder + ": " + e.Error())
+		}
+
+		criteria := imap.NewSearchCriteria()
+		criteria.WithoutFlags = []string{imap.SeenFlag}
+		ids, e := c.Search(criteria)
+		if e != nil {
+			return nil, 0, errors.New(folder + ": " + e.Error())
+		}
+		total += len(ids)
+		if len(ids) == 0 {
+			continue
+		}
+
+		// Only the newest are detailed, dialogs never show more.
+		if len(ids) > imapFetchMax {
+			sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+			ids = ids[len(ids)-imapFetchMax:]
+		}
+		seqset := new(imap.SeqSet)
+		seqset.AddNum(ids...)
+
+		messages := make(chan *imap.Message, 10)
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Fetch(seqset, []imap.FetchItem{imap.FetchEnvelope, imap.FetchInternalDate}, messages)
+		}()
+		for msg := range messages {
+			mails = append(mails, newEmail(msg))
+		}
+		if e = <-done; e != nil {
+			return nil, 0, errors.New(folder + ": " + e.Error())
+		}
+	}
+
+	// Newest first, like the Gmail feed. Dates are UTC RFC3339 strings.
+	sort.SliceStable(mails, func(i, j int) bool { return mails[i].Issued > mails[j].Issued })
+	return mails, total, nil
+}
+
+// newEmail converts an IMAP message to mail data.
+//
+func newEmail(msg *imap.Message) *Email {
+	mail := &Email{}
+	date := msg.InternalDate
+	if msg.Envelope != nil {
+		mail.Title = msg.Envelope.Subject
+		if !msg.Envelope.Date.IsZero() {
+			date = msg.Envelope.Date
+		}
+		if len(msg.Envelope.From) > 0 {
+			from := msg.Envelope.From[0]
+			mail.AuthorEmail = from.Address()
+			mail.AuthorName = from.PersonalName
+			if mail.AuthorName == "" {
+				mail.AuthorName = mail.AuthorEmail
+			}
+		}
+	}
+	if !date.IsZero() {
+		mail.Issued = date.UTC().Format(time.RFC3339)
+		mail.Modified = mail.Issued
+	}
+
+	// Escape texts for GTK.
+	mail.AuthorName = gtktext.Escape(mail.AuthorName)
+	mail.Title = gtktext.Escape(mail.Title)
+	return mail
+}
+
+//
+//------------------------------------------------------------------[ WATCH ]--
+
+// Watch starts an IDLE connection on each folder. Changes notified by the
+// server call onChange, which should trigger a check. Lost connections are
+// reported with onError and reopened later.
+//
+func (box *IMAP) Watch(onChange func(), onError func(error)) {
+	box.StopWatch()
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.stop = make(chan struct{})
+	for _, folder := range box.folders() {
+		go box.watch(folder, box.stop, onChange, onError)
+	}
+}
+
+// StopWatch stops the IDLE connections.
+//
+func (box *IMAP) StopWatch() {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	if box.stop != nil {
+		close(box.stop)
+		box.stop = nil
+	}
+}
+
+// watch keeps an IDLE connection on the folder until stopped, with increasing
+// delays between reconnections.
+//
+func (box *IMAP) watch(folder string, stop chan struct{}, onChange func(), onError func(error)) {
+	delay := imapRetryMin
+	for {
+		started := time.Now()
+		e := box.idle(folder, stop, onChange)
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if time.Since(started) > imapRetryMax { // Connection was fine for a while.
+			delay = imapRetryMin
+		}
+		if e == nil {
+			e = errors.New("connection closed")
+		}
+		onError(errors.New("IMAP idle " + folder + ": " + e.Error()))
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > imapRetryMax {
+			delay = imapRetryMax
+		}
+	}
+}
+
+// idle opens a connection on the folder and waits for server notifications,
+// until stopped or disconnected.
+//
+func (box *IMAP) idle(folder string, stop chan struct{}, onChange func()) error {
+	c, e := box.dial()
+	if e != nil {
+		return e
+	}
+	updates := make(chan client.Update, 16)
+	c.Updates = updates
+	defer func() { // Drain notifications until the connection is closed.
+		closed := make(chan struct{})
+		go func() { c.Logout(); close(closed) }()
+		for {
+			select {
+			case <-updates:
+			case <-closed:
+				return
+			}
+		}
+	}()
+
+	if _, e = c.Select(folder, true); e != nil {
+		return e
+	}
+
+	c.Timeout = 0 // The IDLE command lasts until stopped.
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Idle(stop, &client.IdleOptions{LogoutTimeout: imapIdleRestart})
+	}()
+
+	var notify <-chan time.Time
+	for {
+		select {
+		case update := <-updates:
+			switch update.(type) {
+			case *client.MailboxUpdate, *client.MessageUpdate, *client.ExpungeUpdate:
+				if notify == nil { // A change is often sent as multiple updates.
+					notify = time.After(imapNotifyDelay)
+				}
+			}
+
+		case <-notify:
+			notify = nil
+			onChange()
+
+		case e = <-done:
+			return e
+		}
+	}
+}
+
+//
+//-------------------------------------------------------------[ CONNECTION ]--
+
+// dial opens a connection to the server and logs in.
+//
+func (box *IMAP) dial() (*client.Client, error) {
+	addr := box.address()
+	host, _, _ := net.SplitHostPort(addr)
+	conf := box.TLSConfig
+	if conf == nil {
+		conf = &tls.Config{ServerName: host}
+	}
+
+	var c *client.Client
+	var e error
+	dialer := &net.Dialer{Timeout: imapTimeout}
+	if box.Security == IMAPSecurityTLS {
+		c, e = client.DialWithDialerTLS(dialer, addr, conf)
+	} else {
+		c, e = client.DialWithDialer(dialer, addr)
+	}
+	if e != nil {
+		return nil, e
+	}
+	c.Timeout = imapTimeout
+
+	if box.Security == IMAPSecuritySTARTTLS {
+		if e = c.StartTLS(conf); e != nil {
+			c.Terminate()
+			return nil, errors.New("STARTTLS: " + e.Error())
+		}
+	}
+
+	user, password := box.credentials()
+	if e = c.Login(user, password); e != nil {
+		c.Logout()
+		return nil, e
+	}
+	return c, nil
+}
+
+// address returns the server address with the default port of the security
+// mode if needed.
+//
+func (box *IMAP) address() string {
+	if _, _, e := net.SplitHostPort(box.Server); e == nil {
+		return box.Server
+	}
+	if box.Security == IMAPSecurityTLS {
+		return net.JoinHostPort(box.Server, "993")
+	}
+	return net.JoinHostPort(box.Server, "143")
+}
+
+// folders returns the folders to check.
+//
+func (box *IMAP) folders() []string {
+	if len(box.Folders) == 0 {
+		return []string{"INBOX"}
+	}
+	return box.Folders
+}
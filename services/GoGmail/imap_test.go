@@ -0,0 +1,154 @@
+package GoGmail
+
+import (
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testBackend is a memory IMAP backend able to push updates to IDLE clients.
+//
+type testBackend struct {
+	*memory.Backend
+	updates chan backend.Update
+}
+
+func (be *testBackend) Updates() <-chan backend.Update { return be.updates }
+
+// addMail adds an unread mail to the INBOX.
+//
+func (be *testBackend) addMail(t *testing.T, from, subject string, date time.Time) {
+	user, e := be.Login(nil, "username", "password")
+	if !assert.NoError(t, e, "Login") {
+		t.FailNow()
+	}
+	mbox, _ := user.GetMailbox("INBOX")
+	body := "From: " + from + "\r\n" +
+		"To: username@example.org\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Date: " + date.Format(time.RFC1123Z) + "\r\n" +
+		"\r\n" +
+		"Hello"
+	e = mbox.(*memory.Mailbox).CreateMessage(nil, date, bytes.NewBufferString(body))
+	assert.NoError(t, e, "CreateMessage")
+}
+
+// notify sends a new INBOX messages count to connected clients.
+//
+func (be *testBackend) notify(messages uint32) {
+	status := imap.NewMailboxStatus("INBOX", []imap.StatusItem{imap.StatusMessages})
+	status.Messages = messages
+	be.updates <- &backend.MailboxUpdate{Update: backend.NewUpdate("username", "INBOX"), MailboxStatus: status}
+}
+
+// testServer starts a local IMAP server. The TLS mode serves IMAPS, others
+// serve a plain connection offering STARTTLS.
+//
+func testServer(t *testing.T, security int) (*testBackend, *IMAP, func()) {
+	https := httptest.NewTLSServer(http.NotFoundHandler()) // Provides a test certificate.
+	certs := x509.NewCertPool()
+	certs.AddCert(https.Certificate())
+	https.Close()
+
+	var listener net.Listener
+	var e error
+	if security == IMAPSecurityTLS {
+		listener, e = tls.Listen("tcp", "127.0.0.1:0", https.TLS)
+	} else {
+		listener, e = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if !assert.NoError(t, e, "Listen") {
+		t.FailNow()
+	}
+
+	be := &testBackend{Backend: memory.New(), updates: make(chan backend.Update, 1)}
+	srv := server.New(be)
+	srv.AllowInsecureAuth = security == IMAPSecurityNone
+	if security == IMAPSecuritySTARTTLS {
+		srv.TLSConfig = https.TLS
+	}
+	go srv.Serve(listener)
+
+	box := NewIMAP(nil, listener.Addr().String(), security)
+	box.TLSConfig = &tls.Config{RootCAs: certs, ServerName: "example.com"}
+	return be, box, func() { srv.Close() }
+}
+
+func TestIMAP(t *testing.T) {
+	for _, security := range []int{IMAPSecurityTLS, IMAPSecuritySTARTTLS, IMAPSecurityNone} {
+		be, box, stop := testServer(t, security)
+
+		var delta int
+		var first bool
+		var err error
+		box.callResult = func(d int, f bool, e error) { delta, first, err = d, f, e }
+
+		box.Check()
+		assert.Error(t, err, "no login")
+
+		box.SetLogin("username", "wrong")
+		box.Check()
+		assert.Error(t, err, "bad login")
+
+		box.SetLogin("username", "password")
+		box.Check()
+		assert.NoError(t, err, "Check")
+		assert.True(t, first, "first check")
+		assert.Equal(t, 0, box.Count(), "mail seen by default")
+
+		date := time.Date(2020, 5, 4, 12, 0, 0, 0, time.UTC)
+		be.addMail(t, "Old <old@example.org>", "old <news>", date)
+		be.addMail(t, "new@example.org", "new", date.Add(time.Hour))
+
+		box.Check()
+		assert.NoError(t, err, "Check")
+		assert.Equal(t, 2, delta, "delta")
+		if assert.Equal(t, 2, box.Count(), "count") && assert.Len(t, box.Data().Mail, 2, "mails") {
+			assert.Equal(t, "new", box.Mail[0].Title, "newest first")
+			assert.Equal(t, "new@example.org", box.Mail[0].AuthorName, "name from address")
+			assert.Equal(t, "old &lt;news&gt;", box.Mail[1].Title, "escaped")
+			assert.Equal(t, "Old", box.Mail[1].AuthorName, "name")
+			assert.Equal(t, "old@example.org", box.Mail[1].AuthorEmail, "email")
+			assert.Equal(t, "2020-05-04T12:00:00Z", box.Mail[1].Issued, "date")
+		}
+
+		box.Folders = []string{"INBOX", "Missing"}
+		box.Check()
+		assert.Error(t, err, "missing folder")
+
+		stop()
+	}
+}
+
+func TestIMAPWatch(t *testing.T) {
+	be, box, stop := testServer(t, IMAPSecurityNone)
+	defer stop()
+	box.SetLogin("username", "password")
+
+	changed := make(chan struct{}, 10)
+	box.Watch(
+		func() { changed <- struct{}{} },
+		func(e error) { t.Error("watch:", e) },
+	)
+	defer box.StopWatch()
+
+	time.Sleep(200 * time.Millisecond) // Let the client start to idle.
+	be.notify(2)
+
+	select {
+	case <-changed:
+	case <-time.After(imapNotifyDelay + 5*time.Second):
+		t.Fatal("no change notified")
+	}
+}
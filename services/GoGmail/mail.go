@@ -22,8 +22,9 @@ type Mailbox interface {
 	IsValid() bool
 	Count() (nbInbox int)
 	Clear()
-	LoadLogin(filepath string)
-	SaveLogin(login string)
+
+	// Data returns the mails data, used by dialog templates.
+	Data() *Feed
 }
 
 // RendererMail is a display interface to show inbox mail count on the icon.
@@ -67,7 +68,6 @@ type Feed struct {
 
 	// Mail polling data.
 	login      string                 // Login informations.
-	file       string                 // Login file location.
 	restart    chan bool              // restart channel to forward user requests.
 	callResult func(int, bool, error) // Action to execute to send polling results.
 }
@@ -91,6 +91,12 @@ func (feed *Feed) Clear() {
 	feed.Total = 0
 }
 
+// Data returns the mails data.
+//
+func (feed *Feed) Data() *Feed {
+	return feed
+}
+
 // IsValid return true is user informations were provided.
 // Only tells if login and password were submitted, not if they are valid.
 //
@@ -127,28 +133,44 @@ func (feed *Feed) Check() {
 	feed.callResult(feed.Count()-count, count == 0, e)
 }
 
-// LoadLogin get user login information from file.
+// credentials returns the user name and password of the login.
+//
+func (feed *Feed) credentials() (user, password string) {
+	t, _ := base64.StdEncoding.DecodeString(feed.login)
+	return splitLogin(string(t))
+}
+
+// SetLogin sets the login informations.
 //
-func (feed *Feed) LoadLogin(filename string) {
-	feed.file = filename
-	b, err := ioutil.ReadFile(feed.file)
-	if err == nil {
-		t, e2 := base64.StdEncoding.DecodeString(string(b))
-		if e2 == nil {
-			split := strings.Split(string(t), "\n")
-			feed.login = base64.StdEncoding.EncodeToString([]byte(split[0] + ":" + split[1]))
-		}
+func (feed *Feed) SetLogin(user, password string) {
+	feed.login = base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+}
+
+// splitLogin splits a login in the format username:password.
+//
+func splitLogin(login string) (user, password string) {
+	split := strings.SplitN(login, ":", 2)
+	if len(split) < 2 {
+		return split[0], ""
 	}
+	return split[0], split[1]
 }
 
-// SaveLogin login informations to file with the same format as the Gmail applet.
+// readLoginFile reads the old login file, with the same format as the Gmail
+// applet: base64 of "username\npassword".
 //
-func (feed *Feed) SaveLogin(login string) {
-	if login == "" {
-		return
+func readLoginFile(filename string) (user, password string, e error) {
+	b, e := ioutil.ReadFile(filename)
+	if e != nil {
+		return "", "", e
+	}
+	t, e := base64.StdEncoding.DecodeString(string(b))
+	if e != nil {
+		return "", "", e
+	}
+	split := strings.SplitN(string(t), "\n", 2)
+	if len(split) < 2 {
+		return "", "", errors.New("bad login file: " + filename)
 	}
-	str := []byte(strings.Replace(login, ":", "\n", 1))
-	coded := base64.StdEncoding.EncodeToString(str)
-	ioutil.WriteFile(feed.file, []byte(coded), 0600)
-	feed.LoadLogin(feed.file)
+	return split[0], split[1], nil
 }
@@ -26,9 +26,10 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.SetConfig(&app.conf)
 
 	// Events.
-	events.OnClick = app.Command().Callback(cmdLeft) // Left and middle click: launch the configured action.
-	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnClick = func() { app.clickAction(topClickLeft, cmdLeft) } // Left and middle click: launch the configured action.
+	events.OnMiddleClick = func() { app.clickAction(topClickMiddle, cmdMiddle) }
 	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		menu.AddEntry("Top processes", "utilities-system-monitor", func() { go app.showTop() })
 		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
 			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
 		}
@@ -41,6 +42,8 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	app.Poller().Add(app.GetMemActivity)
 
 	app.service.App = app
+	app.service.Log = app.Log()
+	app.service.Alert = sysinfo.NewAlert(app)
 	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
 		cdtype.InfoNone:    {},
 		cdtype.InfoOnIcon:  {Sep: "\n", ShowPre: false},
@@ -54,6 +57,8 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Settings for service.
 	app.service.Settings(app.conf.DisplayText, app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName)
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
 	app.service.SetSize(countTrue(app.conf.ShowRAM, app.conf.ShowSwap))
 
 	// Defaults.
@@ -64,6 +69,28 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	}
 }
 
+// clickAction shows the top processes dialog if it's set for this click, or
+// launches the configured command.
+//
+func (app *Applet) clickAction(click, cmd int) {
+	if app.conf.TopClick == click {
+		go app.showTop() // Threaded as data collection can take some time.
+		return
+	}
+	app.Command().Launch(cmd)
+}
+
+// showTop shows the dialog with the processes using the most memory.
+//
+func (app *Applet) showTop() {
+	list, e := sysinfo.TopMemory(app.conf.TopCount)
+	if app.Log().Err(e, "top processes") {
+		return
+	}
+	e = sysinfo.PopupProcesses(app, app.Translate("Top processes by memory usage"), list, sysinfo.FormatMemory)
+	app.Log().Err(e, "popup top processes")
+}
+
 //
 //---------------------------------------------------------------------[ MEM ]--
 
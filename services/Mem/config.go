@@ -1,6 +1,9 @@
 package Mem
 
-import "github.com/sqp/godock/libs/cdtype"
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+)
 
 // Commands references.
 const (
@@ -8,12 +11,20 @@ const (
 	cmdMiddle
 )
 
+// Top processes dialog click options.
+const (
+	topClickNone = iota
+	topClickLeft
+	topClickMiddle
+)
+
 //------------------------------------------------------------------[ CONFIG ]--
 
 type appletConf struct {
 	cdtype.ConfGroupIconBoth `group:"Icon"`
 	groupConfiguration       `group:"Configuration"`
 	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
 }
 
 type groupConfiguration struct {
@@ -30,6 +41,7 @@ type groupConfiguration struct {
 	// GraphMix bool
 
 	UpdateDelay cdtype.Duration `default:"3"`
+	RecordSize  int
 	ShowRAM     bool
 	ShowSwap    bool
 }
@@ -40,4 +52,7 @@ type groupActions struct {
 	LeftClass     string
 	MiddleAction  int
 	MiddleCommand string
+
+	TopClick int
+	TopCount int `default:"10"`
 }
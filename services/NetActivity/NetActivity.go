@@ -28,7 +28,10 @@ import (
 	"github.com/sqp/godock/libs/text/bytesize" // Human readable bytes.
 
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
 //
@@ -43,8 +46,13 @@ type Applet struct {
 
 	conf    *appletConf
 	service *sysinfo.IOActivity
+	traffic *sysinfo.Traffic // Cumulative traffic counters. nil when disabled.
 	up      *uptoshare.Uploader
 	video   videodl.Downloader
+
+	quotaWarned bool       // True when the quota exceeded dialog was shown for the period.
+	uploading   bool       // True when the upload progress is rendered on the icon.
+	renderMu    sync.Mutex // Data renderer owner: the poller, or the upload progress.
 }
 
 // NewApplet creates a new applet instance.
@@ -57,7 +65,12 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	events.OnClick = app.Command().Callback(cmdLeft)
 	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
 	events.OnBuildMenu = app.buildMenu
-	events.End = func() { app.video.WebUnregister() }
+	events.End = func() {
+		app.video.WebUnregister()
+		if app.traffic != nil {
+			app.Log().Err(app.traffic.Save(), "save traffic")
+		}
+	}
 
 	events.OnDropData = func(data string) {
 		if strings.HasPrefix(data, "http://") || strings.HasPrefix(data, "https://") {
@@ -72,18 +85,27 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 	// Uptoshare actions
 	app.up = uptoshare.New()
 	app.up.Log = app.Log()
-	app.up.SetPreCheck(func() { app.SetEmblem(app.FileLocation("icon"), EmblemAction) })
-	app.up.SetPostCheck(func() { app.SetEmblem("none", EmblemAction) })
+	app.up.SetPreCheck(func() {
+		app.SetEmblem(app.FileLocation("icon"), EmblemAction)
+		app.showProgress(true)
+	})
+	app.up.SetPostCheck(func() {
+		app.SetEmblem("none", EmblemAction)
+		app.showProgress(false)
+	})
 	app.up.SetOnResult(app.onUploadDone)
+	app.up.SetOnProgress(app.onUploadProgress)
 
 	// Network activity actions.
 	app.service = sysinfo.NewIOActivity(app)
 	app.service.Log = app.Log()
 	app.service.FormatIcon = sysinfo.FormatIcon
 	app.service.FormatLabel = formatLabel
-	app.service.GetData = sysinfo.GetNetActivity
+	app.service.GetData = app.getNetActivity
+	app.service.Alert = sysinfo.NewAlert(app)
+	app.service.Alert.Scale = 1. / 1024 // Bytes to KiB.
 
-	app.Poller().Add(app.service.Check)
+	app.Poller().Add(app.checkActivity)
 
 	// Video download actions.
 	ActionsVideoDL := 0
@@ -111,18 +133,45 @@ func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	app.up.LimitRate = app.conf.UploadRateLimit
 	app.up.PostAnonymous = app.conf.PostAnonymous
 	app.up.FileForAll = app.conf.FileForAll
+	e := app.up.SetHostsFile(app.FileDataDir(cdglobal.DirUserAppData, uptoshare.HostsFile))
+	app.Log().Err(e, "load upload hosts")
 	app.up.SiteFile(app.conf.SiteFile)
 	app.up.SiteImage(app.conf.SiteImage)
 	app.up.SiteText(app.conf.SiteText)
 	app.up.SiteVideo(app.conf.SiteVideo)
+	app.setTransforms()
 
 	// Video download settings.
 	app.video.SetConfig(&app.conf.Config)
 	app.video.SetEnabledWeb(videodl.WebState(app.conf.EnabledWeb))
 
 	// Settings for poller and IOActivity (force renderer reset in case of reload).
+	renderer := app.conf.DisplayValues
+	if app.quotaGauge() {
+		renderer = -1 // Rates aren't rendered, the gauge is used by the quota.
+	}
+	app.renderMu.Lock()
 	app.service.Settings(uint64(app.conf.UpdateDelay.Value()), app.conf.DisplayText,
-		app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName, app.conf.Devices...)
+		renderer, app.conf.GraphType, app.conf.GaugeName, app.conf.Devices...)
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
+
+	// Traffic counters settings.
+	switch {
+	case !app.conf.TrafficEnabled:
+		app.traffic = nil
+
+	case app.traffic == nil:
+		app.traffic = sysinfo.NewTraffic(app)
+	}
+	if app.traffic != nil {
+		app.traffic.ResetDay = app.conf.QuotaResetDay
+	}
+	if app.quotaGauge() && !app.uploading {
+		app.DataRenderer().Gauge(1, app.conf.GaugeName)
+	}
+	app.quotaWarned = false
+	app.renderMu.Unlock()
 
 	// Defaults.
 	def.PollerInterval = app.conf.UpdateDelay.Value()
@@ -150,26 +199,95 @@ func (app *Applet) buildMenu(menu cdtype.Menuer) {
 	if needSep {
 		menu.AddSeparator()
 	}
+	if app.traffic != nil {
+		menu.AddEntry("Network traffic", "network-transmit-receive", app.showTraffic)
+	}
 	subup := menu.AddSubMenu("Upload", "")
+	uploads := app.up.Uploads()
+	for _, item := range uploads {
+		item := item
+		if item.Error != "" {
+			subup.AddEntry("Resume: "+item.Name, "view-refresh", func() {
+				app.Log().GoTry(func() { app.Log().Err(app.up.Resume(item.ID), "resume upload") })
+			})
+			subup.AddEntry("Drop: "+item.Name, "edit-delete", func() { app.Log().Err(app.up.Cancel(item.ID), "drop upload") })
+			continue
+		}
+		label := fmt.Sprintf("Cancel: %s (%d%%)", item.Name, int(item.Fraction()*100))
+		subup.AddEntry(label, "process-stop", func() { app.Log().Err(app.up.Cancel(item.ID), "cancel upload") })
+	}
+	if len(uploads) > 0 {
+		subup.AddSeparator()
+	}
+	now := time.Now()
 	for _, hist := range app.up.ListHistory() {
 		hist := hist
-		subup.AddEntry(hist["file"], "", func() {
+		label := hist["file"]
+		if label == "" { // Text.
+			label = hist["link"]
+		}
+		if hist.Expired(now) {
+			label += " (expired)"
+		}
+		subhist := subup.AddSubMenu(label, "")
+		subhist.AddEntry("Copy link", "edit-copy", func() {
 			app.Log().Info(hist["link"])
 			clipboard.Write(hist["link"])
 			// app.ShowDialog(link, 5)
 		})
+		switch {
+		case hist.Expired(now):
+
+		case app.up.CanDelete(hist):
+			subhist.AddEntry("Delete remote copy", "edit-delete", func() {
+				app.Log().GoTry(func() { app.Log().Err(app.up.Delete(hist["link"]), "delete upload") })
+			})
+
+		case strings.HasPrefix(hist[uptoshare.LinkDelete], "http"): // Deletion page.
+			subhist.AddEntry("Open delete page", "edit-delete", func() {
+				app.Log().ExecAsync(cdglobal.CmdOpen, hist[uptoshare.LinkDelete])
+			})
+		}
 	}
 
 	menu.AddSeparator()
 	app.video.Menu(menu)
 }
 
+// setTransforms sets the content changes applied before uploads.
+//
+func (app *Applet) setTransforms() {
+	var image []uptoshare.Transform
+	if app.conf.StripMetadata {
+		image = append(image, uptoshare.StripMetadata())
+	}
+	if app.conf.ImageMaxSize > 0 {
+		image = append(image, uptoshare.ResizeImage(app.conf.ImageMaxSize, app.conf.ImageMaxSize))
+	}
+
+	for _, typ := range []uptoshare.FileType{uptoshare.FileTypeFile, uptoshare.FileTypeImage, uptoshare.FileTypeText, uptoshare.FileTypeVideo} {
+		var list []uptoshare.Transform
+		if typ == uptoshare.FileTypeImage {
+			list = image
+		}
+		if app.conf.EncryptUploads {
+			list = append(list, uptoshare.Encrypt())
+		}
+		app.up.SetTransforms(typ, list...)
+	}
+}
+
 //
 //-----------------------------------------------------------[ PUBLIC REMOTE ]--
 
 // UploadFiles uploads data to a one-click site: file location or text.
+// Multiple files are sent as one archive if enabled.
 //
 func (app *Applet) UploadFiles(files ...string) {
+	if app.conf.UploadArchive && len(files) > 1 {
+		app.uploadConfirm(strings.Join(files, "\n"))
+		return
+	}
 	for _, file := range files {
 		app.uploadConfirm(file)
 	}
@@ -213,10 +331,25 @@ func (app *Applet) UpToShareLastLink() string {
 	return hists[0]["link"]
 }
 
-// UpToShareLinks gets all links of items sent to one-click hosting services.
+// UpToShareLinks gets links of items sent to one-click hosting services,
+// matching the query (see uptoshare.ParseQuery). Newest first.
+//
+func (app *Applet) UpToShareLinks(query map[string]string) ([]map[string]string, error) {
+	q, e := uptoshare.ParseQuery(query)
+	if e != nil {
+		return nil, e
+	}
+	var list []map[string]string
+	for _, links := range app.up.Search(q) {
+		list = append(list, links)
+	}
+	return list, nil
+}
+
+// UpToShareDelete deletes an uploaded file from its hosting service.
 //
-func (app *Applet) UpToShareLinks() []uptoshare.Links {
-	return app.up.ListHistory()
+func (app *Applet) UpToShareDelete(link string) error {
+	return app.up.Delete(link)
 }
 
 // DownloadVideo downloads the video from url.
@@ -252,6 +385,142 @@ func (app *Applet) onUploadDone(links uptoshare.Links) {
 	}
 }
 
+// checkActivity updates the network activity on the poller loop.
+//
+func (app *Applet) checkActivity() {
+	app.renderMu.Lock()
+	defer app.renderMu.Unlock()
+	app.service.Check()
+}
+
+// onUploadProgress renders the upload progress on the icon.
+// Called by the upload goroutine.
+//
+func (app *Applet) onUploadProgress(p uptoshare.Progress) {
+	app.renderMu.Lock()
+	defer app.renderMu.Unlock()
+	if app.uploading {
+		app.Log().Err(app.DataRenderer().Render(p.Fraction()), "render upload progress")
+	}
+	app.Log().Debug("upload", p.Name, p.Format())
+}
+
+// showProgress sets the icon data renderer for the upload progress, or sets
+// back the activity or quota renderer. Called by the upload goroutine.
+//
+func (app *Applet) showProgress(show bool) {
+	app.renderMu.Lock()
+	defer app.renderMu.Unlock()
+	if show == app.uploading || (show && !app.conf.UploadProgress) {
+		return
+	}
+	app.uploading = show
+	if show {
+		app.service.HoldRenderer(true)
+		app.Log().Err(app.DataRenderer().Progress(1), "set upload progress")
+		return
+	}
+	app.DataRenderer().Remove()
+	app.service.HoldRenderer(false)
+	if app.quotaGauge() {
+		app.DataRenderer().Gauge(1, app.conf.GaugeName)
+	}
+}
+
+//
+//-----------------------------------------------------------------[ TRAFFIC ]--
+
+// getNetActivity gets network counters for the activity monitor, and updates
+// the traffic counters if enabled.
+//
+func (app *Applet) getNetActivity() ([]sysinfo.Value, error) {
+	values, e := sysinfo.GetNetActivity()
+	if e != nil || app.traffic == nil {
+		return values, e
+	}
+	app.Log().Err(app.traffic.Update(values, time.Now()), "save traffic")
+	app.checkQuota()
+	return values, nil
+}
+
+// quotaGauge returns whether the quota usage is rendered on the icon gauge.
+//
+func (app *Applet) quotaGauge() bool {
+	return app.conf.TrafficEnabled && app.conf.QuotaSize > 0 && app.conf.QuotaGauge
+}
+
+// quota returns the traffic quota in bytes, and the traffic used for the quota
+// period on the monitored interfaces.
+//
+func (app *Applet) quota() (quota, used uint64) {
+	quota = uint64(app.conf.QuotaSize) * 1024 * 1024
+	used = app.traffic.Used(app.conf.QuotaPeriod, app.conf.Devices...)
+	return quota, used
+}
+
+// checkQuota renders the quota usage and warns the user when it's exceeded.
+// Called by the poller, with the renderer lock held.
+//
+func (app *Applet) checkQuota() {
+	if app.conf.QuotaSize <= 0 {
+		return
+	}
+	quota, used := app.quota()
+	if app.quotaGauge() && !app.uploading {
+		value := float64(used) / float64(quota)
+		if value > 1 {
+			value = 1
+		}
+		app.Log().Err(app.DataRenderer().Render(value), "render quota")
+	}
+
+	switch {
+	case used <= quota:
+		app.quotaWarned = false // New period started, or quota changed.
+
+	case !app.quotaWarned:
+		app.quotaWarned = true
+		msg := fmt.Sprintf("%s\n%s / %s", app.Translate("Network quota exceeded"), bytesize.ByteSize(used), bytesize.ByteSize(quota))
+		app.Log().Err(app.ShowDialog(msg, dialogTrafficDuration), "quota dialog")
+	}
+}
+
+// showTraffic shows a dialog with the traffic counters of each interface.
+//
+func (app *Applet) showTraffic() {
+	var names []string
+	for name := range app.traffic.Devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		dev := app.traffic.Devices[name]
+		lines = append(lines, fmt.Sprintf("%s:  %s %s  %s %s  %s %s", name,
+			app.Translate("day"), formatTraffic(dev.Day),
+			app.Translate("month"), formatTraffic(dev.Month),
+			app.Translate("total"), formatTraffic(dev.Total)))
+	}
+
+	if app.conf.QuotaSize > 0 {
+		quota, used := app.quota()
+		remain := uint64(0)
+		if used < quota {
+			remain = quota - used
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s / %s (%s %s)", app.Translate("Quota"),
+			bytesize.ByteSize(used), bytesize.ByteSize(quota), bytesize.ByteSize(remain), app.Translate("remaining")))
+	}
+	app.Log().Err(app.ShowDialog(strings.Join(lines, "\n"), dialogTrafficDuration), "traffic dialog")
+}
+
+// formatTraffic formats a traffic counter. Format="↓ 42 MB / ↑ 12 MB".
+//
+func formatTraffic(tc sysinfo.TrafficCount) string {
+	return fmt.Sprintf("%s %s / %s %s", "↓", bytesize.ByteSize(tc.In), "↑", bytesize.ByteSize(tc.Out))
+}
+
 //
 //-----------------------------------------------------------------[ DISPLAY ]--
 
@@ -2,6 +2,7 @@ package NetActivity
 
 import "github.com/sqp/godock/libs/cdtype"
 import "github.com/sqp/godock/libs/net/videodl"
+import "github.com/sqp/godock/libs/sysinfo"
 
 const (
 	// EmblemAction is the position of the "upload in progress" emblem.
@@ -11,6 +12,9 @@ const (
 	EmblemDownload = cdtype.EmblemTopLeft
 )
 
+// dialogTrafficDuration is the display duration of traffic dialogs, in seconds.
+const dialogTrafficDuration = 15
+
 // Commands references.
 const (
 	cmdLeft = iota
@@ -27,6 +31,7 @@ type appletConf struct {
 	videodl.Config           `group:"VideoDL"`
 	videodl.Commands         `group:"Actions"`
 	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
 }
 
 type groupConfig struct {
@@ -44,7 +49,14 @@ type groupConfig struct {
 
 	MonitoringEnabled bool
 	UpdateDelay       cdtype.Duration `default:"3"`
+	RecordSize        int
 	Devices           []string
+
+	TrafficEnabled bool
+	QuotaSize      int // In MiB.
+	QuotaPeriod    sysinfo.TrafficPeriod
+	QuotaResetDay  int
+	QuotaGauge     bool
 }
 
 type groupUpload struct {
@@ -54,6 +66,7 @@ type groupUpload struct {
 	DialogDuration  int
 	UploadHistory   int
 	UploadRateLimit int
+	UploadProgress  bool
 
 	FileForAll    bool
 	SiteText      string
@@ -61,6 +74,11 @@ type groupUpload struct {
 	SiteVideo     string
 	SiteFile      string
 	PostAnonymous bool
+
+	UploadArchive  bool
+	ImageMaxSize   int
+	StripMetadata  bool
+	EncryptUploads bool
 }
 
 type groupVideo struct {
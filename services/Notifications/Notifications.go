@@ -2,15 +2,21 @@
 //
 // requires a hacked version of the dbus api (that wont stop after eavesdropping a message).
 //
+// The history is saved in the applet data dir, grouped by sender for display.
+// Actions of notifications are recorded, but can't be invoked from the history:
+// clients only accept the ActionInvoked signal from the notification server.
+//
+// In server mode, the applet owns the org.freedesktop.Notifications name and
+// displays notifications as dialogs from its icon, so the dock can be the
+// notification daemon on minimal desktops. Actions of notifications still
+// open are listed in the history.
+//
 package Notifications
 
 // https://developer.gnome.org/notification-spec/
 
 import (
-	"github.com/godbus/dbus"
-
-	"github.com/sqp/godock/libs/cdtype"             // Applet types.
-	"github.com/sqp/godock/libs/srvdbus/dbuscommon" // EavesDrop
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
 
 	"strconv"
 	"strings"
@@ -26,14 +32,17 @@ func init() { cdtype.Applets.Register("Notifications", NewApplet) }
 type Applet struct {
 	cdtype.AppBase // Applet base and dock connection.
 
-	conf   *appletConf
-	notifs *Notifs
+	conf    *appletConf
+	notifs  *Notifs
+	server  *Server // Set in server mode.
+	started bool
 }
 
 // NewApplet creates a new applet instance.
 //
 func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
-	app := &Applet{AppBase: base, notifs: &Notifs{log: base.Log()}}
+	app := &Applet{AppBase: base}
+	app.notifs = NewNotifs(app, base.Log())
 	app.SetConfig(&app.conf, app.actions()...)
 
 	// Events.
@@ -43,8 +52,7 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 
 	// Notifs.
 	app.notifs.SetOnCount(app.UpdateCount)
-	e := app.notifs.Start()
-	app.Log().Err(e, "notifications listener")
+	app.notifs.SetOnNew(app.onNew)
 
 	return app
 }
@@ -54,11 +62,51 @@ func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
 func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
 	// Set notification service config.
 	app.notifs.NotifConfig = app.conf.NotifConfig
+	if app.server != nil {
+		app.server.Duration = app.conf.PopupDuration
+	}
 
 	// New message icon.
 	if app.conf.NotifAltIcon == "" {
 		app.conf.NotifAltIcon = app.FileLocation(defaultNotifAltIcon)
 	}
+	app.UpdateCount(len(app.notifs.List())) // History loaded.
+
+	switch {
+	case !app.started:
+		app.started = true
+		app.start()
+
+	case app.conf.ServerMode != (app.server != nil):
+		app.Log().Info("restart the applet to change the notifications mode")
+	}
+}
+
+// start starts the notification server in server mode, or listens to the
+// notifications sent to the active server. Falls back to listening if another
+// server owns the name.
+//
+func (app *Applet) start() {
+	if app.conf.ServerMode {
+		srv := NewServer(app.notifs, app.Log())
+		srv.OnShow = app.showBubble
+		srv.OnHide = app.hideBubble
+		srv.Duration = app.conf.PopupDuration
+		ok, e := srv.Connect()
+		switch {
+		case app.Log().Err(e, "notifications server"):
+
+		case !ok:
+			app.Log().Info("notifications server already active, listening only")
+
+		default:
+			app.server = srv
+			return
+		}
+	}
+
+	e := app.notifs.Start()
+	app.Log().Err(e, "notifications listener")
 }
 
 //
@@ -83,10 +131,28 @@ func (app *Applet) actions() []*cdtype.Action {
 			Icon:     "edit-clear",
 			Call:     app.notifs.Clear,
 			Threaded: true,
+		}, {
+			ID:   ActionDoNotDisturb,
+			Name: "Do not disturb",
+			Menu: cdtype.MenuCheckBox,
+			Call: app.actionToggleDoNotDisturb,
 		},
 	}
 }
 
+// actionToggleDoNotDisturb toggles the do not disturb mode, and saves it to
+// the config.
+//
+func (app *Applet) actionToggleDoNotDisturb() {
+	app.conf.DoNotDisturb = !app.conf.DoNotDisturb
+	cu, e := app.UpdateConfig()
+	if app.Log().Err(e, "UpdateConfig") {
+		return
+	}
+	cu.Set("Configuration", "DoNotDisturb", app.conf.DoNotDisturb)
+	app.Log().Err(cu.Save(), "UpdateConfig")
+}
+
 //
 //-----------------------------------------------------------------[ DISPLAY ]--
 
@@ -108,167 +174,126 @@ func (app *Applet) UpdateCount(count int) {
 	app.SetIcon(icon)
 }
 
+// displayAll shows the history dialog. In server mode, actions of notifications
+// still open are proposed in a list, and the dialog replaces the displayed one.
+//
 func (app *Applet) displayAll() {
 	var msg string
-	messages := app.notifs.List()
-	if len(messages) == 0 {
+	groups := app.notifs.Groups()
+	if len(groups) == 0 {
 		msg = "No recent notifications"
 
 	} else {
-		text, e := app.conf.DialogTemplate.ToString("ListNotif", messages)
+		text, e := app.conf.DialogTemplate.ToString("ListNotif", groups)
 		app.Log().Err(e, "template")
 		msg = strings.TrimRight(text, "\n")
 	}
 
-	app.PopupDialog(cdtype.DialogData{
+	data := cdtype.DialogData{
 		Message:    msg,
 		UseMarkup:  true,
 		Buttons:    "edit-clear;cancel",
 		TimeLength: app.conf.DialogDuration,
 		Callback:   cdtype.DialogCallbackValidNoArg(app.Action().Callback(ActionClear)), // Clear notifs if the user press the 1st button.
-	})
-
-	// if self.config['clear'] else 4 + len(msg)/40 }  // if we're going to clear the history, show the dialog until the user closes it
-}
-
-//
-//-----------------------------------------------------------[ NOTIFICATIONS ]--
-
-// Notif defines a single Dbus notification.
-//
-type Notif struct {
-	Sender, Icon, Title, Content string
-	duration, ID                 uint32
-}
-
-// Notifs handles Dbus notifications management.
-//
-type Notifs struct {
-	NotifConfig
-
-	C chan *dbus.Message
-
-	messages  []*Notif
-	callCount func(int)
-	log       cdtype.Logger
-}
-
-// NotifConfig defines the notification service configuration.
-//
-type NotifConfig struct {
-	MaxSize   int
-	Blacklist []string
-}
-
-const match = "type='method_call',path='/org/freedesktop/Notifications',member='Notify',eavesdrop='true'"
-
-// List returns the list of notifications.
-//
-func (notifs *Notifs) List() []*Notif {
-	return notifs.messages
-}
-
-// Clear resets the list of notifications.
-//
-func (notifs *Notifs) Clear() {
-	notifs.messages = nil
-	if notifs.callCount != nil {
-		notifs.callCount(len(notifs.messages))
 	}
-}
 
-// Add a new notifications to the list.
-//
-func (notifs *Notifs) Add(newtif *Notif) {
-	if newtif == nil {
-		return
+	var actions []NotifAction
+	if app.server != nil {
+		actions = app.server.Actions()
+		app.server.Hidden()
 	}
-
-	for _, ignore := range notifs.Blacklist {
-		if newtif.Sender == ignore {
-			return
+	if len(actions) > 0 {
+		var labels []string
+		for _, act := range actions {
+			labels = append(labels, act.Notif.Title+": "+act.Label)
 		}
-	}
-
-	if !notifs.replace(newtif) {
-		notifs.messages = append(notifs.messages, newtif)
-		if len(notifs.messages) > notifs.MaxSize {
-			notifs.messages = notifs.messages[len(notifs.messages)-notifs.MaxSize:]
+		data.Buttons = "ok;edit-clear;cancel"
+		data.Widget = cdtype.DialogWidgetList{Values: labels}
+		data.Callback = func(clickedButton int, answer interface{}) {
+			id, ok := answer.(int)
+			switch {
+			case clickedButton == 1:
+				app.Action().Launch(ActionClear)
+
+			case ok && id >= 0 && id < len(actions) && (clickedButton == cdtype.DialogButtonFirst || clickedButton == cdtype.DialogKeyEnter):
+				act := actions[id]
+				app.Log().Err(app.server.Invoke(act.Notif.ID, act.Key), "invoke action", act.Key)
+			}
 		}
 	}
-
-	if notifs.callCount != nil {
-		notifs.callCount(len(notifs.messages))
-	}
+	app.Log().Err(app.PopupDialog(data), "popup")
 }
 
-// try to replace an old notification (same id). Return true if replaced.
+// onNew shows the new notification in a dialog if enabled, unless in do not
+// disturb mode. The notification is recorded anyway.
 //
-func (notifs *Notifs) replace(newtif *Notif) bool {
-	// removed for now, ID was always 0.
-	// for i, oldtif := range notifs.messages {
-	// if oldtif.ID == newtif.ID {
-
-	// 	// TODO:REMOVE !!!
-	// 	println("replaced", oldtif.ID, newtif.ID)
-
-	// 	notifs.messages[i] = newtif
-	// 	return true
-	// }
-	// }
-	return false
-}
-
-// SetOnCount sets the callback for notifications count change.
+// In server mode, notifications are displayed by showBubble.
 //
-func (notifs *Notifs) SetOnCount(call func(int)) {
-	notifs.callCount = call
+func (app *Applet) onNew(notif *Notif) {
+	if !app.conf.Popup || app.conf.DoNotDisturb || app.server != nil {
+		return
+	}
+	text, e := app.conf.DialogTemplate.ToString("PopupNotif", notif)
+	if app.Log().Err(e, "template") {
+		return
+	}
+	app.PopupDialog(cdtype.DialogData{
+		Message:    strings.TrimRight(text, "\n"),
+		Icon:       notif.Icon,
+		UseMarkup:  true,
+		TimeLength: app.conf.PopupDuration,
+	})
 }
 
-// Start the message eavesdropping loop and forward notifs changes to the callback.
+// showBubble displays a notification received in server mode, as a dialog
+// from the icon, unless in do not disturb mode. Actions are proposed in a
+// list. Closing the dialog dismisses the notification.
 //
-func (notifs *Notifs) Start() error {
-	var e error
-	notifs.C, e = dbuscommon.EavesDrop(match)
-	if e != nil {
-		return e
+// The dialog replaces the one displayed, and expires with the notification.
+// Returns false if it wasn't displayed.
+//
+func (app *Applet) showBubble(notif *Notif, expire int) bool {
+	if app.conf.DoNotDisturb {
+		return false
+	}
+	text, e := app.conf.DialogTemplate.ToString("PopupNotif", notif)
+	if app.Log().Err(e, "template") {
+		return false
+	}
+	data := cdtype.DialogData{
+		Message:    strings.TrimRight(text, "\n"),
+		Icon:       notif.Icon,
+		UseMarkup:  true,
+		TimeLength: expire,
 	}
-	notifs.log.GoTry(notifs.Listen)
-	return nil
-}
 
-// Listen to eavesdropped messages to find notifications..
-//
-func (notifs *Notifs) Listen() {
-	for msg := range notifs.C {
-		switch msg.Type {
-		// case dbus.TypeSignal:
-
-		case dbus.TypeMethodCall:
-			// ensure we got a valid message
-			if msg.Headers[dbus.FieldMember].Value().(string) == "Notify" && len(msg.Body) >= 8 {
-				notifs.Add(messageToNotif(msg))
-			}
+	actions := notif.ActionList()
+	data.Buttons = "ok" // A button is needed to get the dismiss callback.
+	if len(actions) > 0 {
+		var labels []string
+		for _, act := range actions {
+			labels = append(labels, act.Label)
 		}
+		data.Buttons = "ok;cancel"
+		data.Widget = cdtype.DialogWidgetList{Values: labels}
 	}
-}
-
-// messageToNotif converts the dbus message to a notification.
-//
-func messageToNotif(message *dbus.Message) *Notif {
-	newtif := &Notif{
-		Sender:  message.Body[0].(string),
-		ID:      message.Body[1].(uint32), // always 0 ??
-		Icon:    message.Body[2].(string),
-		Title:   message.Body[3].(string),
-		Content: message.Body[4].(string),
-		// duration: message.Body[7],
+	data.Callback = func(clickedButton int, answer interface{}) {
+		id, ok := answer.(int)
+		if ok && id >= 0 && id < len(actions) && (clickedButton == cdtype.DialogButtonFirst || clickedButton == cdtype.DialogKeyEnter) {
+			app.Log().Err(app.server.Invoke(notif.ID, actions[id].Key), "invoke action", actions[id].Key)
+			return
+		}
+		app.server.Dismiss(notif.ID)
 	}
 
-	// Title too short (it's probably something we don't mind, like a notification that the volume has changed)
-	if len(newtif.Title) < 2 {
-		return nil
-	}
+	e = app.PopupDialog(data)
+	return !app.Log().Err(e, "popup")
+}
 
-	return newtif
+// hideBubble removes the dialog of a notification closed by its client. The
+// dock API can't close a dialog, so it's replaced by an empty one expiring
+// after a second.
+//
+func (app *Applet) hideBubble(id uint32) {
+	app.Log().Err(app.ShowDialog("", 1), "hide notification")
 }
@@ -19,6 +19,10 @@ type groupConfig struct {
 	NotifAltIcon   string
 	DialogDuration int
 	DialogTemplate cdtype.Template `default:"dialognotif"`
+	ServerMode     bool            // Own the notifications service instead of listening to it.
+	Popup          bool            // Show new notifications in a dialog.
+	PopupDuration  int
+	DoNotDisturb   bool `action:"3"` // Record notifications without popups.
 }
 
 //
@@ -31,6 +35,7 @@ const (
 	ActionNone = iota
 	ActionShowAll
 	ActionClear
+	ActionDoNotDisturb
 )
 
 // Actions available in the menu.
@@ -38,4 +43,6 @@ const (
 var menuUser = []int{
 	ActionShowAll,
 	ActionClear,
+	ActionNone,
+	ActionDoNotDisturb,
 }
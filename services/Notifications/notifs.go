@@ -0,0 +1,398 @@
+package Notifications
+
+import (
+	"github.com/godbus/dbus"
+
+	"github.com/sqp/godock/libs/cdtype"             // Applet types.
+	"github.com/sqp/godock/libs/files/history"      // History file.
+	"github.com/sqp/godock/libs/srvdbus/dbuscommon" // EavesDrop
+
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HistoryFile defines the name of the notifications history file.
+//
+const HistoryFile = "notifications_history.txt"
+
+// Notifications Dbus service names.
+//
+const (
+	notifPath  = "/org/freedesktop/Notifications"
+	notifIface = "org.freedesktop.Notifications"
+)
+
+// Match rules of the Notify calls, and of the replies giving the notification ID.
+//
+const (
+	matchNotify = "type='method_call',path='" + notifPath + "',member='Notify',eavesdrop='true'"
+	matchReply  = "type='method_return',sender='" + notifIface + "',eavesdrop='true'"
+)
+
+// maxPending is the maximum number of Notify calls waiting for their reply.
+// Replies lost (ignored errors...) are forgotten when reached.
+//
+const maxPending = 50
+
+//
+//-------------------------------------------------------------------[ NOTIF ]--
+
+// Notif defines a single Dbus notification.
+//
+type Notif struct {
+	Sender, Icon, Title, Content string
+
+	ID      uint32    // Notification ID given by the server. 0 until known.
+	Actions []string  // Actions as sent: list of key and label pairs.
+	Time    time.Time // Reception time.
+	Client  string    `json:"-"` // Unique bus name of the sender.
+}
+
+// NotifAction defines an action of a notification.
+//
+type NotifAction struct {
+	Notif      *Notif
+	Key, Label string
+}
+
+// Date returns the reception time formatted for display.
+//
+func (notif *Notif) Date() string {
+	if notif.Time.IsZero() {
+		return ""
+	}
+	now := time.Now()
+	if notif.Time.YearDay() == now.YearDay() && notif.Time.Year() == now.Year() {
+		return notif.Time.Format("15:04")
+	}
+	return notif.Time.Format("Jan 2 15:04")
+}
+
+// isNoise returns true for notifications not worth recording, like a volume
+// change with a title too short.
+//
+func (notif *Notif) isNoise() bool {
+	return len(notif.Title) < 2
+}
+
+// ActionList returns the actions of the notification. Empty if the client is
+// unknown, like notifications loaded from the history file.
+//
+func (notif *Notif) ActionList() (list []NotifAction) {
+	if notif.Client == "" || notif.ID == 0 {
+		return nil
+	}
+	for i := 0; i+1 < len(notif.Actions); i += 2 {
+		label := notif.Actions[i+1]
+		if label == "" {
+			label = notif.Actions[i]
+		}
+		list = append(list, NotifAction{Notif: notif, Key: notif.Actions[i], Label: label})
+	}
+	return list
+}
+
+// NotifGroup defines the notifications of a sender.
+//
+type NotifGroup struct {
+	Sender string
+	List   []*Notif // Newest first.
+}
+
+//
+//------------------------------------------------------------------[ NOTIFS ]--
+
+// Notifs handles Dbus notifications management.
+//
+type Notifs struct {
+	history.History
+	NotifConfig
+
+	C chan *dbus.Message
+
+	mu        sync.Mutex
+	messages  []*Notif
+	pending   map[string]*Notif // Notify calls waiting for the reply with the ID, by client and serial.
+	callCount func(int)
+	callNew   func(*Notif)
+	log       cdtype.Logger
+}
+
+// NotifConfig defines the notification service configuration.
+//
+type NotifConfig struct {
+	MaxSize   int
+	Blacklist []string
+}
+
+// NewNotifs creates a notifications manager, with its history saved in the
+// applet data dir.
+//
+func NewNotifs(app history.AppletLike, log cdtype.Logger) *Notifs {
+	notifs := &Notifs{
+		History: *history.New(app, HistoryFile),
+		pending: make(map[string]*Notif),
+		log:     log,
+	}
+	notifs.SetFuncs(notifs.load, notifs.save, notifs.trim)
+	log.Err(notifs.Load(), "load history")
+	return notifs
+}
+
+// List returns a copy of the list of notifications.
+//
+func (notifs *Notifs) List() []*Notif {
+	notifs.mu.Lock()
+	defer notifs.mu.Unlock()
+	list := make([]*Notif, len(notifs.messages))
+	for i, notif := range notifs.messages {
+		copied := *notif
+		list[i] = &copied
+	}
+	return list
+}
+
+// Groups returns the notifications grouped by sender. The group with the
+// newest notification is first.
+//
+func (notifs *Notifs) Groups() []*NotifGroup {
+	list := notifs.List()
+	var groups []*NotifGroup
+	bySender := make(map[string]*NotifGroup)
+	for i := len(list) - 1; i >= 0; i-- {
+		group, ok := bySender[list[i].Sender]
+		if !ok {
+			group = &NotifGroup{Sender: list[i].Sender}
+			bySender[group.Sender] = group
+			groups = append(groups, group)
+		}
+		group.List = append(group.List, list[i])
+	}
+	return groups
+}
+
+// Clear resets the list of notifications.
+//
+func (notifs *Notifs) Clear() {
+	notifs.mu.Lock()
+	notifs.messages = nil
+	notifs.mu.Unlock()
+	notifs.log.Err(notifs.Save(), "save history")
+	notifs.count()
+}
+
+// Add a new notifications to the list. A notification with a replaces ID
+// takes the place of the previous one.
+//
+func (notifs *Notifs) Add(newtif *Notif, replacesID uint32) {
+	if newtif == nil {
+		return
+	}
+
+	for _, ignore := range notifs.Blacklist {
+		if newtif.Sender == ignore {
+			return
+		}
+	}
+
+	if newtif.Time.IsZero() {
+		newtif.Time = time.Now()
+	}
+
+	notifs.mu.Lock()
+	if replacesID > 0 {
+		notifs.remove(newtif.Client, replacesID)
+		newtif.ID = replacesID
+	}
+	notifs.messages = append(notifs.messages, newtif)
+	notifs.trim()
+	notifs.mu.Unlock()
+
+	notifs.log.Err(notifs.Save(), "save history")
+	notifs.count()
+	if notifs.callNew != nil {
+		notifs.callNew(newtif)
+	}
+}
+
+// SetID sets the notification ID given by the server. Needed to match a
+// future replacement.
+//
+func (notifs *Notifs) SetID(notif *Notif, id uint32) {
+	notifs.mu.Lock()
+	notif.ID = id
+	notifs.mu.Unlock()
+	notifs.log.Err(notifs.Save(), "save history")
+}
+
+// remove removes the notification with the ID, sent by the client. Must be
+// locked.
+//
+func (notifs *Notifs) remove(client string, id uint32) {
+	for i, oldtif := range notifs.messages {
+		if oldtif.ID == id && oldtif.Client == client {
+			notifs.messages = append(notifs.messages[:i], notifs.messages[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetOnCount sets the callback for notifications count change.
+//
+func (notifs *Notifs) SetOnCount(call func(int)) {
+	notifs.callCount = call
+}
+
+// SetOnNew sets the callback for new notifications.
+//
+func (notifs *Notifs) SetOnNew(call func(*Notif)) {
+	notifs.callNew = call
+}
+
+func (notifs *Notifs) count() {
+	if notifs.callCount != nil {
+		notifs.callCount(len(notifs.List()))
+	}
+}
+
+//
+//----------------------------------------------------------------[ LISTENER ]--
+
+// Start the message eavesdropping loop and forward notifs changes to the callback.
+//
+func (notifs *Notifs) Start() error {
+	var e error
+	notifs.C, e = dbuscommon.EavesDrop(matchNotify, matchReply)
+	if e != nil {
+		return e
+	}
+	notifs.log.GoTry(notifs.Listen)
+	return nil
+}
+
+// Listen to eavesdropped messages to find notifications..
+//
+func (notifs *Notifs) Listen() {
+	for msg := range notifs.C {
+		notifs.onMessage(msg)
+	}
+}
+
+// onMessage handles an eavesdropped message: a Notify call, or the reply
+// with the notification ID.
+//
+func (notifs *Notifs) onMessage(msg *dbus.Message) {
+	switch msg.Type {
+	case dbus.TypeMethodCall:
+		// ensure we got a valid message
+		member, _ := msg.Headers[dbus.FieldMember].Value().(string)
+		if member != "Notify" || len(msg.Body) < 8 {
+			return
+		}
+		newtif, replacesID := messageToNotif(msg)
+		if newtif == nil {
+			return
+		}
+		if replacesID == 0 && msg.Flags&dbus.FlagNoReplyExpected == 0 {
+			notifs.mu.Lock()
+			if len(notifs.pending) >= maxPending {
+				notifs.pending = make(map[string]*Notif)
+			}
+			notifs.pending[pendingKey(newtif.Client, msg.Serial())] = newtif
+			notifs.mu.Unlock()
+		}
+		notifs.Add(newtif, replacesID)
+
+	case dbus.TypeMethodReply:
+		serial, _ := msg.Headers[dbus.FieldReplySerial].Value().(uint32)
+		dest, _ := msg.Headers[dbus.FieldDestination].Value().(string)
+		key := pendingKey(dest, serial)
+		notifs.mu.Lock()
+		newtif, ok := notifs.pending[key]
+		delete(notifs.pending, key)
+		notifs.mu.Unlock()
+		if !ok || len(msg.Body) == 0 {
+			return
+		}
+		if id, ok := msg.Body[0].(uint32); ok {
+			notifs.SetID(newtif, id)
+		}
+	}
+}
+
+func pendingKey(client string, serial uint32) string {
+	return client + " " + strconv.FormatUint(uint64(serial), 10)
+}
+
+// messageToNotif converts the dbus message to a notification, and returns
+// the ID of the notification it replaces, if any.
+//
+func messageToNotif(message *dbus.Message) (*Notif, uint32) {
+	newtif := &Notif{}
+	var replacesID uint32
+	var ok [5]bool
+	newtif.Sender, ok[0] = message.Body[0].(string)
+	replacesID, ok[1] = message.Body[1].(uint32)
+	newtif.Icon, ok[2] = message.Body[2].(string)
+	newtif.Title, ok[3] = message.Body[3].(string)
+	newtif.Content, ok[4] = message.Body[4].(string)
+	newtif.Actions, _ = message.Body[5].([]string)
+	newtif.Client, _ = message.Headers[dbus.FieldSender].Value().(string)
+	// duration: message.Body[7],
+
+	for _, valid := range ok {
+		if !valid {
+			return nil, 0
+		}
+	}
+
+	// Title too short (it's probably something we don't mind, like a notification that the volume has changed)
+	if newtif.isNoise() {
+		return nil, 0
+	}
+
+	return newtif, replacesID
+}
+
+//
+//-----------------------------------------------------------------[ HISTORY ]--
+
+func (notifs *Notifs) load() error {
+	content, e := ioutil.ReadFile(notifs.File)
+	if os.IsNotExist(e) {
+		return nil
+	}
+	if e != nil {
+		return e
+	}
+
+	var list []*Notif
+	e = json.Unmarshal(content, &list)
+	notifs.mu.Lock()
+	notifs.messages = list
+	notifs.trim()
+	notifs.mu.Unlock()
+	return e
+}
+
+func (notifs *Notifs) save() error {
+	notifs.mu.Lock()
+	data, e := json.MarshalIndent(notifs.messages, "", "\t")
+	notifs.mu.Unlock()
+	if e != nil {
+		return e
+	}
+	return ioutil.WriteFile(notifs.File, data, 0600)
+}
+
+// trim drops the oldest notifications over the history size. Must be locked.
+//
+func (notifs *Notifs) trim() {
+	if notifs.MaxSize > 0 && len(notifs.messages) > notifs.MaxSize {
+		notifs.messages = notifs.messages[len(notifs.messages)-notifs.MaxSize:]
+	}
+}
@@ -0,0 +1,119 @@
+package Notifications
+
+import (
+	"github.com/godbus/dbus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sqp/godock/libs/log"
+
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeApp provides the history data dir.
+type fakeApp struct{ dir string }
+
+func (app fakeApp) FileDataDir(path ...string) string {
+	return filepath.Join(append([]string{app.dir}, path...)...)
+}
+
+// notify creates an eavesdropped Notify call.
+func notify(client, sender, title string, replacesID uint32, actions ...string) *dbus.Message {
+	return &dbus.Message{
+		Type: dbus.TypeMethodCall,
+		Headers: map[dbus.HeaderField]dbus.Variant{
+			dbus.FieldMember: dbus.MakeVariant("Notify"),
+			dbus.FieldSender: dbus.MakeVariant(client),
+		},
+		Body: []interface{}{sender, replacesID, "", title, "content", actions, map[string]dbus.Variant{}, int32(-1)},
+	}
+}
+
+// reply creates an eavesdropped reply to a Notify call.
+func reply(client string, id uint32) *dbus.Message {
+	return &dbus.Message{
+		Type: dbus.TypeMethodReply,
+		Headers: map[dbus.HeaderField]dbus.Variant{
+			dbus.FieldReplySerial: dbus.MakeVariant(uint32(0)),
+			dbus.FieldDestination: dbus.MakeVariant(client),
+		},
+		Body: []interface{}{id},
+	}
+}
+
+func TestNotifs(t *testing.T) {
+	dir, e := ioutil.TempDir("", "notifications")
+	if !assert.NoError(t, e, "TempDir") {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	logger := log.NewLog(log.Logs).SetName("Notifications_test")
+	notifs := NewNotifs(fakeApp{dir}, logger)
+	notifs.MaxSize = 3
+	notifs.Blacklist = []string{"ignored"}
+	var count int
+	var popups []string
+	notifs.SetOnCount(func(nb int) { count = nb })
+	notifs.SetOnNew(func(notif *Notif) { popups = append(popups, notif.Title) })
+
+	notifs.onMessage(notify(":1.10", "mail", "first mail", 0, "open", "Open"))
+	notifs.onMessage(reply(":1.10", 7))
+	notifs.onMessage(notify(":1.11", "chat", "hello", 0))
+	notifs.onMessage(notify(":1.11", "ignored", "not recorded", 0))
+	notifs.onMessage(notify(":1.11", "chat", "x", 0)) // Title too short.
+	assert.Equal(t, 2, count, "count")
+	assert.Equal(t, []string{"first mail", "hello"}, popups, "new callback")
+
+	list := notifs.List()
+	if assert.Len(t, list, 2, "list") {
+		assert.Equal(t, uint32(7), list[0].ID, "ID from reply")
+		assert.False(t, list[0].Time.IsZero(), "time")
+	}
+
+	// Replace, only for the same client.
+	notifs.onMessage(notify(":1.10", "mail", "second mail", 7, "open", "Open"))
+	notifs.onMessage(notify(":1.12", "mail", "other client", 7))
+	list = notifs.List()
+	if assert.Len(t, list, 3, "replaced") {
+		assert.Equal(t, "hello", list[0].Title, "replaced removed")
+		assert.Equal(t, "second mail", list[1].Title, "replaced added")
+		assert.Equal(t, uint32(7), list[1].ID, "replaced ID")
+	}
+
+	groups := notifs.Groups()
+	if assert.Len(t, groups, 2, "groups") {
+		assert.Equal(t, "mail", groups[0].Sender, "newest group first")
+		assert.Len(t, groups[0].List, 2, "group by sender")
+		assert.Equal(t, "other client", groups[0].List[0].Title, "newest first")
+	}
+
+	actions := groups[0].List[1].ActionList()
+	if assert.Len(t, actions, 1, "actions") {
+		assert.Equal(t, "open", actions[0].Key, "action key")
+		assert.Equal(t, "Open", actions[0].Label, "action label")
+	}
+
+	// List returns copies.
+	list[1].Title = "changed"
+	assert.Equal(t, "second mail", notifs.List()[1].Title, "copied")
+
+	// History size and persistence.
+	notifs.onMessage(notify(":1.11", "chat", "again", 0))
+	assert.Len(t, notifs.List(), 3, "max size")
+
+	loaded := NewNotifs(fakeApp{dir}, logger)
+	list = loaded.List()
+	if assert.Len(t, list, 3, "loaded") {
+		assert.Equal(t, "again", list[2].Title, "loaded")
+		assert.Equal(t, uint32(7), list[0].ID, "loaded ID")
+		assert.Empty(t, list[0].Client, "client not saved")
+	}
+	assert.Empty(t, list[0].ActionList(), "no actions after restart")
+
+	notifs.Clear()
+	assert.Equal(t, 0, count, "cleared")
+	assert.Empty(t, NewNotifs(fakeApp{dir}, logger).List(), "cleared file")
+}
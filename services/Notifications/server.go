@@ -0,0 +1,266 @@
+package Notifications
+
+import (
+	"github.com/godbus/dbus"
+
+	"github.com/sqp/godock/libs/cdglobal"           // Global consts.
+	"github.com/sqp/godock/libs/cdtype"             // Applet types.
+	"github.com/sqp/godock/libs/srvdbus/dbuscommon" // Dbus service.
+
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Notification server informations.
+//
+const (
+	serverName   = "Cairo-Dock"
+	serverVendor = "Cairo-Dock"
+	specVersion  = "1.2"
+)
+
+// Reasons of a NotificationClosed signal.
+//
+const (
+	ClosedExpired   = 1 // The notification expired.
+	ClosedDismissed = 2 // The notification was dismissed by the user.
+	ClosedByCall    = 3 // The notification was closed by a call to CloseNotification.
+	ClosedUndefined = 4 // The notification wasn't displayed, or its display was replaced.
+)
+
+// ErrNotOpen is returned when invoking an action of a closed notification.
+//
+var ErrNotOpen = errors.New("notification closed")
+
+// serverCapabilities lists the optional features supported by the server.
+//
+var serverCapabilities = []string{"actions", "body", "body-markup"}
+
+//
+//------------------------------------------------------------------[ SERVER ]--
+
+// Server is a notification server that owns the org.freedesktop.Notifications
+// name on the session bus. Notifications are recorded in the history, and
+// displayed with the OnShow callback.
+//
+// Only one notification is displayed at a time, like dialogs of the dock: a
+// new notification replaces the displayed one, which is closed. The display
+// must call Invoke or Dismiss when closed by the user, and expire by itself
+// after the given duration. It's removed with OnHide when the client closes
+// the notification, or replaces it by one that can't be displayed.
+//
+type Server struct {
+	*dbuscommon.Server // Dbus connection.
+
+	// OnShow displays the notification. Expire is the display duration in
+	// seconds, 0 for unlimited. Returns false if it wasn't displayed.
+	OnShow func(notif *Notif, expire int) bool
+
+	// OnHide removes the display of the notification.
+	OnHide func(id uint32)
+
+	// Duration is the default display duration in seconds, when the client
+	// lets the server decide.
+	Duration int
+
+	notifs *Notifs
+	mu     sync.Mutex
+	lastID uint32
+	shown  uint32                // ID of the displayed notification, 0 if none.
+	open   map[uint32]*openNotif // Notifications not closed yet.
+}
+
+// openNotif defines a notification not closed yet.
+//
+type openNotif struct {
+	notif *Notif
+	timer *time.Timer // Expiration timer, nil if unlimited.
+}
+
+// NewServer creates a notification server recording to the notifs history.
+// It must be started with Connect.
+//
+func NewServer(notifs *Notifs, log cdtype.Logger) *Server {
+	return &Server{
+		Server: dbuscommon.NewServer(notifIface, notifPath, log),
+		notifs: notifs,
+		open:   make(map[uint32]*openNotif),
+	}
+}
+
+// Connect connects to the DBus API and starts the service. Returns false if
+// another notification server is active.
+//
+func (srv *Server) Connect() (bool, error) {
+	if srv.Server == nil {
+		return false, errors.New("no session bus")
+	}
+	return srv.Start(srv, nil)
+}
+
+// Actions returns the actions of notifications not closed yet, oldest first.
+//
+func (srv *Server) Actions() (list []NotifAction) {
+	srv.mu.Lock()
+	ids := make([]int, 0, len(srv.open))
+	for id := range srv.open {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		list = append(list, srv.open[uint32(id)].notif.ActionList()...)
+	}
+	srv.mu.Unlock()
+	return list
+}
+
+// Hidden forgets the display of the notification, replaced by another dialog.
+// The notification stays open, with its actions listed by Actions.
+//
+func (srv *Server) Hidden() {
+	srv.mu.Lock()
+	srv.shown = 0
+	srv.mu.Unlock()
+}
+
+// Invoke sends the action of the notification to its client, and closes it.
+//
+func (srv *Server) Invoke(id uint32, key string) error {
+	srv.mu.Lock()
+	_, ok := srv.open[id]
+	srv.mu.Unlock()
+	if !ok {
+		return ErrNotOpen
+	}
+	e := srv.Conn.Emit(notifPath, notifIface+".ActionInvoked", id, key)
+	srv.closed(id, ClosedDismissed)
+	return e
+}
+
+// Dismiss closes the notification on user request.
+//
+func (srv *Server) Dismiss(id uint32) {
+	srv.closed(id, ClosedDismissed)
+}
+
+// closed forgets the notification and emits the NotificationClosed signal, if
+// it was still open. Its display is removed, unless it was closed by the user
+// or expired with the display.
+//
+func (srv *Server) closed(id, reason uint32) {
+	srv.mu.Lock()
+	open, ok := srv.open[id]
+	delete(srv.open, id)
+	shown := ok && id == srv.shown
+	if shown {
+		srv.shown = 0
+	}
+	srv.mu.Unlock()
+	if !ok {
+		return
+	}
+	if open.timer != nil {
+		open.timer.Stop()
+	}
+	if shown && reason != ClosedDismissed && reason != ClosedExpired && srv.OnHide != nil {
+		srv.OnHide(id)
+	}
+	srv.Log.Err(srv.Conn.Emit(notifPath, notifIface+".NotificationClosed", id, reason), "emit NotificationClosed")
+}
+
+// expire returns the display duration in seconds for the client timeout.
+//
+func (srv *Server) expire(expireTimeout int32) int {
+	switch {
+	case expireTimeout == 0:
+		return 0
+
+	case expireTimeout > 0:
+		return int((time.Duration(expireTimeout)*time.Millisecond + time.Second - 1) / time.Second)
+	}
+	return srv.Duration
+}
+
+// track registers the displayed notification, replacing the previous one with
+// the same ID. The notification displayed before, if any, is closed.
+//
+func (srv *Server) track(notif *Notif, expire int) {
+	open := &openNotif{notif: notif}
+	if expire > 0 {
+		open.timer = time.AfterFunc(time.Duration(expire)*time.Second, func() { srv.closed(notif.ID, ClosedExpired) })
+	}
+
+	srv.mu.Lock()
+	if old := srv.open[notif.ID]; old != nil && old.timer != nil {
+		old.timer.Stop()
+	}
+	srv.open[notif.ID] = open
+	replaced := srv.shown
+	srv.shown = notif.ID
+	srv.mu.Unlock()
+
+	if replaced != 0 && replaced != notif.ID {
+		srv.closed(replaced, ClosedUndefined)
+	}
+}
+
+//
+//----------------------------------------------------------------[ DBUS API ]--
+
+// Notify displays and records a notification. Returns the notification ID.
+//
+func (srv *Server) Notify(sender dbus.Sender, appName string, replacesID uint32, appIcon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, expireTimeout int32) (uint32, *dbus.Error) {
+
+	srv.mu.Lock()
+	id := replacesID
+	if id == 0 {
+		srv.lastID++
+		id = srv.lastID
+	}
+	srv.mu.Unlock()
+
+	notif := &Notif{
+		Sender:  appName,
+		Icon:    appIcon,
+		Title:   summary,
+		Content: body,
+		ID:      id,
+		Actions: actions,
+		Time:    time.Now(),
+		Client:  string(sender),
+	}
+
+	expire := srv.expire(expireTimeout)
+	if srv.OnShow != nil && srv.OnShow(notif, expire) {
+		srv.track(notif, expire)
+	} else {
+		srv.closed(id, ClosedUndefined) // Not displayed, replacing an open one.
+	}
+	if !notif.isNoise() {
+		srv.notifs.Add(notif, replacesID)
+	}
+	return id, nil
+}
+
+// CloseNotification closes the notification.
+//
+func (srv *Server) CloseNotification(id uint32) *dbus.Error {
+	srv.closed(id, ClosedByCall)
+	return nil
+}
+
+// GetCapabilities returns the optional features supported by the server.
+//
+func (srv *Server) GetCapabilities() ([]string, *dbus.Error) {
+	return serverCapabilities, nil
+}
+
+// GetServerInformation returns the name, vendor and version of the server,
+// and the version of the specification it implements.
+//
+func (srv *Server) GetServerInformation() (string, string, string, string, *dbus.Error) {
+	return serverName, serverVendor, cdglobal.AppVersion, specVersion, nil
+}
@@ -0,0 +1,183 @@
+package Notifications
+
+import (
+	"github.com/godbus/dbus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sqp/godock/libs/log"
+
+	"bufio"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startBus starts a private session bus, used as default session bus by the
+// test process. Skips the test if dbus-daemon isn't available.
+//
+func startBus(t *testing.T) (string, func()) {
+	if _, e := exec.LookPath("dbus-daemon"); e != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	dir, _ := ioutil.TempDir("", "notifications-bus")
+	conf := filepath.Join(dir, "bus.conf")
+	ioutil.WriteFile(conf, []byte(`<busconfig>
+  <type>session</type>
+  <listen>unix:path=`+filepath.Join(dir, "bus")+`</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`), 0600)
+
+	cmd := exec.Command("dbus-daemon", "--config-file="+conf, "--nofork", "--print-address=1")
+	out, _ := cmd.StdoutPipe()
+	if e := cmd.Start(); e != nil {
+		os.RemoveAll(dir)
+		t.Skip("dbus-daemon start:", e)
+	}
+	address, _ := bufio.NewReader(out).ReadString('\n')
+	address = strings.TrimSpace(address)
+	os.Setenv("DBUS_SESSION_BUS_ADDRESS", address)
+	return address, func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestServer(t *testing.T) {
+	address, stop := startBus(t)
+	defer stop()
+
+	dir, _ := ioutil.TempDir("", "notifications")
+	defer os.RemoveAll(dir)
+	logger := log.NewLog(log.Logs).SetName("Notifications_test")
+	notifs := NewNotifs(fakeApp{dir}, logger)
+
+	shown := make(chan int, 10)
+	hidden := make(chan uint32, 10)
+	var display int32 = 1
+	srv := NewServer(notifs, logger)
+	srv.Duration = 5
+	srv.OnShow = func(notif *Notif, expire int) bool {
+		if atomic.LoadInt32(&display) == 0 {
+			return false
+		}
+		shown <- expire
+		return true
+	}
+	srv.OnHide = func(id uint32) { hidden <- id }
+	ok, e := srv.Connect()
+	if !assert.NoError(t, e, "Connect") || !assert.True(t, ok, "Connect") {
+		return
+	}
+
+	// Client.
+	conn, e := dbus.Dial(address)
+	if !assert.NoError(t, e, "Dial") {
+		return
+	}
+	defer conn.Close()
+	assert.NoError(t, conn.Auth(nil), "Auth")
+	assert.NoError(t, conn.Hello(), "Hello")
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+	conn.BusObject().Call("org.freedesktop.DBus.AddMatch", 0, "type='signal',interface='"+notifIface+"'")
+	obj := conn.Object(notifIface, notifPath)
+
+	var caps []string
+	assert.NoError(t, obj.Call(notifIface+".GetCapabilities", 0).Store(&caps), "GetCapabilities")
+	assert.Contains(t, caps, "actions", "GetCapabilities")
+
+	var name, vendor, version, spec string
+	e = obj.Call(notifIface+".GetServerInformation", 0).Store(&name, &vendor, &version, &spec)
+	assert.NoError(t, e, "GetServerInformation")
+	assert.Equal(t, specVersion, spec, "GetServerInformation")
+
+	notify := func(replacesID uint32, title string, timeout int32, actions ...string) uint32 {
+		var id uint32
+		e := obj.Call(notifIface+".Notify", 0, "mail", replacesID, "", title, "body", actions, map[string]dbus.Variant{}, timeout).Store(&id)
+		assert.NoError(t, e, "Notify")
+		return id
+	}
+	waitSignal := func(member string) *dbus.Signal {
+		for {
+			select {
+			case sig := <-signals:
+				if sig.Name == notifIface+"."+member {
+					return sig
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("signal not received:", member)
+				return nil
+			}
+		}
+	}
+
+	id := notify(0, "new mail", -1, "open", "Open")
+	assert.Equal(t, uint32(1), id, "first ID")
+	assert.Equal(t, 5, <-shown, "default duration")
+	assert.Len(t, srv.Actions(), 1, "open actions")
+
+	assert.Equal(t, id, notify(id, "new mails", 0, "open", "Open"), "replaced ID")
+	assert.Equal(t, 0, <-shown, "no expiration")
+	list := notifs.List()
+	if assert.Len(t, list, 1, "replaced") {
+		assert.Equal(t, "new mails", list[0].Title, "replaced")
+	}
+
+	// Action invoked from the dialog.
+	assert.NoError(t, srv.Invoke(id, "open"), "Invoke")
+	if sig := waitSignal("ActionInvoked"); sig != nil {
+		assert.Equal(t, []interface{}{id, "open"}, sig.Body, "ActionInvoked")
+	}
+	if sig := waitSignal("NotificationClosed"); sig != nil {
+		assert.Equal(t, []interface{}{id, uint32(ClosedDismissed)}, sig.Body, "dismissed")
+	}
+	assert.Equal(t, ErrNotOpen, srv.Invoke(id, "open"), "Invoke closed")
+	assert.Empty(t, srv.Actions(), "closed actions")
+
+	id = notify(0, "other", 0)
+	<-shown
+	assert.NoError(t, obj.Call(notifIface+".CloseNotification", 0, id).Err, "CloseNotification")
+	if sig := waitSignal("NotificationClosed"); sig != nil {
+		assert.Equal(t, []interface{}{id, uint32(ClosedByCall)}, sig.Body, "closed by call")
+	}
+	assert.Equal(t, id, <-hidden, "display removed")
+
+	// Displayed notification replaced by a new one.
+	first := notify(0, "first", 0, "open", "Open")
+	<-shown
+	id = notify(0, "persistent", 0)
+	assert.Equal(t, 0, <-shown, "no expiration")
+	if sig := waitSignal("NotificationClosed"); sig != nil {
+		assert.Equal(t, []interface{}{first, uint32(ClosedUndefined)}, sig.Body, "display replaced")
+	}
+	assert.Empty(t, srv.Actions(), "replaced actions")
+
+	// Not displayed, in do not disturb mode.
+	atomic.StoreInt32(&display, 0)
+	notify(0, "hidden", 0, "open", "Open")
+	assert.Empty(t, srv.Actions(), "not displayed")
+	atomic.StoreInt32(&display, 1)
+
+	// Dialog closed by the user, without actions nor expiration.
+	srv.Dismiss(id)
+	if sig := waitSignal("NotificationClosed"); sig != nil {
+		assert.Equal(t, []interface{}{id, uint32(ClosedDismissed)}, sig.Body, "dismissed without actions")
+	}
+
+	id = notify(0, "expires", 1)
+	assert.Equal(t, 1, <-shown, "duration rounded up")
+	if sig := waitSignal("NotificationClosed"); sig != nil {
+		assert.Equal(t, []interface{}{id, uint32(ClosedExpired)}, sig.Body, "expired")
+	}
+}
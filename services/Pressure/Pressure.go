@@ -0,0 +1,195 @@
+// Package Pressure is a system pressure stall and load monitoring applet for Cairo-Dock.
+//
+// Pressure stall information (PSI) shows the share of time tasks were waiting
+// for the CPU, memory or IO. It requires a kernel 4.20 or above.
+package Pressure
+
+import (
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"runtime"
+)
+
+//
+//------------------------------------------------------------------[ APPLET ]--
+
+func init() { cdtype.Applets.Register("Pressure", NewApplet) }
+
+// Applet defines a dock applet.
+//
+type Applet struct {
+	cdtype.AppBase // Applet base and dock connection.
+
+	conf    *appletConf
+	service *Monitor
+}
+
+// NewApplet creates a new applet instance.
+//
+func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
+	app := &Applet{AppBase: base, service: NewMonitor()}
+	app.SetConfig(&app.conf)
+
+	// Events.
+	events.OnClick = app.Command().Callback(cmdLeft)
+	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
+			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
+		}
+		if app.conf.MiddleAction > 0 && app.conf.MiddleCommand != "" {
+			menu.AddEntry("Action middle click", "system-run", app.Command().Callback(cmdMiddle))
+		}
+	}
+
+	app.Poller().Add(app.service.Check)
+
+	app.service.App = app
+	app.service.Log = app.Log()
+	app.service.Alert = sysinfo.NewAlert(app)
+	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
+		cdtype.InfoNone:    {},
+		cdtype.InfoOnIcon:  {Sep: "\n", ShowPre: false},
+		cdtype.InfoOnLabel: {Sep: "\n", ShowPre: true},
+	}
+
+	return app
+}
+
+// Init load user configuration if needed and initialise applet.
+//
+func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
+	// Settings for poller and renderer.
+	app.service.Settings(app.conf.DisplayText, app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName)
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
+	app.service.Average = app.conf.Average
+	app.service.ShowFull = app.conf.ShowFull
+	app.service.ShowLoad = app.conf.ShowLoad
+	var resources []string
+	if app.conf.ShowCPU {
+		resources = append(resources, sysinfo.PressureCPU)
+	}
+	if app.conf.ShowMemory {
+		resources = append(resources, sysinfo.PressureMemory)
+	}
+	if app.conf.ShowIO {
+		resources = append(resources, sysinfo.PressureIO)
+	}
+	app.service.SetResources(resources)
+	app.service.SetSize(app.service.Count())
+
+	// Defaults.
+	def.PollerInterval = app.conf.UpdateDelay.Value()
+	def.Commands[cmdLeft] = cdtype.NewCommandStd(app.conf.LeftAction, app.conf.LeftCommand, app.conf.LeftClass)
+	def.Commands[cmdMiddle] = cdtype.NewCommandStd(app.conf.MiddleAction, app.conf.MiddleCommand)
+}
+
+//
+//-----------------------------------------------------------------[ MONITOR ]--
+
+// resourceNames are the displayed names of pressure resources.
+//
+var resourceNames = map[string]string{
+	sysinfo.PressureCPU:    "CPU",
+	sysinfo.PressureMemory: "Mem",
+	sysinfo.PressureIO:     "IO",
+}
+
+// Monitor monitors pressure stall information and load average, and renders
+// them on the icon.
+//
+// Each resource provides a pair of values: some and full, like in and out
+// for IO activity. The load average is displayed in percent of the CPU count.
+//
+type Monitor struct {
+	sysinfo.RenderPercent
+
+	Resources []string // Resources to display (sysinfo.PressureCPU...).
+	Average   int      // Averages window (average10 or average60).
+	ShowFull  bool     // Display the full stall value after the some value.
+	ShowLoad  bool     // Display the 1 minute load average.
+
+	nbCPU  int
+	warned bool // Missing kernel support was logged.
+}
+
+// NewMonitor creates a new pressure monitoring service.
+//
+func NewMonitor() *Monitor {
+	return &Monitor{nbCPU: runtime.NumCPU()}
+}
+
+// SetResources sets the pressure resources to display. Without kernel support,
+// the load average is displayed instead, and the problem is logged only once.
+//
+func (mon *Monitor) SetResources(resources []string) {
+	mon.Resources = resources
+	if len(resources) == 0 || sysinfo.HasPressure() {
+		return
+	}
+	if !mon.warned {
+		mon.warned = true
+		mon.Log.NewErr(sysinfo.ErrNoPressure.Error(), "pressure: using load average")
+	}
+	mon.Resources = nil
+	mon.ShowLoad = true
+}
+
+// Count returns the number of values to render with current settings.
+//
+func (mon *Monitor) Count() int {
+	count := len(mon.Resources)
+	if mon.ShowFull {
+		count *= 2
+	}
+	if mon.ShowLoad {
+		count++
+	}
+	return count
+}
+
+// Check displays current pressure stall averages and load.
+//
+func (mon *Monitor) Check() {
+	mon.Clear()
+
+	for _, res := range mon.Resources {
+		name := resourceName(res)
+		psi, e := sysinfo.GetPressure(res)
+		if mon.Log.Err(e, "get pressure", res) {
+			psi = &sysinfo.Pressure{} // Keep values in place on the renderer.
+		}
+		mon.Append(name+" some", mon.average(psi.Some))
+		if mon.ShowFull {
+			mon.Append(name+" full", mon.average(psi.Full))
+		}
+	}
+
+	if mon.ShowLoad {
+		load, _, _, e := sysinfo.GetLoadAverage()
+		mon.Log.Err(e, "get load average")
+		mon.Append("Load", load/float64(mon.nbCPU))
+	}
+
+	mon.Display()
+}
+
+// average returns the configured stall average, in the 0..1 range.
+//
+func (mon *Monitor) average(stall sysinfo.PressureStall) float64 {
+	if mon.Average == average60 {
+		return stall.Avg60 / 100
+	}
+	return stall.Avg10 / 100
+}
+
+// resourceName returns the displayed name of a pressure resource.
+//
+func resourceName(res string) string {
+	if name, ok := resourceNames[res]; ok {
+		return name
+	}
+	return res
+}
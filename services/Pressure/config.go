@@ -0,0 +1,52 @@
+package Pressure
+
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+)
+
+// Commands references.
+const (
+	cmdLeft = iota
+	cmdMiddle
+)
+
+// Pressure averages windows.
+const (
+	average10 = iota
+	average60
+)
+
+//------------------------------------------------------------------[ CONFIG ]--
+
+type appletConf struct {
+	cdtype.ConfGroupIconBoth `group:"Icon"`
+	groupConfiguration       `group:"Configuration"`
+	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
+}
+
+type groupConfiguration struct {
+	DisplayText   cdtype.InfoPosition
+	DisplayValues int
+
+	GaugeName string
+	GraphType cdtype.RendererGraphType
+
+	UpdateDelay cdtype.Duration `default:"3"`
+	RecordSize  int
+	Average     int
+	ShowCPU     bool
+	ShowMemory  bool
+	ShowIO      bool
+	ShowFull    bool
+	ShowLoad    bool
+}
+
+type groupActions struct {
+	LeftAction    int
+	LeftCommand   string
+	LeftClass     string
+	MiddleAction  int
+	MiddleCommand string
+}
@@ -0,0 +1,213 @@
+// Package Sensors is a temperature and fan monitoring applet for Cairo-Dock.
+//
+// Sensors are found in the hwmon and thermal sysfs classes.
+package Sensors
+
+import (
+	"github.com/sqp/godock/libs/cdtype" // Applet types.
+	"github.com/sqp/godock/libs/sysinfo"
+
+	"strings"
+)
+
+//
+//------------------------------------------------------------------[ APPLET ]--
+
+func init() { cdtype.Applets.Register("Sensors", NewApplet) }
+
+// Applet defines a dock applet.
+//
+type Applet struct {
+	cdtype.AppBase // Applet base and dock connection.
+
+	conf    *appletConf
+	service *Monitor
+}
+
+// NewApplet creates a new applet instance.
+//
+func NewApplet(base cdtype.AppBase, events *cdtype.Events) cdtype.AppInstance {
+	app := &Applet{AppBase: base, service: &Monitor{}}
+	app.SetConfig(&app.conf)
+
+	// Events.
+	events.OnClick = app.Command().Callback(cmdLeft)
+	events.OnMiddleClick = app.Command().Callback(cmdMiddle)
+	events.OnBuildMenu = func(menu cdtype.Menuer) {
+		menu.AddEntry("Available sensors", "dialog-information", app.showSensors)
+		if app.conf.LeftAction > 0 && app.conf.LeftCommand != "" {
+			menu.AddEntry("Action left click", "system-run", app.Command().Callback(cmdLeft))
+		}
+		if app.conf.MiddleAction > 0 && app.conf.MiddleCommand != "" {
+			menu.AddEntry("Action middle click", "system-run", app.Command().Callback(cmdMiddle))
+		}
+	}
+
+	app.Poller().Add(app.service.Check)
+
+	app.service.App = app
+	app.service.Log = app.Log()
+	app.service.Alert = sysinfo.NewAlert(app)
+	app.service.Texts = map[cdtype.InfoPosition]sysinfo.RenderOne{
+		cdtype.InfoNone:    {},
+		cdtype.InfoOnIcon:  {Sep: "\n", ShowPre: false},
+		cdtype.InfoOnLabel: {Sep: "\n", ShowPre: true},
+	}
+
+	return app
+}
+
+// Init load user configuration if needed and initialise applet.
+//
+func (app *Applet) Init(def *cdtype.Defaults, confLoaded bool) {
+	// Settings for poller and renderer.
+	app.service.Settings(app.conf.DisplayText, app.conf.DisplayValues, app.conf.GraphType, app.conf.GaugeName)
+	app.service.Alert.SetConfig(app.conf.AlertConfig)
+	app.service.Record = sysinfo.UpdateRecorder(app.service.Record, app, app.Name(), app.conf.RecordSize)
+	app.service.Ranges = map[sysinfo.SensorType][2]float64{
+		sysinfo.SensorTemp: {app.conf.TempMin, app.conf.TempMax},
+		sysinfo.SensorFan:  {app.conf.FanMin, app.conf.FanMax},
+	}
+	app.service.SetSensors(sysinfo.Sensors(), app.conf.Sensors)
+	if app.conf.AlertCritical {
+		app.service.Alert.SetThresholds(app.service.CritThresholds())
+	} else {
+		app.service.Alert.SetThresholds(nil)
+	}
+	app.service.Alert.SetIgnored(app.service.Names(sysinfo.SensorFan)...)
+	app.service.SetSize(len(app.service.list))
+
+	// Defaults.
+	def.PollerInterval = app.conf.UpdateDelay.Value()
+	def.Commands[cmdLeft] = cdtype.NewCommandStd(app.conf.LeftAction, app.conf.LeftCommand, app.conf.LeftClass)
+	def.Commands[cmdMiddle] = cdtype.NewCommandStd(app.conf.MiddleAction, app.conf.MiddleCommand)
+}
+
+// showSensors shows a dialog with the list of sensors found, to help the user
+// fill the sensors list in the config.
+//
+func (app *Applet) showSensors() {
+	var lines []string
+	for _, sensor := range sysinfo.Sensors() {
+		line := sensor.Name
+		if value, e := sensor.Read(); e == nil {
+			line += ": " + sensor.Format(value)
+		}
+		if sensor.Crit > 0 {
+			line += " (" + app.Translate("critical") + " " + sensor.Format(sensor.Crit) + ")"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, app.Translate("No sensor found."))
+	}
+	app.Log().Err(app.ShowDialog(strings.Join(lines, "\n"), 10), "show sensors")
+}
+
+//
+//-----------------------------------------------------------------[ MONITOR ]--
+
+// Monitor monitors sensors values and renders them on the icon.
+//
+// Values are rendered between the min and max of their sensor type range.
+// The text, alert and recorder use values in the sensor unit. The alert only
+// checks temperatures, as its threshold is in °C.
+//
+type Monitor struct {
+	sysinfo.RenderPercent
+
+	Ranges map[sysinfo.SensorType][2]float64 // Min and max rendered values, by sensor type.
+
+	list []*sysinfo.Sensor
+}
+
+// SetSensors sets the list of sensors to display, found by name in the given
+// list. If no name is provided, all temperature sensors are displayed.
+//
+func (mon *Monitor) SetSensors(found []*sysinfo.Sensor, names []string) {
+	mon.list = nil
+	if len(names) == 0 {
+		for _, sensor := range found {
+			if sensor.Type == sysinfo.SensorTemp {
+				mon.list = append(mon.list, sensor)
+			}
+		}
+		return
+	}
+
+	for _, name := range names {
+		sensor := findSensor(found, name)
+		if sensor == nil {
+			mon.Log.NewErr("sensor not found", name)
+			continue
+		}
+		mon.list = append(mon.list, sensor)
+	}
+}
+
+// Names returns the names of displayed sensors of the given type.
+//
+func (mon *Monitor) Names(typ sysinfo.SensorType) (names []string) {
+	for _, sensor := range mon.list {
+		if sensor.Type == typ {
+			names = append(names, sensor.Name)
+		}
+	}
+	return names
+}
+
+// CritThresholds returns the critical trip values of displayed sensors, to be
+// used as alert thresholds.
+//
+func (mon *Monitor) CritThresholds() map[string]float64 {
+	thresholds := make(map[string]float64)
+	for _, sensor := range mon.list {
+		if sensor.Crit > 0 {
+			thresholds[sensor.Name] = sensor.Crit
+		}
+	}
+	return thresholds
+}
+
+// Check displays current sensors values.
+//
+func (mon *Monitor) Check() {
+	mon.Clear()
+	for _, sensor := range mon.list {
+		value, e := sensor.Read()
+		if mon.Log.Err(e, "read sensor", sensor.Name) {
+			mon.AppendFailed(sensor.Name, "N/A")
+			continue
+		}
+		mon.AppendRaw(sensor.Name, mon.scale(sensor.Type, value), value, sensor.Format(value))
+	}
+	mon.Display()
+}
+
+// scale returns the value position in its type range, in the 0..1 range.
+//
+func (mon *Monitor) scale(typ sysinfo.SensorType, value float64) float64 {
+	rng := mon.Ranges[typ]
+	if rng[1] <= rng[0] {
+		return 0
+	}
+	switch ratio := (value - rng[0]) / (rng[1] - rng[0]); {
+	case ratio < 0:
+		return 0
+	case ratio > 1:
+		return 1
+	default:
+		return ratio
+	}
+}
+
+// findSensor returns the sensor matching the name, or nil if not found.
+//
+func findSensor(list []*sysinfo.Sensor, name string) *sysinfo.Sensor {
+	for _, sensor := range list {
+		if sensor.Name == name {
+			return sensor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package Sensors
+
+import (
+	"github.com/sqp/godock/libs/cdtype"
+	"github.com/sqp/godock/libs/sysinfo"
+)
+
+// Commands references.
+const (
+	cmdLeft = iota
+	cmdMiddle
+)
+
+//------------------------------------------------------------------[ CONFIG ]--
+
+type appletConf struct {
+	cdtype.ConfGroupIconBoth `group:"Icon"`
+	groupConfiguration       `group:"Configuration"`
+	groupActions             `group:"Actions"`
+	sysinfo.AlertConfig      `group:"Alert"`
+}
+
+type groupConfiguration struct {
+	DisplayText   cdtype.InfoPosition
+	DisplayValues int
+
+	GaugeName string
+	GraphType cdtype.RendererGraphType
+
+	UpdateDelay cdtype.Duration `default:"3"`
+	RecordSize  int
+	Sensors     []string
+	TempMin     float64
+	TempMax     float64
+	FanMin      float64
+	FanMax      float64
+
+	AlertCritical bool
+}
+
+type groupActions struct {
+	LeftAction    int
+	LeftCommand   string
+	LeftClass     string
+	MiddleAction  int
+	MiddleCommand string
+}
@@ -0,0 +1,5 @@
+// +build all Battery
+
+package allapps
+
+import _ "github.com/sqp/godock/services/Battery"
@@ -0,0 +1,5 @@
+// +build all Pressure
+
+package allapps
+
+import _ "github.com/sqp/godock/services/Pressure"
@@ -0,0 +1,5 @@
+// +build all Sensors
+
+package allapps
+
+import _ "github.com/sqp/godock/services/Sensors"
@@ -69,8 +69,8 @@ func KeysReport(log cdtype.Logger) cftype.ListKey {
 	title := tran.Slate("Status")
 
 	// Links.
-	urlCharts := fmt.Sprintf("http://%s/%s", websrv.Service.URL(), websrv.PathCharts)
-	urlPprof := fmt.Sprintf("http://%s/%s", websrv.Service.URL(), websrv.PathPprof)
+	urlCharts := fmt.Sprintf("%s://%s/%s", websrv.Service.Scheme(), websrv.Service.URL(), websrv.PathCharts)
+	urlPprof := fmt.Sprintf("%s://%s/%s", websrv.Service.Scheme(), websrv.Service.URL(), websrv.PathPprof)
 
 	// Switch widgets.
 	switchDebug := newkey.SwitchText(log, log.GetDebug, func(v bool) { log.SetDebug(v) }, "", "")